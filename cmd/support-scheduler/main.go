@@ -61,6 +61,9 @@ func main() {
 	// Start the ticker
 	scheduler.StartTicker()
 
+	// Pick up the schedule files
+	scheduler.StartScheduleDirWatcher()
+
 	// Time it took to start service
 	scheduler.LoggingClient.Info("Service started in: "+time.Since(start).String(), "")
 	scheduler.LoggingClient.Info("Listening on port: "+strconv.Itoa(scheduler.Configuration.Service.Port), "")
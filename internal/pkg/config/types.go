@@ -110,7 +110,7 @@ type DatabaseInfo struct {
 type ScheduleInfo struct {
 	// Name of the schedule must be unique?
 	Name string
-	// Start time in ISO 8601 format YYYYMMDD'T'HHmmss
+	// Start time in ISO 8601 format YYYYMMDD'T'HHmmss, the single fire time when there is no Frequency, Cron or RunOnce
 	Start string
 	// End time in ISO 8601 format YYYYMMDD'T'HHmmss
 	End string
@@ -120,6 +120,34 @@ type ScheduleInfo struct {
 	Cron string
 	// Boolean indicating that this schedules runs one time - at the time indicated by the start
 	RunOnce bool
+	// IANA time zone name used to interpret Start, End and Cron, defaults to UTC
+	Timezone string
+	// Window in milliseconds of the random delay added to each fire time
+	JitterMs int
+	// What to do with the fires missed while the scheduler was down : skip, fire-once or catch-up (default)
+	MissedFirePolicy string
+	// Fire on the multiples of the Frequency since the epoch in the Timezone, e.g. on the quarter hours, instead of since Start
+	AlignToInterval bool
+	// Groups the Schedule can be triggered with
+	Tags []string
+	// Bound in milliseconds on the time an execution of all the Events takes, the Events left past it are skipped
+	MaxExecutionMs int
+	// Number of fires after which the Schedule is complete, 0 is unbounded
+	MaxIterations int
+	// How the interval changes after each fire : constant (default) or multiplicative
+	IntervalPolicy string
+	// Factor the interval is multiplied by after each fire under the multiplicative IntervalPolicy
+	IntervalFactor float64
+	// ISO 8601 duration the interval stops at, the largest when it grows and the smallest when it shrinks
+	IntervalLimit string
+	// Go back to the Frequency once an execution succeeds with a response body
+	ResetInterval bool
+	// Days of the week the Schedule fires on, e.g. mon-fri or sat,sun, empty is every day
+	WindowDays string
+	// HH:MM time of day, in the Timezone, the fires are allowed from
+	WindowStart string
+	// HH:MM time of day the fires are allowed up to, before the WindowStart when the window wraps midnight
+	WindowEnd string
 }
 
 //TODO: We should be pulling the Service Info for Addressable from core-metadata
@@ -143,6 +171,28 @@ type ScheduleEventInfo struct {
 	Path string
 	// Associated Schedule for the Event
 	Schedule string
+	// Position of the Event within its Schedule, 0 runs after the ordered Events
+	Order int
+	// Regular expression the response body must match, empty accepts any body
+	ExpectResponse string
+	// Parameters appended to the query string of the Event request
+	QueryParams map[string]string
+	// Headers of the Event request, a Content-Type here overrides the DefaultContentType of the service
+	Headers map[string]string
+	// Gzip the body of the Event request
+	CompressBody bool
+	// File path within the BodySourceDir or http(s) url the body of the Event request is read from, replaces the Parameters
+	BodySource string
+	// Return the redirect responses of the Event request instead of following them
+	NoRedirects bool
+	// Redirects followed before the Event request fails, 0 follows up to 10
+	MaxRedirects int
+	// Status codes and ranges of the Event response counted as a success, empty uses the SuccessStatusCodes of the service
+	SuccessStatus string
+	// Http or https url the outcome of a successful Event execution is posted to
+	OnSuccessURL string
+	// Http or https url the outcome of a failed Event execution is posted to
+	OnFailureURL string
 	// Source of the Scheduler *not sure we need this*
 	Scheduler string
 }
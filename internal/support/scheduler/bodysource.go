@@ -0,0 +1,159 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DefaultBodySourceTTLMs is how long a body read from its source is reused when BodySourceTTLMs is not set
+const DefaultBodySourceTTLMs = 60000
+
+type cachedBody struct {
+	body     string
+	loadedAt time.Time
+}
+
+var (
+	bodySourceMutex sync.Mutex
+	bodySources     = make(map[string]cachedBody) // map : body source -> body read from it
+)
+
+func bodySourceTtl() time.Duration {
+	if Configuration == nil || Configuration.BodySourceTTLMs <= 0 {
+		return DefaultBodySourceTTLMs * time.Millisecond
+	}
+	return time.Duration(Configuration.BodySourceTTLMs) * time.Millisecond
+}
+
+// The body of an event with a BodySource replaces its parameters. The source is a file of the BodySourceDir, given
+// as a path or a file:// url, or an http or https url fetched with a GET.
+func resolveBodySource(ctx context.Context, scheduleEvent models.ScheduleEvent) (models.ScheduleEvent, error) {
+	source := strings.TrimSpace(scheduleEvent.BodySource)
+	if source == "" {
+		return scheduleEvent, nil
+	}
+	body, err := loadBodySource(ctx, source)
+	if err != nil {
+		return scheduleEvent, err
+	}
+	scheduleEvent.Parameters = body
+	return scheduleEvent, nil
+}
+
+// the bodies are cached for the BodySourceTTLMs so the source is not read on every fire, a failed read is not cached
+func loadBodySource(ctx context.Context, source string) (string, error) {
+	now := clock.Now()
+	bodySourceMutex.Lock()
+	cached, exists := bodySources[source]
+	bodySourceMutex.Unlock()
+	if exists && now.Sub(cached.loadedAt) < bodySourceTtl() {
+		return cached.body, nil
+	}
+
+	body, err := readBodySource(ctx, source)
+	if err != nil {
+		return "", err
+	}
+	bodySourceMutex.Lock()
+	bodySources[source] = cachedBody{body: body, loadedAt: now}
+	bodySourceMutex.Unlock()
+	return body, nil
+}
+
+func readBodySource(ctx context.Context, source string) (string, error) {
+	if !isUrlBodySource(source) {
+		path, err := bodySourcePath(source)
+		if err != nil {
+			return "", err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("read the body source %s occurs error : %s", source, err.Error())
+		}
+		return string(data), nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, source, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid body source %s : %s", source, err.Error())
+	}
+	body, statusCode, err := sendRequestAndGetResponse(getHTTPClient(), req.WithContext(ctx))
+	if err != nil {
+		return "", err
+	}
+	if statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices {
+		return "", fmt.Errorf("the body source %s answered with status code %d", source, statusCode)
+	}
+	return string(body), nil
+}
+
+func isUrlBodySource(source string) bool {
+	lower := strings.ToLower(source)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// Resolve a file source within the BodySourceDir, a relative path is relative to it. The files are only read from
+// that directory so an event can not send any file the scheduler can read, none are read when it is not configured.
+func bodySourcePath(source string) (string, error) {
+	if Configuration == nil || Configuration.BodySourceDir == "" {
+		return "", fmt.Errorf("the body source %s is a file while no BodySourceDir is configured", source)
+	}
+	dir, err := filepath.Abs(Configuration.BodySourceDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid BodySourceDir %s : %s", Configuration.BodySourceDir, err.Error())
+	}
+
+	path := filepath.FromSlash(strings.TrimPrefix(source, "file://"))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	path = filepath.Clean(path)
+	if !withinDir(dir, path) {
+		return "", fmt.Errorf("the body source %s is outside of the BodySourceDir %s", source, Configuration.BodySourceDir)
+	}
+
+	//a link within the directory may not lead out of it either
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		resolvedDir, err := filepath.EvalSymlinks(dir)
+		if err != nil || !withinDir(resolvedDir, resolved) {
+			return "", fmt.Errorf("the body source %s is outside of the BodySourceDir %s", source, Configuration.BodySourceDir)
+		}
+	}
+	return path, nil
+}
+
+func withinDir(dir string, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// a file source must be within the BodySourceDir, the check is repeated when the file is read
+func validateBodySource(scheduleEvent models.ScheduleEvent) error {
+	source := strings.TrimSpace(scheduleEvent.BodySource)
+	if source == "" || isUrlBodySource(source) {
+		return nil
+	}
+	if _, err := bodySourcePath(source); err != nil {
+		return fmt.Errorf("the schedule event %q has an invalid body source : %s", scheduleEvent.Name, err.Error())
+	}
+	return nil
+}
+
+func clearBodySources() {
+	bodySourceMutex.Lock()
+	defer bodySourceMutex.Unlock()
+	bodySources = make(map[string]cachedBody)
+}
@@ -0,0 +1,199 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func addBodySourceTestEvent(t *testing.T, schedule models.Schedule, source string) {
+	scheduleEvent := newTestScheduleEvent(schedule, TestScheduleEventName, http.MethodPost, "/api/v1/event", "")
+	scheduleEvent.BodySource = source
+	mustAddScheduleEvent(t, scheduleEvent)
+}
+
+// configure a temporary BodySourceDir, the caller removes it and resets the configuration
+func useBodySourceDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "scheduler")
+	if err != nil {
+		t.Fatalf("unexpected error creating the body dir : %s", err.Error())
+	}
+	Configuration.BodySourceDir = dir
+	return dir
+}
+
+func TestFileBodySource(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+	Configuration.BodySourceTTLMs = 60000
+	defer func() { Configuration.BodySourceTTLMs = 0 }()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	dir := useBodySourceDir(t)
+	defer os.RemoveAll(dir)
+	defer func() { Configuration.BodySourceDir = "" }()
+	bodyFile := filepath.Join(dir, "body.json")
+	if err := ioutil.WriteFile(bodyFile, []byte(`{"readings":1}`), 0644); err != nil {
+		t.Fatalf("unexpected error writing the body : %s", err.Error())
+	}
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addBodySourceTestEvent(t, schedule, "body.json")
+
+	executeSchedule(schedule.Id.Hex())
+	if err := ioutil.WriteFile(bodyFile, []byte(`{"readings":2}`), 0644); err != nil {
+		t.Fatalf("unexpected error writing the body : %s", err.Error())
+	}
+	//the cached body is sent until the TTL has passed
+	fake.Advance(30 * time.Second)
+	executeSchedule(schedule.Id.Hex())
+	fake.Advance(31 * time.Second)
+	executeSchedule(schedule.Id.Hex())
+
+	expected := []string{`{"readings":1}`, `{"readings":1}`, `{"readings":2}`}
+	if len(client.bodies) != len(expected) {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.bodies), len(expected))
+	}
+	for i, body := range client.bodies {
+		if body != expected[i] {
+			t.Errorf(TestUnexpectedMsgFormatStr, body, expected[i])
+		}
+	}
+}
+
+func TestUrlBodySource(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{response: `{"origin":"core-data"}`}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addBodySourceTestEvent(t, schedule, "http://config-seed:48010/api/v1/payload")
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	if len(client.requests) != 2 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 2)
+	}
+	if method := client.requests[0].Method; method != http.MethodGet {
+		t.Errorf(TestUnexpectedMsgFormatStr, method, http.MethodGet)
+	}
+	if body := client.bodies[1]; body != `{"origin":"core-data"}` {
+		t.Errorf(TestUnexpectedMsgFormatStr, body, `{"origin":"core-data"}`)
+	}
+}
+
+func TestMissingBodySourceFailsTheEvent(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	dir := useBodySourceDir(t)
+	defer os.RemoveAll(dir)
+	defer func() { Configuration.BodySourceDir = "" }()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addBodySourceTestEvent(t, schedule, "file://"+filepath.Join(dir, "missing.json"))
+	if err := executeSchedule(schedule.Id.Hex()); err == nil {
+		t.Error("expected the missing body source to fail the execution")
+	}
+	if len(client.requests) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 0)
+	}
+}
+
+func TestFileBodySourcesStayWithinTheBodySourceDir(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	parent, err := ioutil.TempDir("", "scheduler")
+	if err != nil {
+		t.Fatalf("unexpected error creating the body dir : %s", err.Error())
+	}
+	defer os.RemoveAll(parent)
+	dir := filepath.Join(parent, "bodies")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("unexpected error creating the body dir : %s", err.Error())
+	}
+	secret := filepath.Join(parent, "secret.json")
+	if err := ioutil.WriteFile(secret, []byte(`{"secret":true}`), 0644); err != nil {
+		t.Fatalf("unexpected error writing the secret : %s", err.Error())
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "body.json"), []byte(`{"readings":1}`), 0644); err != nil {
+		t.Fatalf("unexpected error writing the body : %s", err.Error())
+	}
+	link := filepath.Join(dir, "link.json")
+	if err := os.Symlink(secret, link); err != nil {
+		t.Fatalf("unexpected error linking the secret : %s", err.Error())
+	}
+
+	//no file is read while the BodySourceDir is not configured
+	if _, err := bodySourcePath(filepath.Join(dir, "body.json")); err == nil {
+		t.Error("expected the file sources to be refused without a BodySourceDir")
+	}
+
+	Configuration.BodySourceDir = dir
+	defer func() { Configuration.BodySourceDir = "" }()
+	valid := []string{"body.json", "./nested/../body.json", filepath.Join(dir, "body.json"), "file://" + filepath.Join(dir, "body.json")}
+	for _, source := range valid {
+		if _, err := bodySourcePath(source); err != nil {
+			t.Errorf("unexpected error for the body source %s : %s", source, err.Error())
+		}
+	}
+	invalid := []string{"../secret.json", "nested/../../secret.json", secret, "file://" + secret, "file:///etc/passwd", "link.json"}
+	for _, source := range invalid {
+		if _, err := bodySourcePath(source); err == nil {
+			t.Errorf("expected the body source %s to be refused", source)
+		}
+		if err := validateBodySource(models.ScheduleEvent{Name: TestScheduleEventName, BodySource: source}); err == nil {
+			t.Errorf("expected the schedule event with the body source %s to be invalid", source)
+		}
+	}
+
+	//an event loaded with an outside source fails without sending anything
+	schedule := addTestSchedule(t, TestScheduleName)
+	addBodySourceTestEvent(t, schedule, "../secret.json")
+	if err := executeSchedule(schedule.Id.Hex()); err == nil {
+		t.Error("expected the outside body source to fail the execution")
+	}
+	if len(client.requests) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 0)
+	}
+}
+
+func TestAddScheduleEventRejectsAnOutsideBodySource(t *testing.T) {
+	resetScheduler()
+	addTestSchedule(t, TestScheduleName)
+
+	body := `{"name":"` + TestScheduleEventName + `","schedule":"` + TestScheduleName + `","bodySource":"/etc/passwd",` +
+		`"addressable":{"name":"` + TestScheduleEventName + `","protocol":"http","method":"POST","address":"localhost","port":48080,"path":"/api/v1/event"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scheduleevent", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusBadRequest)
+	}
+	if _, err := queryScheduleEventByName(TestScheduleEventName); err == nil {
+		t.Error("expected the schedule event with the outside body source not to be added")
+	}
+}
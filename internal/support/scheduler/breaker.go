@@ -0,0 +1,120 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Count the outcome of an execution toward the FailureThreshold, pausing the schedule once it has failed that many
+// times in a row. Reports whether the schedule has been paused. Must be called with the schedule mutex held.
+func recordExecutionOutcomeLocked(context *ScheduleContext, failed bool, now time.Time) bool {
+	if !failed {
+		context.ConsecutiveFailures = 0
+		context.failingSince = time.Time{}
+		return false
+	}
+
+	context.ConsecutiveFailures++
+	if context.failingSince.IsZero() {
+		context.failingSince = now
+	}
+	threshold := failureThreshold()
+	if threshold == 0 || context.ConsecutiveFailures < threshold || context.Paused {
+		return false
+	}
+
+	context.Paused = true
+	context.AutoPaused = true
+	context.autoPausedAt = now
+	markStateChanged()
+	LoggingClient.Warn(fmt.Sprintf("the schedule %s failed %d times in a row and has been paused", context.Schedule.Name, context.ConsecutiveFailures))
+	return true
+}
+
+// Resume an automatically paused schedule once the FailureCooldownMs has passed. The failures are still counted, so
+// the next failure pauses it again. Must be called with the schedule mutex held.
+func autoResumeLocked(context *ScheduleContext, now time.Time) bool {
+	cooldown := failureCooldown()
+	if !context.AutoPaused || cooldown == 0 || now.Before(context.autoPausedAt.Add(cooldown)) {
+		return false
+	}
+
+	context.skipMissedFires(now)
+	context.Paused = false
+	context.AutoPaused = false
+	markStateChanged()
+	LoggingClient.Info(fmt.Sprintf("the schedule %s has been resumed after its failure cooldown, next time : %s", context.Schedule.Name, context.NextTime.String()))
+	return true
+}
+
+// Remove a schedule which has kept failing for the RemoveAfterFailureMs, its config is assumed to be stale. The
+// schedule is also deleted from core-metadata when RemoveFromMetadata is set. Reports whether the schedule
+// has been removed. Must be called with the schedule mutex held.
+func removeFailingScheduleLocked(context *ScheduleContext, now time.Time) bool {
+	duration := removeAfterFailure()
+	if duration == 0 || context.failingSince.IsZero() || now.Sub(context.failingSince) < duration {
+		return false
+	}
+
+	schedule := context.Schedule
+	if err := removeScheduleLocked(schedule.Id.Hex()); err != nil {
+		LoggingClient.Error(fmt.Sprintf("unable to remove the failing schedule %s : %s", schedule.Name, err.Error()))
+		return false
+	}
+	LoggingClient.Error(fmt.Sprintf("the schedule %s has been failing since %s, %d executions in a row, and has been removed", schedule.Name, context.failingSince.String(), context.ConsecutiveFailures))
+
+	if Configuration.RemoveFromMetadata && !degradedMode && msc != nil {
+		go deleteScheduleFromMetadata(schedule)
+	}
+	return true
+}
+
+func deleteScheduleFromMetadata(schedule models.Schedule) {
+	if err := msc.Delete(schedule.Id.Hex()); err != nil {
+		LoggingClient.Error(fmt.Sprintf("error deleting the failing schedule %s from core-metadata : %s", schedule.Name, err.Error()))
+		return
+	}
+	LoggingClient.Info(fmt.Sprintf("deleted the failing schedule %s from core-metadata", schedule.Name))
+}
+
+// number of schedules currently paused by their failures, must be called with the schedule mutex held
+func autoPausedCountLocked() int {
+	count := 0
+	for _, scheduleContext := range scheduleIdToContextMap {
+		if scheduleContext.AutoPaused && !scheduleContext.MarkedDeleted {
+			count++
+		}
+	}
+	return count
+}
+
+// 0 when the schedules are never paused by their failures
+func failureThreshold() int {
+	if Configuration == nil || Configuration.FailureThreshold <= 0 {
+		return 0
+	}
+	return Configuration.FailureThreshold
+}
+
+// 0 when the paused schedules wait for a manual resume
+func failureCooldown() time.Duration {
+	if Configuration == nil || Configuration.FailureCooldownMs <= 0 {
+		return 0
+	}
+	return time.Duration(Configuration.FailureCooldownMs) * time.Millisecond
+}
+
+// 0 when the failing schedules are never removed
+func removeAfterFailure() time.Duration {
+	if Configuration == nil || Configuration.RemoveAfterFailureMs <= 0 {
+		return 0
+	}
+	return time.Duration(Configuration.RemoveAfterFailureMs) * time.Millisecond
+}
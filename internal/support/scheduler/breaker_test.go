@@ -0,0 +1,170 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestScheduleIsPausedAfterFailureThreshold(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{statusCode: http.StatusInternalServerError}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.FailureThreshold = 3
+	defer func() { Configuration.FailureThreshold = 0 }()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+
+	for i := 0; i < 5; i++ {
+		fake.Advance(24 * time.Hour)
+		triggerSchedule()
+		waitForExecutions()
+	}
+
+	if len(client.requests) != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 3)
+	}
+	if !context.Paused || !context.AutoPaused {
+		t.Error("expected the schedule to be paused by its failures")
+	}
+	if count := autoPausedCountLocked(); count != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, count, 1)
+	}
+
+	//a manual resume starts counting over
+	resumeSchedule(schedule.Id.Hex())
+	if context.Paused || context.ConsecutiveFailures != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.ConsecutiveFailures, 0)
+	}
+}
+
+func TestPausedScheduleResumesAfterCooldown(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{statusCode: http.StatusInternalServerError}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.FailureThreshold = 1
+	Configuration.FailureCooldownMs = int(36 * time.Hour / time.Millisecond)
+	defer func() {
+		Configuration.FailureThreshold = 0
+		Configuration.FailureCooldownMs = 0
+	}()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+
+	fake.Advance(24 * time.Hour)
+	triggerSchedule()
+	waitForExecutions()
+	if !context.AutoPaused {
+		t.Fatal("expected the schedule to be paused by its failure")
+	}
+
+	//still cooling down
+	fake.Advance(24 * time.Hour)
+	triggerSchedule()
+	waitForExecutions()
+	if !context.Paused || len(client.requests) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+
+	//resumed after the cooldown, the next failure pauses it again
+	fake.Advance(12 * time.Hour)
+	triggerSchedule()
+	waitForExecutions()
+	if context.Paused {
+		t.Error("expected the schedule to be resumed after the cooldown")
+	}
+	fake.Advance(24 * time.Hour)
+	triggerSchedule()
+	waitForExecutions()
+	if len(client.requests) != 2 || !context.AutoPaused {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 2)
+	}
+
+	//a success clears the failures
+	client.statusCode = http.StatusOK
+	resumeSchedule(schedule.Id.Hex())
+	fake.Advance(24 * time.Hour)
+	triggerSchedule()
+	waitForExecutions()
+	if context.Paused || context.ConsecutiveFailures != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.ConsecutiveFailures, 0)
+	}
+}
+
+func TestScheduleIsRemovedAfterFailureDuration(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{statusCode: http.StatusInternalServerError}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+	scheduleClient := &fakeScheduleClient{deleted: make(chan string, 1)}
+	msc = scheduleClient
+
+	Configuration.RemoveAfterFailureMs = int(72 * time.Hour / time.Millisecond)
+	Configuration.RemoveFromMetadata = true
+	defer func() {
+		Configuration.RemoveAfterFailureMs = 0
+		Configuration.RemoveFromMetadata = false
+		msc = nil
+	}()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	//the first failure on the 2nd, still failing three days later on the 5th
+	for i := 0; i < 6; i++ {
+		fake.Advance(24 * time.Hour)
+		triggerSchedule()
+		waitForExecutions()
+		if _, exists := scheduleIdToContextMap[schedule.Id.Hex()]; !exists {
+			break
+		}
+	}
+
+	if len(client.requests) != 4 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 4)
+	}
+	if _, err := querySchedule(schedule.Id.Hex()); err == nil {
+		t.Error("expected the failing schedule to be removed")
+	}
+	select {
+	case id := <-scheduleClient.deleted:
+		if id != schedule.Id.Hex() {
+			t.Errorf(TestUnexpectedMsgFormatStr, id, schedule.Id.Hex())
+		}
+	case <-time.After(time.Second):
+		t.Error("expected the failing schedule to be deleted from core-metadata")
+	}
+}
+
+func TestSuccessRestartsTheFailureDuration(t *testing.T) {
+	Configuration.RemoveAfterFailureMs = int(time.Hour / time.Millisecond)
+	defer func() { Configuration.RemoveAfterFailureMs = 0 }()
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	context := &ScheduleContext{}
+	recordExecutionOutcomeLocked(context, true, start)
+	recordExecutionOutcomeLocked(context, false, start.Add(30*time.Minute))
+	recordExecutionOutcomeLocked(context, true, start.Add(45*time.Minute))
+	if removeFailingScheduleLocked(context, start.Add(90*time.Minute)) {
+		t.Error("expected the success to restart the failure duration")
+	}
+}
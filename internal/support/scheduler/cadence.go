@@ -0,0 +1,77 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// ScheduleCadence holds the timing of a schedule which can be changed at runtime, the fields left out are kept
+type ScheduleCadence struct {
+	Frequency *string `json:"frequency"`
+	Cron      *string `json:"cron"`
+	Start     *string `json:"start"`
+	End       *string `json:"end"`
+}
+
+// Apply the cadence to a copy of the schedule. A new frequency replaces the cron expression and the other way round.
+func (c ScheduleCadence) apply(schedule models.Schedule) models.Schedule {
+	if c.Frequency != nil {
+		schedule.Frequency = *c.Frequency
+		if c.Cron == nil {
+			schedule.Cron = ""
+		}
+	}
+	if c.Cron != nil {
+		schedule.Cron = *c.Cron
+		if c.Frequency == nil {
+			schedule.Frequency = ""
+		}
+	}
+	if c.Start != nil {
+		schedule.Start = *c.Start
+	}
+	if c.End != nil {
+		schedule.End = *c.End
+	}
+	return schedule
+}
+
+// Change the timing of a schedule, storing it in core-metadata before it is rescheduled. The next fire follows the
+// new cadence while the iterations and the failures so far are kept, an execution already running completes
+// undisturbed.
+func updateScheduleCadence(scheduleId string, cadence ScheduleCadence) (models.Schedule, error) {
+	current, err := querySchedule(scheduleId)
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	schedule := cadence.apply(current)
+
+	//check the new timing on a scratch context so an invalid one leaves the schedule as it is
+	candidate := ScheduleContext{}
+	if err := candidate.Reset(schedule); err != nil {
+		return models.Schedule{}, ErrInvalidCadence{Err: err}
+	}
+	if err := validateOneOffStart(schedule); err != nil {
+		return models.Schedule{}, ErrInvalidCadence{Err: err}
+	}
+
+	if !isDegradedMode() {
+		if err := msc.Update(schedule); err != nil {
+			LoggingClient.Error(fmt.Sprintf("error updating the schedule %s in core-metadata : %s", schedule.Name, err.Error()))
+			return models.Schedule{}, err
+		}
+	}
+
+	//the fires so far still count towards the MaxIterations of the schedule
+	if err := resetSchedule(schedule, true); err != nil {
+		return models.Schedule{}, err
+	}
+	LoggingClient.Info(fmt.Sprintf("updated the cadence of the schedule %s", schedule.Name))
+	return schedule, nil
+}
@@ -0,0 +1,209 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func putScheduleCadence(id string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/schedule/"+id, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	return rec
+}
+
+func addCadenceTestSchedule(t *testing.T, frequency string) (models.Schedule, *fakeScheduleClient) {
+	schedule := newTestSchedule(TestScheduleName)
+	schedule.Frequency = frequency
+	mustAddSchedule(t, schedule)
+	scheduleClient := &fakeScheduleClient{schedules: []models.Schedule{schedule}}
+	msc = scheduleClient
+	return schedule, scheduleClient
+}
+
+func TestUpdateScheduleCadence(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 1, 1, 0, 5, 5, 0, time.UTC))
+	defer restore()
+	schedule, scheduleClient := addCadenceTestSchedule(t, "PT60S")
+	defer func() { msc = nil }()
+
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	if expected := time.Date(2018, 1, 1, 0, 6, 0, 0, time.UTC); !context.NextTime.Equal(expected) {
+		t.Fatalf(TestUnexpectedMsgFormatStr, context.NextTime, expected)
+	}
+
+	rec := putScheduleCadence(schedule.Id.Hex(), `{"frequency":"PT10S"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+
+	if expected := time.Date(2018, 1, 1, 0, 5, 10, 0, time.UTC); !context.NextTime.Equal(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, expected)
+	}
+	if context.Frequency != 10*time.Second {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.Frequency, 10*time.Second)
+	}
+	if scheduleClient.schedules[0].Frequency != "PT10S" {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleClient.schedules[0].Frequency, "PT10S")
+	}
+}
+
+func TestUpdateScheduleCadenceKeepsTheProgress(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 1, 1, 0, 5, 5, 0, time.UTC))
+	defer restore()
+	schedule := newTestSchedule(TestScheduleName)
+	schedule.Frequency = "PT60S"
+	schedule.MaxIterations = 3
+	mustAddSchedule(t, schedule)
+	msc = &fakeScheduleClient{schedules: []models.Schedule{schedule}}
+	defer func() { msc = nil }()
+
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	context.CurrentIterations = 2
+	context.ConsecutiveFailures = 1
+
+	if rec := putScheduleCadence(schedule.Id.Hex(), `{"frequency":"PT10S"}`); rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+
+	//the schedule has a single fire left whatever its cadence
+	if context.CurrentIterations != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.CurrentIterations, 2)
+	}
+	if context.ConsecutiveFailures != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.ConsecutiveFailures, 1)
+	}
+}
+
+func TestUpdateScheduleWithAnInvalidDefinitionKeepsTheSchedule(t *testing.T) {
+	resetScheduler()
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	invalid := schedule
+	invalid.MissedFirePolicy = "unknown"
+	if err := updateSchedule(invalid); err == nil {
+		t.Fatal("expected an error for the invalid schedule")
+	}
+
+	current, err := querySchedule(schedule.Id.Hex())
+	if err != nil {
+		t.Fatalf("the schedule should still be scheduled : %s", err.Error())
+	}
+	if current.MissedFirePolicy != "" {
+		t.Errorf(TestUnexpectedMsgFormatStr, current.MissedFirePolicy, "")
+	}
+	if _, err := queryScheduleEventByName(TestScheduleEventName); err != nil {
+		t.Errorf("the event of the schedule should still be found : %s", err.Error())
+	}
+}
+
+func TestUpdateScheduleCadenceReplacesFrequencyWithCron(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 1, 1, 0, 5, 5, 0, time.UTC))
+	defer restore()
+	schedule, _ := addCadenceTestSchedule(t, "PT60S")
+	defer func() { msc = nil }()
+
+	if rec := putScheduleCadence(schedule.Id.Hex(), `{"cron":"0 30 * * * *"}`); rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+
+	updated, _ := querySchedule(schedule.Id.Hex())
+	if updated.Frequency != "" || updated.Cron != "0 30 * * * *" {
+		t.Errorf("expected the cron expression to replace the frequency, got %s", updated.String())
+	}
+	if expected := time.Date(2018, 1, 1, 0, 30, 0, 0, time.UTC); !scheduleIdToContextMap[schedule.Id.Hex()].NextTime.Equal(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleIdToContextMap[schedule.Id.Hex()].NextTime, expected)
+	}
+}
+
+func TestUpdateScheduleCadenceErrors(t *testing.T) {
+	resetScheduler()
+	schedule, scheduleClient := addCadenceTestSchedule(t, "PT60S")
+	defer func() { msc = nil }()
+
+	tests := []struct {
+		name     string
+		id       string
+		body     string
+		expected int
+	}{
+		{"unknown schedule", bson.NewObjectId().Hex(), `{"frequency":"PT10S"}`, http.StatusNotFound},
+		{"malformed body", schedule.Id.Hex(), `{"frequency":`, http.StatusBadRequest},
+		{"invalid cron", schedule.Id.Hex(), `{"cron":"not a cron"}`, http.StatusBadRequest},
+		{"end before start", schedule.Id.Hex(), `{"end":"20170101T000000"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rec := putScheduleCadence(tt.id, tt.body); rec.Code != tt.expected {
+				t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, tt.expected)
+			}
+		})
+	}
+
+	//the rejected cadences left the schedule as it was
+	current, err := querySchedule(schedule.Id.Hex())
+	if err != nil || current.Frequency != "PT60S" || current.End != "" {
+		t.Errorf("expected the schedule to be unchanged, got %s", current.String())
+	}
+	if scheduleClient.schedules[0].Frequency != "PT60S" {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleClient.schedules[0].Frequency, "PT60S")
+	}
+}
+
+func TestUpdateScheduleCadenceDuringExecution(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	client := &hangingHTTPClient{calls: make(map[string]int), path: "/api/v1/hang", release: make(chan struct{})}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+	schedule, _ := addCadenceTestSchedule(t, "PT60S")
+	defer func() { msc = nil }()
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/hang", "")
+
+	fake.Advance(30 * time.Second)
+	triggerSchedule()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		client.mutex.Lock()
+		calls := client.calls["/api/v1/hang"]
+		client.mutex.Unlock()
+		if calls == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	//the running execution is not disturbed and requeues the schedule at the time of the new cadence
+	fake.Advance(2 * time.Second)
+	if rec := putScheduleCadence(schedule.Id.Hex(), `{"frequency":"PT10S"}`); rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	close(client.release)
+	waitForExecutions()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	if expected := time.Date(2018, 1, 1, 0, 1, 10, 0, time.UTC); !context.NextTime.Equal(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, expected)
+	}
+	if context.Executing || scheduleQueue.Length() != 1 {
+		t.Errorf("expected the schedule to be requeued once its execution is over")
+	}
+}
@@ -0,0 +1,173 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DefaultCallbackTimeoutMs bounds a callback request when CallbackTimeoutMs is not set
+const DefaultCallbackTimeoutMs = 5000
+
+// MaxPendingCallbacks is the number of callbacks which can be in flight at the same time, the callbacks over the
+// limit are dropped so slow receivers never pile up requests
+const MaxPendingCallbacks = 100
+
+// CallbackHeader marks the requests posting the outcome of a schedule event, the scheduler refuses to trigger
+// schedules from them so a callback can never start the execution which calls it back
+const CallbackHeader = "X-Scheduler-Callback"
+
+// EventOutcome is the body posted to the OnSuccessURL or the OnFailureURL of a schedule event after it has fired
+type EventOutcome struct {
+	ScheduleId      string `json:"scheduleId"`
+	ScheduleEventId string `json:"scheduleEventId"`
+	Name            string `json:"name"`
+	Success         bool   `json:"success"`
+	StatusCode      int    `json:"statusCode,omitempty"`
+	DurationMs      int64  `json:"durationMs"`
+	Error           string `json:"error,omitempty"`
+	CorrelationId   string `json:"correlationId,omitempty"`
+}
+
+var (
+	callbackSlots     = make(chan struct{}, MaxPendingCallbacks)
+	callbacksInFlight sync.WaitGroup
+)
+
+func callbackTimeout() time.Duration {
+	if Configuration == nil || Configuration.CallbackTimeoutMs <= 0 {
+		return DefaultCallbackTimeoutMs * time.Millisecond
+	}
+	return time.Duration(Configuration.CallbackTimeoutMs) * time.Millisecond
+}
+
+// the callback url matching the outcome of the execution, empty when the event has none
+func callbackURL(scheduleEvent models.ScheduleEvent, err error) string {
+	if err != nil {
+		return strings.TrimSpace(scheduleEvent.OnFailureURL)
+	}
+	return strings.TrimSpace(scheduleEvent.OnSuccessURL)
+}
+
+// Post the outcome of an execution to the matching callback of the event outside of the execution. A failed
+// callback is logged, it is neither retried nor changes the outcome of the execution.
+func postEventCallback(scheduleEvent models.ScheduleEvent, scheduleId string, correlationId string, statusCode int, duration time.Duration, err error) {
+	callback := callbackURL(scheduleEvent, err)
+	if callback == "" || isDryRun() {
+		return
+	}
+
+	outcome := EventOutcome{
+		ScheduleId:      scheduleId,
+		ScheduleEventId: scheduleEvent.Id.Hex(),
+		Name:            scheduleEvent.Name,
+		Success:         err == nil,
+		StatusCode:      statusCode,
+		DurationMs:      int64(duration / time.Millisecond),
+		CorrelationId:   correlationId,
+	}
+	if err != nil {
+		outcome.Error = err.Error()
+	}
+
+	select {
+	case callbackSlots <- struct{}{}:
+	default:
+		LoggingClient.Warn(executionLogMsg(correlationId, fmt.Sprintf("too many pending callbacks, dropping the callback of the event with id : %s", outcome.ScheduleEventId)), correlationId)
+		return
+	}
+
+	callbacksInFlight.Add(1)
+	go func() {
+		defer callbacksInFlight.Done()
+		defer func() { <-callbackSlots }()
+		if err := sendCallback(callback, outcome); err != nil {
+			LoggingClient.Warn(executionLogMsg(correlationId, fmt.Sprintf("the callback of the event with id : %s failed : %s", outcome.ScheduleEventId, err.Error())), correlationId)
+		}
+	}()
+}
+
+func sendCallback(callback string, outcome EventOutcome) error {
+	payload, err := json.Marshal(outcome)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, callback, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid callback %s : %s", callback, err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), callbackTimeout())
+	defer cancel()
+	req = req.WithContext(ctx)
+	req.Header.Set(ContentTypeKey, ContentTypeJsonValue)
+	req.Header.Set(UserAgentKey, userAgent())
+	req.Header.Set(CorrelationHeader, outcome.CorrelationId)
+	req.Header.Set(CallbackHeader, outcome.ScheduleEventId)
+	signRequest(req, payload)
+
+	_, statusCode, err := sendRequestAndGetResponse(getHTTPClient(), req)
+	if err != nil {
+		return err
+	}
+	if statusCode < http.StatusOK || statusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("the callback %s answered with status code %d", callback, statusCode)
+	}
+	return nil
+}
+
+// wait for the callbacks in flight to complete
+func waitForCallbacks() {
+	callbacksInFlight.Wait()
+}
+
+// The callbacks must be absolute http or https urls, and must not point at the event itself which would then be
+// requested again by each of its executions
+func validateCallbacks(scheduleEvent models.ScheduleEvent) error {
+	callbacks := []struct {
+		field string
+		value string
+	}{
+		{"success", scheduleEvent.OnSuccessURL},
+		{"failure", scheduleEvent.OnFailureURL},
+	}
+	for _, callback := range callbacks {
+		value := strings.TrimSpace(callback.value)
+		if value == "" {
+			continue
+		}
+		parsed, err := url.Parse(value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("the schedule event %q has an invalid %s callback %q, expected an http or https url", scheduleEvent.Name, callback.field, callback.value)
+		}
+		if isMQTTAddressable(scheduleEvent.Addressable) {
+			continue
+		}
+		if eventUrl, err := buildEventUrl(scheduleEvent.Addressable); err == nil && sameEndpoint(parsed, eventUrl) {
+			return fmt.Errorf("the schedule event %q has a %s callback %q targeting the event itself", scheduleEvent.Name, callback.field, callback.value)
+		}
+	}
+	return nil
+}
+
+// whether the callback and the event url share their scheme, host, port and path, whatever their query
+func sameEndpoint(callback *url.URL, eventUrl string) bool {
+	parsed, err := url.Parse(eventUrl)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(callback.Scheme, parsed.Scheme) && strings.EqualFold(callback.Host, parsed.Host) &&
+		strings.TrimRight(callback.Path, "/") == strings.TrimRight(parsed.Path, "/")
+}
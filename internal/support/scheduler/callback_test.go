@@ -0,0 +1,156 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const (
+	testSuccessCallback = "http://localhost:48090/api/v1/succeeded"
+	testFailureCallback = "http://localhost:48090/api/v1/failed"
+)
+
+func addCallbackTestScheduleEvent(t *testing.T, schedule models.Schedule) models.ScheduleEvent {
+	scheduleEvent := newTestScheduleEvent(schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	scheduleEvent.OnSuccessURL = testSuccessCallback
+	scheduleEvent.OnFailureURL = testFailureCallback
+	return mustAddScheduleEvent(t, scheduleEvent)
+}
+
+// execute the schedule and return the callback it posted
+func executeWithCallback(t *testing.T, client *mockHTTPClient, schedule models.Schedule) (*http.Request, EventOutcome) {
+	executeSchedule(schedule.Id.Hex())
+	waitForCallbacks()
+
+	if len(client.requests) != 2 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 2)
+	}
+	callback := client.requests[1]
+	if callback.Method != http.MethodPost {
+		t.Errorf(TestUnexpectedMsgFormatStr, callback.Method, http.MethodPost)
+	}
+	var outcome EventOutcome
+	if err := json.Unmarshal([]byte(client.bodies[1]), &outcome); err != nil {
+		t.Fatalf("unexpected error decoding the outcome %s : %s", client.bodies[1], err.Error())
+	}
+	return callback, outcome
+}
+
+func TestSuccessCallbackFiresOn2xx(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{statusCode: http.StatusNoContent}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addCallbackTestScheduleEvent(t, schedule)
+
+	callback, outcome := executeWithCallback(t, client, schedule)
+	if callback.URL.String() != testSuccessCallback {
+		t.Errorf(TestUnexpectedMsgFormatStr, callback.URL.String(), testSuccessCallback)
+	}
+	if callback.Header.Get(CallbackHeader) != scheduleEvent.Id.Hex() {
+		t.Errorf(TestUnexpectedMsgFormatStr, callback.Header.Get(CallbackHeader), scheduleEvent.Id.Hex())
+	}
+	if !outcome.Success || outcome.StatusCode != http.StatusNoContent || outcome.Error != "" {
+		t.Errorf("unexpected outcome %+v", outcome)
+	}
+	if outcome.ScheduleId != schedule.Id.Hex() || outcome.ScheduleEventId != scheduleEvent.Id.Hex() || outcome.Name != TestScheduleEventName {
+		t.Errorf("unexpected outcome %+v", outcome)
+	}
+}
+
+func TestFailureCallbackFiresOnError(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{statusCode: http.StatusNotFound}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addCallbackTestScheduleEvent(t, schedule)
+
+	callback, outcome := executeWithCallback(t, client, schedule)
+	if callback.URL.String() != testFailureCallback {
+		t.Errorf(TestUnexpectedMsgFormatStr, callback.URL.String(), testFailureCallback)
+	}
+	if outcome.Success || outcome.StatusCode != http.StatusNotFound || outcome.Error == "" {
+		t.Errorf("unexpected outcome %+v", outcome)
+	}
+}
+
+func TestCallbackIsBoundedByTheCallbackTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	Configuration.CallbackTimeoutMs = 50
+	defer func() { Configuration.CallbackTimeoutMs = 0 }()
+
+	start := time.Now()
+	if err := sendCallback(server.URL, EventOutcome{Name: TestScheduleEventName}); err == nil {
+		t.Error("expected the hanging callback to time out")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the callback took %s, expected it to be cancelled after its timeout", elapsed)
+	}
+}
+
+func TestValidateCallbacks(t *testing.T) {
+	addressable := models.Addressable{Protocol: "http", HTTPMethod: http.MethodPost, Address: "localhost", Port: 48080, Path: "/api/v1/ping"}
+	valid := []models.ScheduleEvent{
+		{Name: TestScheduleEventName, Addressable: addressable},
+		{Name: TestScheduleEventName, Addressable: addressable, OnSuccessURL: testSuccessCallback, OnFailureURL: "https://alerts.example.com/hook?from=scheduler"},
+	}
+	for _, scheduleEvent := range valid {
+		if err := validateCallbacks(scheduleEvent); err != nil {
+			t.Errorf("unexpected error : %s", err.Error())
+		}
+	}
+
+	invalid := []models.ScheduleEvent{
+		{Name: TestScheduleEventName, Addressable: addressable, OnSuccessURL: "localhost:48090/api/v1/succeeded"},
+		{Name: TestScheduleEventName, Addressable: addressable, OnFailureURL: "ftp://localhost/failed"},
+		{Name: TestScheduleEventName, Addressable: addressable, OnFailureURL: "http:///failed"},
+		//a callback to the event itself would fire it again on each of its executions
+		{Name: TestScheduleEventName, Addressable: addressable, OnSuccessURL: "http://LOCALHOST:48080/api/v1/ping/?again=true"},
+	}
+	for _, scheduleEvent := range invalid {
+		if err := validateCallbacks(scheduleEvent); err == nil {
+			t.Errorf("expected an error for the callbacks %q and %q", scheduleEvent.OnSuccessURL, scheduleEvent.OnFailureURL)
+		}
+	}
+}
+
+func TestCallbacksCanNotTriggerSchedules(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+	addTaggedTestSchedule(t, "cleanup", "nightly")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/schedule/trigger?tag=nightly", nil)
+	req.Header.Set(CallbackHeader, bson.NewObjectId().Hex())
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusLoopDetected {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusLoopDetected)
+	}
+	if len(client.requests) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 0)
+	}
+}
@@ -0,0 +1,135 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxIdleConnsPerHost is the number of idle connections kept per target host when MaxIdleConnsPerHost is not configured
+	DefaultMaxIdleConnsPerHost = 2
+
+	// DefaultIdleConnTimeout is how long an idle connection is kept in milliseconds when IdleConnTimeout is not configured
+	DefaultIdleConnTimeout = 90000
+)
+
+// the settings the default client was built with, it is rebuilt when they change
+type clientSettings struct {
+	timeout             time.Duration
+	caFile              string
+	insecureSkipVerify  bool
+	maxIdleConnsPerHost int
+	idleConnTimeout     time.Duration
+	httpProxy           string
+	httpsProxy          string
+	noProxy             string
+}
+
+var (
+	defaultClientMutex    sync.Mutex
+	defaultClient         *http.Client
+	defaultClientSettings clientSettings
+)
+
+func currentClientSettings() clientSettings {
+	settings := clientSettings{
+		maxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+		idleConnTimeout:     DefaultIdleConnTimeout * time.Millisecond,
+	}
+	if Configuration == nil {
+		return settings
+	}
+
+	settings.timeout = time.Duration(Configuration.Service.Timeout) * time.Millisecond
+	settings.caFile = Configuration.TLSCAFile
+	settings.insecureSkipVerify = Configuration.TLSInsecureSkipVerify
+	if Configuration.MaxIdleConnsPerHost > 0 {
+		settings.maxIdleConnsPerHost = Configuration.MaxIdleConnsPerHost
+	}
+	if Configuration.IdleConnTimeout > 0 {
+		settings.idleConnTimeout = time.Duration(Configuration.IdleConnTimeout) * time.Millisecond
+	}
+	settings.httpProxy = Configuration.HTTPProxy
+	settings.httpsProxy = Configuration.HTTPSProxy
+	settings.noProxy = Configuration.NoProxy
+	return settings
+}
+
+// the client used when none has been injected, shared so the connections are reused
+func getDefaultHTTPClient() *http.Client {
+	defaultClientMutex.Lock()
+	defer defaultClientMutex.Unlock()
+
+	settings := currentClientSettings()
+	if defaultClient != nil && defaultClientSettings == settings {
+		return defaultClient
+	}
+
+	tlsConfig, err := newTLSConfig(settings)
+	if err != nil {
+		//keep verifying against the system roots rather than trusting everything
+		LoggingClient.Error(fmt.Sprintf("unable to load the CA bundle, using the system roots : %s", err.Error()))
+		tlsConfig = &tls.Config{}
+	}
+
+	//the connections of the replaced client are not reused anymore
+	if defaultClient != nil {
+		if transport, ok := defaultClient.Transport.(*http.Transport); ok {
+			transport.CloseIdleConnections()
+		}
+	}
+
+	defaultClient = &http.Client{
+		Timeout:       settings.timeout,
+		Transport:     newTransport(settings, tlsConfig),
+		CheckRedirect: checkRedirect,
+	}
+	defaultClientSettings = settings
+	return defaultClient
+}
+
+// the connections to the targets are kept open between the fires so they are not set up again every time
+func newTransport(settings clientSettings, tlsConfig *tls.Config) *http.Transport {
+	return &http.Transport{
+		Proxy:                 newProxyFunc(settings),
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   10 * time.Second,
+		MaxIdleConnsPerHost:   settings.maxIdleConnsPerHost,
+		IdleConnTimeout:       settings.idleConnTimeout,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// Build the TLS configuration for the HTTPS addressables, verification is only skipped when explicitly enabled
+func newTLSConfig(settings clientSettings) (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+
+	if settings.insecureSkipVerify {
+		LoggingClient.Warn("the TLS certificates of the schedule event targets are not verified, do not use this in production")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if settings.caFile != "" {
+		pem, err := ioutil.ReadFile(settings.caFile)
+		if err != nil {
+			return nil, err
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", settings.caFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// sendToTLSServer sends a request through the default client to a schedule event targeting the server
+func sendToTLSServer(server *httptest.Server) (int, error) {
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	scheduleEvent := models.ScheduleEvent{
+		Id:   bson.NewObjectId(),
+		Name: TestScheduleEventName,
+		Addressable: models.Addressable{
+			Name:       TestScheduleEventName,
+			Protocol:   "https",
+			HTTPMethod: http.MethodGet,
+			Address:    serverUrl.Hostname(),
+			Port:       port,
+			Path:       "/api/v1/ping",
+		},
+	}
+	return executeScheduleEvent(context.Background(), scheduleEvent, "")
+}
+
+func TestDefaultClientRejectsUnknownCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	if _, err := sendToTLSServer(server); err == nil {
+		t.Error("expected the certificate of the server to be rejected")
+	}
+}
+
+func TestDefaultClientTrustsConfiguredCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	caFile, err := ioutil.TempFile("", "scheduler-ca")
+	if err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	defer os.Remove(caFile.Name())
+	pem.Encode(caFile, &pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caFile.Close()
+
+	Configuration.TLSCAFile = caFile.Name()
+	defer func() { Configuration.TLSCAFile = "" }()
+
+	statusCode, err := sendToTLSServer(server)
+	if err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if statusCode != http.StatusOK {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusOK)
+	}
+}
+
+func TestDefaultClientSkipsVerification(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	Configuration.TLSInsecureSkipVerify = true
+	defer func() { Configuration.TLSInsecureSkipVerify = false }()
+
+	statusCode, err := sendToTLSServer(server)
+	if err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if statusCode != http.StatusOK {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusOK)
+	}
+}
+
+func TestNewTLSConfigInvalidCAFile(t *testing.T) {
+	if _, err := newTLSConfig(clientSettings{caFile: "/nonexistent/ca.pem"}); err == nil {
+		t.Error("expected an error for a missing CA bundle")
+	}
+}
+
+func TestDefaultClientReusesConnections(t *testing.T) {
+	var connections int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "pong")
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	for i := 0; i < 5; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		if _, _, err := sendRequestAndGetResponse(getDefaultHTTPClient(), req); err != nil {
+			t.Fatalf("unexpected error : %s", err.Error())
+		}
+	}
+
+	if count := atomic.LoadInt32(&connections); count != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, count, 1)
+	}
+}
+
+func benchmarkRepeatedFires(b *testing.B, client HTTPClient) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "pong")
+	}))
+	defer server.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		if _, _, err := sendRequestAndGetResponse(client, req); err != nil {
+			b.Fatalf("unexpected error : %s", err.Error())
+		}
+	}
+}
+
+func BenchmarkRepeatedFiresWithConnectionReuse(b *testing.B) {
+	benchmarkRepeatedFires(b, getDefaultHTTPClient())
+}
+
+func BenchmarkRepeatedFiresWithoutConnectionReuse(b *testing.B) {
+	benchmarkRepeatedFires(b, &http.Client{Transport: &http.Transport{DisableKeepAlives: true}})
+}
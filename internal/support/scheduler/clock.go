@@ -0,0 +1,22 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import "time"
+
+// Clock is the source of the current time for the scheduling logic.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// the time source of the scheduler, the tests replace it with a fake one
+var clock Clock = realClock{}
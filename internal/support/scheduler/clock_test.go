@@ -0,0 +1,110 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// fakeClock only moves when the test advances it
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// useFakeClock replaces the scheduler clock until the returned function is called
+func useFakeClock(now time.Time) (*fakeClock, func()) {
+	fake := &fakeClock{now: now}
+	clock = fake
+	return fake, func() { clock = realClock{} }
+}
+
+func addClockTestSchedule(t *testing.T, name string, start string, frequency string) string {
+	schedule := newTestSchedule(name)
+	schedule.Start = start
+	schedule.Frequency = frequency
+	return mustAddSchedule(t, schedule).Id.Hex()
+}
+
+func TestTriggerScheduleWithFakeClock(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+
+	every10m := addClockTestSchedule(t, "every-10m", "20180101T001000", "PT10M")
+	hourly := addClockTestSchedule(t, "hourly", "20180101T002000", "PT1H")
+
+	steps := []struct {
+		advance    time.Duration
+		iterations map[string]int64
+	}{
+		{5 * time.Minute, map[string]int64{every10m: 0, hourly: 0}},
+		{5 * time.Minute, map[string]int64{every10m: 1, hourly: 0}},
+		{9 * time.Minute, map[string]int64{every10m: 1, hourly: 0}},
+		{time.Minute, map[string]int64{every10m: 2, hourly: 1}},
+		{10 * time.Minute, map[string]int64{every10m: 3, hourly: 1}},
+	}
+
+	for i, step := range steps {
+		fake.Advance(step.advance)
+		triggerSchedule()
+		waitForExecutions()
+
+		for id, expected := range step.iterations {
+			context := scheduleIdToContextMap[id]
+			if context.CurrentIterations != expected {
+				t.Errorf("step %d, schedule %s : "+TestUnexpectedMsgFormatStrForIntVal, i, context.Schedule.Name, context.CurrentIterations, expected)
+			}
+		}
+	}
+
+	if next := scheduleIdToContextMap[every10m].NextTime; !next.Equal(fake.Now().Add(10 * time.Minute)) {
+		t.Errorf(TestUnexpectedMsgFormatStr, next, fake.Now().Add(10*time.Minute))
+	}
+}
+
+func TestScheduleContextUsesClock(t *testing.T) {
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+
+	testScheduleContext := ScheduleContext{}
+	testScheduleContext.Reset(models.Schedule{
+		Name:      TestScheduleName,
+		Start:     "20180101T000000",
+		End:       "20180101T010000",
+		Frequency: "PT1M",
+	})
+
+	// the first boundary after the fake now, not the real one
+	expected := time.Date(2018, 1, 1, 0, 1, 0, 0, time.UTC)
+	if !testScheduleContext.NextTime.Equal(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, expected)
+	}
+	if testScheduleContext.IsEnded() {
+		t.Error("expected the schedule not to have ended yet")
+	}
+
+	fake.Advance(time.Hour)
+	if !testScheduleContext.IsEnded() {
+		t.Error("expected the schedule to have ended")
+	}
+}
@@ -0,0 +1,129 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ScheduleClone is the request to copy a schedule under a new name
+type ScheduleClone struct {
+	Name       string `json:"name"`
+	WithEvents bool   `json:"withEvents"` // copy the events of the schedule along with it
+}
+
+// the copy of a schedule under the given name, without an id and sharing none of its slices
+func cloneScheduleModel(schedule models.Schedule, name string) models.Schedule {
+	clone := schedule
+	clone.BaseObject = models.BaseObject{}
+	clone.Id = ""
+	clone.Name = name
+	if schedule.Tags != nil {
+		clone.Tags = append([]string{}, schedule.Tags...)
+	}
+	return clone
+}
+
+// The copy of an event for the schedule clone. The name of the source schedule leading the event name is replaced
+// by the name of the clone, the other event names are prefixed with it.
+func cloneScheduleEventModel(scheduleEvent models.ScheduleEvent, source string, clone string) models.ScheduleEvent {
+	copied := scheduleEvent
+	copied.BaseObject = models.BaseObject{}
+	copied.Id = ""
+	copied.Schedule = clone
+	if strings.HasPrefix(scheduleEvent.Name, source) {
+		copied.Name = clone + strings.TrimPrefix(scheduleEvent.Name, source)
+	} else {
+		copied.Name = clone + "-" + scheduleEvent.Name
+	}
+	copied.QueryParams = copyStringMap(scheduleEvent.QueryParams)
+	copied.Headers = copyStringMap(scheduleEvent.Headers)
+	return copied
+}
+
+func copyStringMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	return copied
+}
+
+// Copy the schedule with the given id under a new name, along with its events when asked to. The copies are stored
+// in core-metadata with ids of their own before they are scheduled. Returns the schedule clone.
+func cloneSchedule(scheduleId string, request ScheduleClone) (models.Schedule, error) {
+	mutex.Lock()
+	source, exists := scheduleIdToContextMap[scheduleId]
+	if !exists || source.MarkedDeleted {
+		mutex.Unlock()
+		return models.Schedule{}, fmt.Errorf("scheduler could not find schedule with id : %s", scheduleId)
+	}
+	schedule := cloneScheduleModel(source.Schedule, request.Name)
+	var scheduleEvents []models.ScheduleEvent
+	if request.WithEvents {
+		for _, scheduleEvent := range orderedScheduleEvents(source.ScheduleEventsMap) {
+			scheduleEvents = append(scheduleEvents, cloneScheduleEventModel(scheduleEvent, source.Schedule.Name, request.Name))
+		}
+	}
+	mutex.Unlock()
+
+	//a clone of a one-off schedule which has fired would never fire
+	if err := validateOneOffStart(schedule); err != nil {
+		return models.Schedule{}, ErrInvalidCadence{Err: err}
+	}
+
+	//check every name before anything is stored
+	if _, err := queryScheduleByName(schedule.Name); err == nil {
+		return models.Schedule{}, ErrNameConflict{Kind: "schedule", Name: schedule.Name}
+	}
+	for _, scheduleEvent := range scheduleEvents {
+		if _, err := queryScheduleEventByName(scheduleEvent.Name); err == nil {
+			return models.Schedule{}, ErrNameConflict{Kind: "schedule event", Name: scheduleEvent.Name}
+		}
+	}
+
+	id, err := storeClone("schedule", schedule.Name, func() (string, error) { return addScheduleToCoreMetaData(&schedule) })
+	if err != nil {
+		return models.Schedule{}, err
+	}
+	schedule.Id = id
+	if err := addSchedule(schedule); err != nil {
+		return models.Schedule{}, err
+	}
+
+	for _, scheduleEvent := range scheduleEvents {
+		id, err := storeClone("schedule event", scheduleEvent.Name, func() (string, error) { return addScheduleEventToCoreMetadata(&scheduleEvent) })
+		if err != nil {
+			return schedule, err
+		}
+		scheduleEvent.Id = id
+		if err := addScheduleEvent(scheduleEvent); err != nil {
+			return schedule, err
+		}
+	}
+
+	LoggingClient.Info(fmt.Sprintf("cloned the schedule with id : %s as %s with %d schedule events", scheduleId, schedule.Name, len(scheduleEvents)))
+	return schedule, nil
+}
+
+// store a copy in core-metadata and return its id, a copy only lives in the scheduler in degraded mode
+func storeClone(kind string, name string, store func() (string, error)) (bson.ObjectId, error) {
+	if isDegradedMode() {
+		return bson.NewObjectId(), nil
+	}
+	id, err := store()
+	if err != nil {
+		return "", err
+	}
+	return metadataObjectId(kind, name, id)
+}
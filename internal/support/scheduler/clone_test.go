@@ -0,0 +1,175 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func requestCloneSchedule(id string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/schedule/"+id+"/clone", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestReplyCloneSchedule(t *testing.T) {
+	resetScheduler()
+	SetHTTPClient(&mockHTTPClient{})
+	defer SetHTTPClient(nil)
+	scheduleClient := &fakeScheduleClient{}
+	scheduleEventClient := &fakeScheduleEventClient{}
+	msc = scheduleClient
+	msec = scheduleEventClient
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	source := models.Schedule{Id: bson.NewObjectId(), Name: "nightly", Start: "20180101T000000", Frequency: "P1D", Tags: []string{"cleanup"}}
+	if err := addSchedule(source); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	scrub := addTestScheduleEvent(t, source, "nightly-scrub", http.MethodDelete, "/api/v1/event/scrub", "")
+	purge := models.ScheduleEvent{
+		Id:       bson.NewObjectId(),
+		Name:     "purge",
+		Schedule: source.Name,
+		Headers:  map[string]string{"X-Tenant-Id": "tenant-a"},
+		Addressable: models.Addressable{
+			Name:       "purge",
+			Protocol:   "http",
+			HTTPMethod: http.MethodDelete,
+			Address:    "localhost",
+			Port:       48080,
+			Path:       "/api/v1/event/removeold/age/604800000",
+		},
+	}
+	if err := addScheduleEvent(purge); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+
+	rec := requestCloneSchedule(source.Id.Hex(), `{"name":"weekly","withEvents":true}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	var clone models.Schedule
+	if err := json.NewDecoder(rec.Body).Decode(&clone); err != nil {
+		t.Fatalf("unexpected error decoding the clone : %s", err.Error())
+	}
+	if clone.Name != "weekly" {
+		t.Errorf(TestUnexpectedMsgFormatStr, clone.Name, "weekly")
+	}
+	if !clone.Id.Valid() || clone.Id == source.Id {
+		t.Errorf("expected a new id for the clone, got %q", clone.Id.Hex())
+	}
+	if clone.Frequency != source.Frequency || len(clone.Tags) != 1 || clone.Tags[0] != "cleanup" {
+		t.Errorf("expected the clone to keep the config of the schedule, got %v", clone)
+	}
+	if len(scheduleClient.schedules) != 1 || len(scheduleEventClient.scheduleEvents) != 2 {
+		t.Errorf("expected the clone and its 2 events in core-metadata, got %d schedules and %d events", len(scheduleClient.schedules), len(scheduleEventClient.scheduleEvents))
+	}
+
+	scheduleEvents, err := queryScheduleEventsOfSchedule("weekly")
+	if err != nil {
+		t.Fatalf("unexpected error querying the events of the clone : %s", err.Error())
+	}
+	expected := []string{"weekly-purge", "weekly-scrub"}
+	if len(scheduleEvents) != len(expected) {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(scheduleEvents), len(expected))
+	}
+	for i, scheduleEvent := range scheduleEvents {
+		if scheduleEvent.Name != expected[i] {
+			t.Errorf(TestUnexpectedMsgFormatStr, scheduleEvent.Name, expected[i])
+		}
+		if scheduleEvent.Id == scrub.Id || scheduleEvent.Id == purge.Id {
+			t.Errorf("expected a new id for the event %s", scheduleEvent.Name)
+		}
+		if scheduleEvent.Schedule != "weekly" {
+			t.Errorf(TestUnexpectedMsgFormatStr, scheduleEvent.Schedule, "weekly")
+		}
+	}
+
+	//the clone and its schedule do not share any state
+	scheduleEvents[0].Headers["X-Tenant-Id"] = "tenant-b"
+	if value := scheduleIdToContextMap[source.Id.Hex()].ScheduleEventsMap[purge.Id.Hex()].Headers["X-Tenant-Id"]; value != "tenant-a" {
+		t.Errorf(TestUnexpectedMsgFormatStr, value, "tenant-a")
+	}
+	executeSchedule(source.Id.Hex())
+	if iterations := scheduleIdToContextMap[clone.Id.Hex()].CurrentIterations; iterations != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, iterations, 0)
+	}
+	if err := removeSchedule(clone.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error removing the clone : %s", err.Error())
+	}
+	if _, err := queryScheduleEventByName("nightly-scrub"); err != nil {
+		t.Errorf("the events of the schedule should stay when its clone is removed : %s", err.Error())
+	}
+}
+
+func TestReplyCloneScheduleRejectsTakenNames(t *testing.T) {
+	resetScheduler()
+	msc = &fakeScheduleClient{}
+	msec = &fakeScheduleEventClient{}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	source := addTestSchedule(t, "nightly")
+	addTestScheduleEvent(t, source, "scrub", http.MethodDelete, "/api/v1/event/scrub", "")
+	other := addTestSchedule(t, "weekly")
+	addTestScheduleEvent(t, other, "monthly-scrub", http.MethodDelete, "/api/v1/event/scrub", "")
+
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"name":"weekly"}`, http.StatusConflict},
+		{`{"name":"monthly","withEvents":true}`, http.StatusConflict},
+		{`{"name":" "}`, http.StatusBadRequest},
+	}
+	for _, test := range tests {
+		if rec := requestCloneSchedule(source.Id.Hex(), test.body); rec.Code != test.code {
+			t.Errorf("%s : "+TestUnexpectedMsgFormatStrForIntVal, test.body, rec.Code, test.code)
+		}
+	}
+	if _, err := queryScheduleByName("monthly"); err == nil {
+		t.Error("a clone with a taken event name should not be added")
+	}
+	if rec := requestCloneSchedule(bson.NewObjectId().Hex(), `{"name":"yearly"}`); rec.Code != http.StatusNotFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestReplyCloneScheduleRejectsAFiredOneOff(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+	msc = &fakeScheduleClient{}
+	defer func() { msc = nil }()
+
+	source := models.Schedule{Id: bson.NewObjectId(), Name: "launch", Start: "20180102T000000"}
+	if err := addSchedule(source); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	fake.Advance(48 * time.Hour)
+
+	if rec := requestCloneSchedule(source.Id.Hex(), `{"name":"relaunch"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusBadRequest)
+	}
+	if _, err := queryScheduleByName("relaunch"); err == nil {
+		t.Error("the clone of a fired one-off schedule should not be added")
+	}
+}
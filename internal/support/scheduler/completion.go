@@ -0,0 +1,70 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CompletionNotification is posted to the CompletionURL when a schedule will not fire anymore
+type CompletionNotification struct {
+	ScheduleId string `json:"scheduleId"`
+	Name       string `json:"name"`
+	Iterations int64  `json:"iterations"`
+	Reason     string `json:"reason"` // the stop condition which completed the schedule
+}
+
+// the final execution is not counted in the iterations of a complete schedule, so its fire number is passed in
+func newCompletionNotification(context *ScheduleContext, iterations int64) CompletionNotification {
+	return CompletionNotification{
+		ScheduleId: context.Schedule.Id.Hex(),
+		Name:       context.Schedule.Name,
+		Iterations: iterations,
+		Reason:     context.CompletionReason(),
+	}
+}
+
+// the configured completion sink, empty when the completions are only logged
+func completionUrl() string {
+	if Configuration == nil {
+		return ""
+	}
+	return Configuration.CompletionURL
+}
+
+// Post the completion of a schedule to the completion sink, failures are logged and not retried
+func sendCompletionNotification(url string, notification CompletionNotification, correlationId string) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		LoggingClient.Error(executionLogMsg(correlationId, "unable to encode the completion notification : "+err.Error()), correlationId)
+		return err
+	}
+
+	if isDryRun() {
+		LoggingClient.Info(executionLogMsg(correlationId, fmt.Sprintf("dry run, the completion of schedule %s would be posted to %s with body : %s", notification.Name, url, body)), correlationId)
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		LoggingClient.Error(executionLogMsg(correlationId, "invalid completion url : "+err.Error()), correlationId)
+		return err
+	}
+	req.Header.Set(ContentTypeKey, ContentTypeJsonValue)
+	req.Header.Set(CorrelationHeader, correlationId)
+	req.Header.Set(UserAgentKey, userAgent())
+
+	_, statusCode, err := sendRequestAndGetResponse(getHTTPClient(), req)
+	if err != nil {
+		LoggingClient.Error(executionLogMsg(correlationId, fmt.Sprintf("unable to notify the completion of schedule %s : %s", notification.Name, err.Error())), correlationId)
+		return err
+	}
+	LoggingClient.Debug(executionLogMsg(correlationId, fmt.Sprintf("notified the completion of schedule %s, status code : %d", notification.Name, statusCode)), correlationId)
+	return nil
+}
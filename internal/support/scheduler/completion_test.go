@@ -0,0 +1,94 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestCompletionNotificationSentOnce(t *testing.T) {
+	resetScheduler()
+	notifications := make(chan CompletionNotification, 10)
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification CompletionNotification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			t.Errorf("unexpected error decoding the notification : %s", err.Error())
+		}
+		notifications <- notification
+	}))
+	defer sink.Close()
+
+	Configuration.CompletionURL = sink.URL
+	defer func() { Configuration.CompletionURL = "" }()
+
+	schedule := models.Schedule{
+		Id:      bson.NewObjectId(),
+		Name:    TestScheduleName,
+		Start:   time.Now().Add(-time.Minute).UTC().Format(TIMELAYOUT),
+		RunOnce: true,
+	}
+	if err := addSchedule(schedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	for i := 0; i < 3; i++ {
+		triggerSchedule()
+		waitForExecutions()
+	}
+
+	select {
+	case notification := <-notifications:
+		if notification.ScheduleId != schedule.Id.Hex() {
+			t.Errorf(TestUnexpectedMsgFormatStr, notification.ScheduleId, schedule.Id.Hex())
+		}
+		if notification.Name != TestScheduleName {
+			t.Errorf(TestUnexpectedMsgFormatStr, notification.Name, TestScheduleName)
+		}
+		if notification.Iterations != 1 {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, notification.Iterations, 1)
+		}
+		if notification.Reason != CompletedRunOnce {
+			t.Errorf(TestUnexpectedMsgFormatStr, notification.Reason, CompletedRunOnce)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the completion was not notified")
+	}
+
+	select {
+	case <-notifications:
+		t.Error("the completion should only be notified once")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestNoCompletionNotificationForRepeatingSchedule(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.CompletionURL = "http://localhost:48081/completion"
+	defer func() { Configuration.CompletionURL = "" }()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	executeSchedule(schedule.Id.Hex())
+	time.Sleep(50 * time.Millisecond)
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if len(client.requests) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+}
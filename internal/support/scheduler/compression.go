@@ -0,0 +1,39 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Wrap the body of the response in a reader decoding its Content-Encoding, the bodies in an unknown encoding are
+// read as they are
+func decodedBody(resp *http.Response) (io.Reader, error) {
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get(ContentEncodingKey))) {
+	case GzipEncoding:
+		return gzip.NewReader(resp.Body)
+	case DeflateEncoding:
+		return zlib.NewReader(resp.Body)
+	}
+	return resp.Body, nil
+}
+
+func gzipBody(body string) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write([]byte(body)); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
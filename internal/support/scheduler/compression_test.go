@@ -0,0 +1,137 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestSendRequestDecodesCompressedResponses(t *testing.T) {
+	const payload = `{"status":"ok"}`
+
+	tests := []struct {
+		encoding string
+		encode   func(w *bytes.Buffer)
+	}{
+		{GzipEncoding, func(w *bytes.Buffer) {
+			writer := gzip.NewWriter(w)
+			writer.Write([]byte(payload))
+			writer.Close()
+		}},
+		{DeflateEncoding, func(w *bytes.Buffer) {
+			writer := zlib.NewWriter(w)
+			writer.Write([]byte(payload))
+			writer.Close()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var buffer bytes.Buffer
+				tt.encode(&buffer)
+				w.Header().Set(ContentEncodingKey, tt.encoding)
+				w.Write(buffer.Bytes())
+			}))
+			defer server.Close()
+
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			req.Header.Set(AcceptEncodingKey, GzipEncoding+", "+DeflateEncoding)
+			body, _, err := sendRequestAndGetResponse(&http.Client{}, req)
+			if err != nil {
+				t.Fatalf("unexpected error sending the request : %s", err.Error())
+			}
+			if string(body) != payload {
+				t.Errorf(TestUnexpectedMsgFormatStr, string(body), payload)
+			}
+		})
+	}
+}
+
+func TestSendRequestRejectsCorruptCompressedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ContentEncodingKey, GzipEncoding)
+		w.Write([]byte("not gzip"))
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set(AcceptEncodingKey, GzipEncoding)
+	if _, _, err := sendRequestAndGetResponse(&http.Client{}, req); err == nil {
+		t.Error("expected an error for the corrupt gzip body")
+	}
+}
+
+func TestCompressBodyEvent(t *testing.T) {
+	resetScheduler()
+	payload := `{"readings":"` + strings.Repeat("x", 64*1024) + `"}`
+
+	var mutex sync.Mutex
+	var encoding string
+	var received []byte
+	var receivedLength int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		encoding = r.Header.Get(ContentEncodingKey)
+		raw, _ := ioutil.ReadAll(r.Body)
+		receivedLength = len(raw)
+		if reader, err := gzip.NewReader(bytes.NewReader(raw)); err == nil {
+			received, _ = ioutil.ReadAll(reader)
+		}
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := models.ScheduleEvent{
+		Id:           bson.NewObjectId(),
+		Name:         TestScheduleEventName,
+		Schedule:     schedule.Name,
+		Parameters:   payload,
+		CompressBody: true,
+		Addressable: models.Addressable{
+			Name:       TestScheduleEventName,
+			Protocol:   "http",
+			HTTPMethod: http.MethodPost,
+			Address:    serverUrl.Hostname(),
+			Port:       port,
+			Path:       "/api/v1/event",
+		},
+	}
+	if err := addScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if encoding != GzipEncoding {
+		t.Errorf(TestUnexpectedMsgFormatStr, encoding, GzipEncoding)
+	}
+	if string(received) != payload {
+		t.Errorf("expected the server to decode the payload, got %d bytes", len(received))
+	}
+	if receivedLength >= len(payload) {
+		t.Errorf("expected the payload to be compressed, got %d bytes for %d", receivedLength, len(payload))
+	}
+}
@@ -4,9 +4,64 @@ import "github.com/edgexfoundry/edgex-go/internal/pkg/config"
 
 // Configuration V2 for the Support Scheduler Service
 type ConfigurationStruct struct {
-	ScheduleInterval int
+	ScheduleInterval        int
+	StateFile               string
+	MaxRetries              int
+	RetryBackoff            int
+	RetryServerErrors       bool
+	DeadLetterSize          int
+	MaxConcurrentExecutions int
+	MaxResponseBytes        int
+	DryRun                  bool
+	HistoryDepth            int
+	MaxRequestsPerSecond    int
+	MinIntervalMs           int
+	RejectBelowMinInterval  bool
+	TLSCAFile               string
+	TLSInsecureSkipVerify   bool
+	MaxIdleConnsPerHost     int
+	IdleConnTimeout         int
+	MaxQueueDepth           int
+	StartupSpreadMs         int
+	UserAgent               string
+	CompletionURL           string
+	MetadataRetries         int
+	MetadataRetryBackoff    int
+	AllowDegradedStart      bool
+	MaxCatchUpFires         int
+	LogBodies               bool
+	RedactHeaders           []string
+	RedactFields            []string
+	FailureThreshold        int
+	FailureCooldownMs       int
+	DefaultContentType      string
+	InstanceID              string
+	LeaderLeaseMs           int
+	IdempotencyHeader       string
+	EnableTracing           bool
+	ScheduleDir             string
+	ScheduleDirPollMs       int
+	SigningSecret           string
+	SignatureHeader         string
+	HTTPProxy               string
+	HTTPSProxy              string
+	NoProxy                 string
+	MaxRequestsPerHost      int
+	DedupeWindowMs          int
+	StartupDelayMs          int
+	BodySourceTTLMs         int
+	BodySourceDir           string
+	RemoveAfterFailureMs    int
+	RemoveFromMetadata      bool
+	GlobalHeaders           map[string]string
+	JSONLogging             bool
+	SuccessStatusCodes      string
+	OriginatorID            string
+	MaxDuePerTick           int
+	CallbackTimeoutMs       int
 
 	Clients   map[string]config.ClientInfo
+	Databases map[string]config.DatabaseInfo
 	Logging   config.LoggingInfo
 	Registry  config.RegistryInfo
 	Service   config.ServiceInfo
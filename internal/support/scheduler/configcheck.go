@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Check the configured schedules and schedule events before any of them is loaded, reporting all the problems
+// found at once. The events may refer to the configured schedules and to the ones already in the scheduler.
+func validateConfig(schedules map[string]config.ScheduleInfo, scheduleEvents map[string]config.ScheduleEventInfo) error {
+	var problems []string
+	scheduleNames := make(map[string]bool, len(schedules))
+
+	//the keys are sorted so the problems are always reported in the same order
+	scheduleKeys := make([]string, 0, len(schedules))
+	for key := range schedules {
+		scheduleKeys = append(scheduleKeys, key)
+	}
+	sort.Strings(scheduleKeys)
+
+	for _, key := range scheduleKeys {
+		schedule := schedules[key]
+		if schedule.Name == "" {
+			problems = append(problems, fmt.Sprintf("the schedule %q has no name", key))
+			continue
+		}
+		if scheduleNames[schedule.Name] {
+			problems = append(problems, fmt.Sprintf("the schedule name %q is used more than once", schedule.Name))
+		}
+		scheduleNames[schedule.Name] = true
+
+		//a one-off schedule fires once at its start, the others need exactly one of a frequency or a cron expression
+		if schedule.Frequency != "" && schedule.Cron != "" {
+			problems = append(problems, fmt.Sprintf("the schedule %q has both a frequency and a cron expression", schedule.Name))
+		} else if schedule.Frequency == "" && schedule.Cron == "" && !schedule.RunOnce && schedule.Start == "" {
+			problems = append(problems, fmt.Sprintf("the schedule %q has neither a frequency nor a cron expression", schedule.Name))
+		} else if schedule.Cron != "" {
+			if _, err := parseCron(schedule.Cron); err != nil {
+				problems = append(problems, fmt.Sprintf("the schedule %q has an invalid cron expression %q : %s", schedule.Name, schedule.Cron, err.Error()))
+			}
+		}
+	}
+
+	eventKeys := make([]string, 0, len(scheduleEvents))
+	for key := range scheduleEvents {
+		eventKeys = append(eventKeys, key)
+	}
+	sort.Strings(eventKeys)
+
+	for _, key := range eventKeys {
+		scheduleEvent := scheduleEvents[key]
+		name := scheduleEvent.Name
+		if name == "" {
+			problems = append(problems, fmt.Sprintf("the schedule event %q has no name", key))
+			name = key
+		}
+		addressable := models.Addressable{Protocol: scheduleEvent.Protocol}
+		if !isMQTTAddressable(addressable) && !validMethod(scheduleEvent.Method) {
+			problems = append(problems, fmt.Sprintf("the schedule event %q has an invalid http method %q", name, scheduleEvent.Method))
+		}
+		if err := validateSuccessStatus(models.ScheduleEvent{Name: name, SuccessStatus: scheduleEvent.SuccessStatus}); err != nil {
+			problems = append(problems, err.Error())
+		}
+		callbackEvent := scheduleEventFromConfig(scheduleEvent)
+		callbackEvent.Name = name
+		if err := validateCallbacks(callbackEvent); err != nil {
+			problems = append(problems, err.Error())
+		}
+		if err := validateBodySource(callbackEvent); err != nil {
+			problems = append(problems, err.Error())
+		}
+		if scheduleEvent.Schedule == "" {
+			problems = append(problems, fmt.Sprintf("the schedule event %q has no schedule", name))
+		} else if _, err := queryScheduleByName(scheduleEvent.Schedule); err != nil && !scheduleNames[scheduleEvent.Schedule] {
+			problems = append(problems, fmt.Sprintf("the schedule event %q refers to the unknown schedule %q", name, scheduleEvent.Schedule))
+		}
+	}
+
+	if len(problems) > 0 {
+		return ErrInvalidConfig{Problems: problems}
+	}
+	return nil
+}
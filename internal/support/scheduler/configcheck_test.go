@@ -0,0 +1,94 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestValidateConfigReportsAllProblems(t *testing.T) {
+	resetScheduler()
+
+	schedules := map[string]config.ScheduleInfo{
+		"Unnamed":  {Start: "20180101T000000", Frequency: "P1D"},
+		"BadCron":  {Name: "bad-cron", Cron: "@fortnightly"},
+		"Both":     {Name: "both", Start: "20180101T000000", Frequency: "P1D", Cron: "0 0 * * * *"},
+		"Neither":  {Name: "neither"},
+		"Midnight": {Name: "midnight", Start: "20180101T000000", Frequency: "P1D"},
+	}
+	scheduleEvents := map[string]config.ScheduleEventInfo{
+		"BadCallback": {Name: "bad-callback", Method: "POST", Protocol: "http", Schedule: "midnight", OnFailureURL: "ftp://alerts"},
+		"BadMethod":   {Name: "bad-method", Method: "FETCH", Protocol: "http", Schedule: "midnight"},
+		"BadStatus":   {Name: "bad-status", Method: "GET", Protocol: "http", Schedule: "midnight", SuccessStatus: "299-200"},
+		"Orphan":      {Name: "orphan", Method: "GET", Protocol: "http", Schedule: "nowhere"},
+		"Valid":       {Name: "valid", Method: "DELETE", Protocol: "http", Schedule: "midnight"},
+	}
+
+	err := validateConfig(schedules, scheduleEvents)
+	invalid, ok := err.(ErrInvalidConfig)
+	if !ok {
+		t.Fatalf("expected an invalid config error, got %v", err)
+	}
+
+	expected := []string{
+		`the schedule "bad-cron" has an invalid cron expression "@fortnightly" : unknown descriptor @fortnightly, expected one of @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly or @every <duration>`,
+		`the schedule "both" has both a frequency and a cron expression`,
+		`the schedule "neither" has neither a frequency nor a cron expression`,
+		`the schedule "Unnamed" has no name`,
+		`the schedule event "bad-callback" has an invalid failure callback "ftp://alerts", expected an http or https url`,
+		`the schedule event "bad-method" has an invalid http method "FETCH"`,
+		`the schedule event "bad-status" has invalid success statuses "299-200" : the status range "299-200" ends before it starts`,
+		`the schedule event "orphan" refers to the unknown schedule "nowhere"`,
+	}
+	if strings.Join(invalid.Problems, "\n") != strings.Join(expected, "\n") {
+		t.Errorf(TestUnexpectedMsgFormatStr, strings.Join(invalid.Problems, "; "), strings.Join(expected, "; "))
+	}
+}
+
+func TestValidateConfigAcceptsSchedulesOfTheScheduler(t *testing.T) {
+	resetScheduler()
+	addTestSchedule(t, TestScheduleName)
+
+	scheduleEvents := map[string]config.ScheduleEventInfo{
+		"Ping":    {Name: "ping", Method: "GET", Protocol: "http", Schedule: TestScheduleName},
+		"Publish": {Name: "publish", Protocol: "mqtt", Schedule: TestScheduleName},
+	}
+	if err := validateConfig(nil, scheduleEvents); err != nil {
+		t.Errorf("unexpected error : %s", err.Error())
+	}
+}
+
+func TestAddSchedulersLoadsNothingFromAnInvalidConfig(t *testing.T) {
+	resetScheduler()
+	Configuration.AllowDegradedStart = true
+	Configuration.Schedules = map[string]config.ScheduleInfo{
+		"Midnight": {Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D"},
+		"Neither":  {Name: "neither"},
+	}
+	defer func() {
+		Configuration.AllowDegradedStart = false
+		Configuration.Schedules = nil
+	}()
+
+	msc = &fakeScheduleClient{schedules: []models.Schedule{{Id: bson.NewObjectId(), Name: "metadata", Start: "20180101T000000", Frequency: "P1D"}}}
+	msec = &fakeScheduleEventClient{}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	if err := AddSchedulers(); err == nil {
+		t.Fatal("expected an error for the invalid config")
+	}
+	if _, err := queryScheduleByName(TestScheduleName); err == nil {
+		t.Error("expected the valid schedule of the config not to be loaded")
+	}
+}
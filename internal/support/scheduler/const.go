@@ -28,4 +28,32 @@ const (
 	ContentTypeKey       = "Content-Type"
 	ContentTypeJsonValue = "application/json; charset=utf-8"
 	ContentLengthKey     = "Content-Length"
+	ContentEncodingKey   = "Content-Encoding"
+	AcceptEncodingKey    = "Accept-Encoding"
+	CorrelationHeader    = "X-Correlation-ID"
+	UserAgentKey         = "User-Agent"
+	TraceParentHeader    = "traceparent"
+	RetryAfterHeader     = "Retry-After"
+
+	GzipEncoding    = "gzip"
+	DeflateEncoding = "deflate"
+
+	HTTPProtocol  = "HTTP"
+	HTTPSProtocol = "HTTPS"
+
+	MissedFireSkip    = "skip"
+	MissedFireOnce    = "fire-once"
+	MissedFireCatchUp = "catch-up"
+
+	// how the interval of a schedule changes after each fire
+	IntervalConstant       = "constant"
+	IntervalMultiplicative = "multiplicative"
+
+	// the stop conditions completing a schedule, in the order they are checked
+	CompletedRunOnce       = "run-once"
+	CompletedMaxIterations = "max-iterations"
+	CompletedEnd           = "end"
+
+	// bound on the missed cron fires counted after a downtime
+	MaxMissedCronFires = 10000
 )
@@ -0,0 +1,51 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+)
+
+// the descriptors accepted in place of the cron fields, besides @every <duration>
+var cronDescriptors = map[string]bool{
+	"@yearly":   true,
+	"@annually": true,
+	"@monthly":  true,
+	"@weekly":   true,
+	"@daily":    true,
+	"@midnight": true,
+	"@hourly":   true,
+}
+
+// Parse the cron expression of a schedule, either six cron fields starting with the seconds or a descriptor.
+// The descriptors are case insensitive, @every takes a Go duration of at least one second.
+func parseCron(expression string) (cron.Schedule, error) {
+	expression = strings.TrimSpace(expression)
+	if !strings.HasPrefix(expression, "@") {
+		return cron.Parse(expression)
+	}
+
+	descriptor := strings.ToLower(expression)
+	if descriptor == "@every" || strings.HasPrefix(descriptor, "@every ") {
+		interval := strings.TrimSpace(expression[len("@every"):])
+		duration, err := time.ParseDuration(interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid @every interval %q, expected a duration such as 30s or 1h30m", interval)
+		}
+		if duration < time.Second {
+			return nil, fmt.Errorf("the @every interval %s is shorter than one second", duration)
+		}
+		return cron.Every(duration), nil
+	}
+	if !cronDescriptors[descriptor] {
+		return nil, fmt.Errorf("unknown descriptor %s, expected one of @yearly, @annually, @monthly, @weekly, @daily, @midnight, @hourly or @every <duration>", expression)
+	}
+	return cron.Parse(descriptor)
+}
@@ -0,0 +1,84 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestCronDescriptors(t *testing.T) {
+	// a Wednesday
+	after := time.Date(2018, 1, 3, 10, 20, 30, 0, time.UTC)
+	tests := []struct {
+		cron     string
+		expected time.Time
+	}{
+		{"@hourly", time.Date(2018, 1, 3, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2018, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"@midnight", time.Date(2018, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2018, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2018, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@annually", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 30s", time.Date(2018, 1, 3, 10, 21, 0, 0, time.UTC)},
+		{"@every 1h30m", time.Date(2018, 1, 3, 11, 50, 30, 0, time.UTC)},
+		{"@Daily", time.Date(2018, 1, 4, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		testScheduleContext := ScheduleContext{}
+		if err := testScheduleContext.Reset(models.Schedule{Name: TestScheduleName, Cron: test.cron}); err != nil {
+			t.Errorf("unexpected error for %s : %s", test.cron, err.Error())
+			continue
+		}
+		if next := testScheduleContext.nextCronTime(after); !next.Equal(test.expected) {
+			t.Errorf("%s : "+TestUnexpectedMsgFormatStr, test.cron, next, test.expected)
+		}
+	}
+}
+
+func TestCronDescriptorsFollowTheTimezone(t *testing.T) {
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(models.Schedule{Name: TestScheduleName, Cron: "@midnight", Timezone: "Asia/Shanghai"}); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	next := testScheduleContext.nextCronTime(time.Date(2018, 1, 3, 10, 0, 0, 0, time.UTC))
+	expected := time.Date(2018, 1, 3, 16, 0, 0, 0, time.UTC)
+	if !next.Equal(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, next, expected)
+	}
+}
+
+func TestInvalidCronDescriptors(t *testing.T) {
+	tests := []struct {
+		cron     string
+		contains string
+	}{
+		{"@fortnightly", "unknown descriptor @fortnightly"},
+		{"@everyday", "unknown descriptor @everyday"},
+		{"@every", "invalid @every interval"},
+		{"@every soon", "invalid @every interval"},
+		{"@every 500ms", "shorter than one second"},
+		{"@every -1m", "shorter than one second"},
+	}
+
+	for _, test := range tests {
+		testScheduleContext := ScheduleContext{}
+		err := testScheduleContext.Reset(models.Schedule{Name: TestScheduleName, Cron: test.cron})
+		if err == nil {
+			t.Errorf("expected the cron expression %s to be rejected", test.cron)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.contains) {
+			t.Errorf(TestUnexpectedMsgFormatStr, err.Error(), test.contains)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DefaultDeadLetterSize is the number of dead letters kept when DeadLetterSize is not configured
+const DefaultDeadLetterSize = 100
+
+// DeadLetter is a schedule event whose execution still failed after all of its retries
+type DeadLetter struct {
+	ScheduleEvent models.ScheduleEvent `json:"scheduleEvent"`
+	CorrelationId string               `json:"correlationId"`
+	Time          int64                `json:"time"` // milliseconds since the epoch
+	Attempts      int                  `json:"attempts"`
+	Error         string               `json:"error"`
+}
+
+// ring buffer of the latest dead letters, the oldest one is overwritten once it is full
+var (
+	deadLetterMutex sync.Mutex
+	deadLetters     []DeadLetter
+	deadLetterNext  int
+	deadLetterCount int
+)
+
+func deadLetterSize() int {
+	if Configuration == nil || Configuration.DeadLetterSize <= 0 {
+		return DefaultDeadLetterSize
+	}
+	return Configuration.DeadLetterSize
+}
+
+func addDeadLetter(scheduleEvent models.ScheduleEvent, correlationId string, attempts int, err error) {
+	deadLetter := DeadLetter{
+		ScheduleEvent: scheduleEvent,
+		CorrelationId: correlationId,
+		Time:          clock.Now().UnixNano() / int64(time.Millisecond),
+		Attempts:      attempts,
+		Error:         err.Error(),
+	}
+
+	deadLetterMutex.Lock()
+	defer deadLetterMutex.Unlock()
+
+	if size := deadLetterSize(); len(deadLetters) != size {
+		//the size changed, start over with an empty buffer
+		deadLetters = make([]DeadLetter, size)
+		deadLetterNext = 0
+		deadLetterCount = 0
+	}
+
+	deadLetters[deadLetterNext] = deadLetter
+	deadLetterNext = (deadLetterNext + 1) % len(deadLetters)
+	if deadLetterCount < len(deadLetters) {
+		deadLetterCount++
+	}
+
+	LoggingClient.Error(executionLogMsg(correlationId, "the event with id : "+scheduleEvent.Id.Hex()+" has been dead lettered : "+deadLetter.Error), correlationId)
+}
+
+// Return the dead letters from the oldest to the newest
+func queryDeadLetters() []DeadLetter {
+	deadLetterMutex.Lock()
+	defer deadLetterMutex.Unlock()
+
+	result := make([]DeadLetter, 0, deadLetterCount)
+	for i := 0; i < deadLetterCount; i++ {
+		index := (deadLetterNext - deadLetterCount + i + len(deadLetters)) % len(deadLetters)
+		result = append(result, deadLetters[index])
+	}
+	return result
+}
+
+func clearDeadLetters() {
+	deadLetterMutex.Lock()
+	defer deadLetterMutex.Unlock()
+
+	deadLetters = nil
+	deadLetterNext = 0
+	deadLetterCount = 0
+}
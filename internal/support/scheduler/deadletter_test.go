@@ -0,0 +1,106 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// failingHTTPClient fails every request it receives
+type failingHTTPClient struct {
+	calls int32
+}
+
+func (c *failingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&c.calls, 1)
+	return nil, errors.New("connection refused")
+}
+
+func TestDeadLetterAfterRetries(t *testing.T) {
+	resetScheduler()
+	clearDeadLetters()
+	client := &failingHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.MaxRetries = 2
+	Configuration.RetryBackoff = 1
+	defer func() {
+		Configuration.MaxRetries = 0
+		Configuration.RetryBackoff = 0
+	}()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	if err := executeSchedule(schedule.Id.Hex()); err == nil {
+		t.Error("expected an error from the failing event")
+	}
+
+	if client.calls != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, client.calls, 3)
+	}
+
+	deadLetters := queryDeadLetters()
+	if len(deadLetters) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(deadLetters), 1)
+	}
+	deadLetter := deadLetters[0]
+	if deadLetter.ScheduleEvent.Id != scheduleEvent.Id {
+		t.Errorf(TestUnexpectedMsgFormatStr, deadLetter.ScheduleEvent.Id.Hex(), scheduleEvent.Id.Hex())
+	}
+	if deadLetter.Attempts != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, deadLetter.Attempts, 3)
+	}
+	if deadLetter.Error == "" || deadLetter.CorrelationId == "" {
+		t.Errorf("expected the dead letter to hold the final error and the correlation id, got %+v", deadLetter)
+	}
+}
+
+func TestNoDeadLetterOnSuccess(t *testing.T) {
+	resetScheduler()
+	clearDeadLetters()
+	SetHTTPClient(&mockHTTPClient{})
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	executeSchedule(schedule.Id.Hex())
+
+	if deadLetters := queryDeadLetters(); len(deadLetters) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(deadLetters), 0)
+	}
+}
+
+func TestDeadLetterOverwritesOldest(t *testing.T) {
+	clearDeadLetters()
+	Configuration.DeadLetterSize = 2
+	defer func() { Configuration.DeadLetterSize = 0 }()
+
+	var scheduleEvents []models.ScheduleEvent
+	for i := 0; i < 3; i++ {
+		scheduleEvent := models.ScheduleEvent{Id: bson.NewObjectId()}
+		scheduleEvents = append(scheduleEvents, scheduleEvent)
+		addDeadLetter(scheduleEvent, "", 1, errors.New("failed"))
+	}
+
+	deadLetters := queryDeadLetters()
+	if len(deadLetters) != 2 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(deadLetters), 2)
+	}
+	for i, deadLetter := range deadLetters {
+		if deadLetter.ScheduleEvent.Id != scheduleEvents[i+1].Id {
+			t.Errorf(TestUnexpectedMsgFormatStr, deadLetter.ScheduleEvent.Id.Hex(), scheduleEvents[i+1].Id.Hex())
+		}
+	}
+}
@@ -0,0 +1,93 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Decommission a schedule, deleting it and its events from core-metadata before they leave the scheduler so a
+// reload or a restart does not bring them back. A schedule of the configuration is still added back by the next
+// load unless it is removed from the configuration as well.
+func deleteSchedule(scheduleId string) error {
+	mutex.Lock()
+	scheduleContext, exists := scheduleIdToContextMap[scheduleId]
+	if !exists {
+		mutex.Unlock()
+		return fmt.Errorf("scheduler could not find schedule context with schedule id : %s", scheduleId)
+	}
+	schedule := scheduleContext.Schedule
+	scheduleEvents := orderedScheduleEvents(scheduleContext.ScheduleEventsMap)
+	mutex.Unlock()
+
+	scheduleIds, scheduleEventIds := scheduleFileIds()
+	if !scheduleIds[scheduleId] && storesInCoreMetadata() {
+		for _, scheduleEvent := range scheduleEvents {
+			if scheduleEventIds[scheduleEvent.Id.Hex()] {
+				continue
+			}
+			if err := deleteScheduleEventFromCoreMetadata(scheduleEvent); err != nil {
+				return err
+			}
+		}
+		//core-metadata refuses to delete a schedule still used by events, they are gone by now
+		if err := msc.Delete(scheduleId); err != nil && !isMetadataNotFound(err) {
+			LoggingClient.Error(fmt.Sprintf("error deleting the schedule %s from core-metadata : %s", schedule.Name, err.Error()))
+			return err
+		}
+		LoggingClient.Info(fmt.Sprintf("deleted the schedule %s from core-metadata", schedule.Name))
+	}
+
+	return removeSchedule(scheduleId)
+}
+
+// Decommission a schedule event, deleting it from core-metadata before it leaves the scheduler
+func deleteScheduleEvent(scheduleEventId string) error {
+	scheduleEvent, err := queryScheduleEvent(scheduleEventId)
+	if err != nil {
+		return err
+	}
+
+	_, scheduleEventIds := scheduleFileIds()
+	if !scheduleEventIds[scheduleEventId] && storesInCoreMetadata() {
+		if err := deleteScheduleEventFromCoreMetadata(scheduleEvent); err != nil {
+			return err
+		}
+	}
+
+	return removeScheduleEvent(scheduleEventId)
+}
+
+func deleteScheduleEventFromCoreMetadata(scheduleEvent models.ScheduleEvent) error {
+	if err := msec.Delete(scheduleEvent.Id.Hex()); err != nil && !isMetadataNotFound(err) {
+		LoggingClient.Error(fmt.Sprintf("error deleting the schedule event %s from core-metadata : %s", scheduleEvent.Name, err.Error()))
+		return err
+	}
+	LoggingClient.Info(fmt.Sprintf("deleted the schedule event %s from core-metadata", scheduleEvent.Name))
+	return nil
+}
+
+// In degraded mode the deletes only reach the scheduler, core-metadata brings the schedules back once it is reachable
+func storesInCoreMetadata() bool {
+	if msc == nil || msec == nil {
+		return false
+	}
+	if isDegradedMode() {
+		LoggingClient.Warn("core-metadata is unavailable, the delete only applies until the next load")
+		return false
+	}
+	return true
+}
+
+// what core-metadata does not have is as good as deleted
+func isMetadataNotFound(err error) bool {
+	serviceErr, ok := err.(*types.ErrServiceClient)
+	return ok && serviceErr.StatusCode == http.StatusNotFound
+}
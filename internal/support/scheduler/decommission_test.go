@@ -0,0 +1,100 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func serveDelete(path string) int {
+	req := httptest.NewRequest(http.MethodDelete, path, nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// serve a schedule with two events from core-metadata and load them
+func loadDecommissionTestSchedule(t *testing.T) (*fakeScheduleClient, *fakeScheduleEventClient, models.Schedule, func()) {
+	scheduleClient, scheduleEventClient, _, restore := useFakeMetadataClients()
+	schedule := models.Schedule{Id: bson.NewObjectId(), Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D"}
+	scheduleClient.schedules = []models.Schedule{schedule}
+	scheduleEventClient.scheduleEvents = []models.ScheduleEvent{
+		{Id: bson.NewObjectId(), Name: "first", Schedule: schedule.Name, Service: "core-data"},
+		{Id: bson.NewObjectId(), Name: "second", Schedule: schedule.Name, Service: "core-data"},
+	}
+	if err := loadCoreMetadataInformation(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	return scheduleClient, scheduleEventClient, schedule, restore
+}
+
+func TestDeleteScheduleRemovesItFromCoreMetadata(t *testing.T) {
+	resetScheduler()
+	scheduleClient, scheduleEventClient, schedule, restore := loadDecommissionTestSchedule(t)
+	defer restore()
+
+	if code := serveDelete("/api/v1/schedule/" + schedule.Id.Hex()); code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusOK)
+	}
+	if len(scheduleClient.schedules) != 0 || len(scheduleEventClient.scheduleEvents) != 0 {
+		t.Errorf("expected the schedule and its events to be deleted from core-metadata, left %v %v", scheduleClient.schedules, scheduleEventClient.scheduleEvents)
+	}
+
+	//the next load does not bring the schedule back
+	if err := loadCoreMetadataInformation(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if _, err := querySchedule(schedule.Id.Hex()); err == nil {
+		t.Error("the deleted schedule was loaded again")
+	}
+}
+
+func TestDeleteScheduleEventRemovesItFromCoreMetadata(t *testing.T) {
+	resetScheduler()
+	_, scheduleEventClient, _, restore := loadDecommissionTestSchedule(t)
+	defer restore()
+
+	first := scheduleEventClient.scheduleEvents[0]
+	if code := serveDelete("/api/v1/scheduleevent/" + first.Id.Hex()); code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusOK)
+	}
+	if len(scheduleEventClient.scheduleEvents) != 1 || scheduleEventClient.scheduleEvents[0].Name != "second" {
+		t.Errorf("unexpected core-metadata schedule events %v", scheduleEventClient.scheduleEvents)
+	}
+
+	if err := loadCoreMetadataInformation(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if _, err := queryScheduleEventByName("first"); err == nil {
+		t.Error("the deleted schedule event was loaded again")
+	}
+	if _, err := queryScheduleEventByName("second"); err != nil {
+		t.Errorf("the other schedule event should still be loaded : %s", err.Error())
+	}
+}
+
+func TestDeleteKeepsTheScheduleWhenCoreMetadataFails(t *testing.T) {
+	resetScheduler()
+	_, scheduleEventClient, schedule, restore := loadDecommissionTestSchedule(t)
+	defer restore()
+	scheduleEventClient.err = errors.New("core-metadata is unreachable")
+
+	if code := serveDelete("/api/v1/schedule/" + schedule.Id.Hex()); code != http.StatusServiceUnavailable {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusServiceUnavailable)
+	}
+	if _, err := querySchedule(schedule.Id.Hex()); err != nil {
+		t.Errorf("the schedule should be kept : %s", err.Error())
+	}
+	if code := serveDelete("/api/v1/schedule/" + bson.NewObjectId().Hex()); code != http.StatusNotFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusNotFound)
+	}
+}
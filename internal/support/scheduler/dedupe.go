@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// an outbound request shared by the identical events of the schedules firing together
+type dedupeCall struct {
+	schedule     string // the schedule whose event sent the request
+	sentAt       time.Time
+	done         chan struct{}
+	responseBody []byte
+	statusCode   int
+	err          error
+}
+
+var (
+	dedupeMutex sync.Mutex
+	dedupeCalls = make(map[string]*dedupeCall) // map : request key -> latest call
+)
+
+// the window within which the identical requests of different schedules are sent once, 0 turns it off
+func dedupeWindow() time.Duration {
+	if Configuration == nil || Configuration.DedupeWindowMs <= 0 {
+		return 0
+	}
+	return time.Duration(Configuration.DedupeWindowMs) * time.Millisecond
+}
+
+// the requests are identical when their method, URL and body are
+func dedupeKey(req *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(req.Method + " " + req.URL.String() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Send the request unless an identical request of another schedule was sent within the dedupe window, the
+// outcome of that request is then shared. The requests of the same schedule are never collapsed so the events
+// repeated on purpose and the retries are still sent, neither is a request whose shared call already failed.
+// Reports whether the outcome was shared.
+func sendDeduplicated(ctx context.Context, key string, schedule string, send func() ([]byte, int, error)) ([]byte, int, error, bool) {
+	window := dedupeWindow()
+	if window <= 0 {
+		responseBody, statusCode, err := send()
+		return responseBody, statusCode, err, false
+	}
+
+	now := clock.Now()
+	dedupeMutex.Lock()
+	pruneDedupeCallsLocked(now, window)
+	if call, exists := dedupeCalls[key]; exists && call.schedule != schedule && now.Sub(call.sentAt) <= window && !call.failed() {
+		dedupeMutex.Unlock()
+		select {
+		case <-call.done:
+			return call.responseBody, call.statusCode, call.err, true
+		case <-ctx.Done():
+			return []byte{}, 0, ctx.Err(), true
+		}
+	}
+	call := &dedupeCall{schedule: schedule, sentAt: now, done: make(chan struct{})}
+	dedupeCalls[key] = call
+	dedupeMutex.Unlock()
+
+	call.responseBody, call.statusCode, call.err = send()
+	close(call.done)
+	return call.responseBody, call.statusCode, call.err, false
+}
+
+// a call still in flight has not failed yet
+func (c *dedupeCall) failed() bool {
+	select {
+	case <-c.done:
+		return c.err != nil
+	default:
+		return false
+	}
+}
+
+// drop the completed calls past the window, the caller holds the dedupe mutex
+func pruneDedupeCallsLocked(now time.Time, window time.Duration) {
+	for key, call := range dedupeCalls {
+		select {
+		case <-call.done:
+			if now.Sub(call.sentAt) > window {
+				delete(dedupeCalls, key)
+			}
+		default:
+		}
+	}
+}
+
+func clearDedupeCalls() {
+	dedupeMutex.Lock()
+	defer dedupeMutex.Unlock()
+	dedupeCalls = make(map[string]*dedupeCall)
+}
@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// two schedules with the same POST event firing at the same tick
+func fireIdenticalEvents(t *testing.T, dedupeWindowMs int) *mockHTTPClient {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC))
+	defer restore()
+	Configuration = &ConfigurationStruct{DedupeWindowMs: dedupeWindowMs}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	first := addTestSchedule(t, "first")
+	addTestScheduleEvent(t, first, "first-refresh", http.MethodPost, "/api/v1/refresh", `{"scope":"all"}`)
+	second := addTestSchedule(t, "second")
+	addTestScheduleEvent(t, second, "second-refresh", http.MethodPost, "/api/v1/refresh", `{"scope":"all"}`)
+
+	fake.Advance(2 * time.Second)
+	triggerSchedule()
+	waitForExecutions()
+	return client
+}
+
+func TestDedupeIdenticalEventsAcrossSchedules(t *testing.T) {
+	client := fireIdenticalEvents(t, 1000)
+	if len(client.requests) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	//both events succeeded with the shared response
+	for _, name := range []string{"first-refresh", "second-refresh"} {
+		lastRun, err := queryLastRun(scheduleEventNameToScheduleEventIdMap[name])
+		if err != nil || lastRun.StatusCode != http.StatusOK {
+			t.Errorf("expected the event %s to share the successful response, got %v", name, lastRun)
+		}
+	}
+}
+
+func TestDedupeIsOffByDefault(t *testing.T) {
+	client := fireIdenticalEvents(t, 0)
+	if len(client.requests) != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 2)
+	}
+}
+
+func TestDedupeKeepsTheRepeatedEventsOfASchedule(t *testing.T) {
+	resetScheduler()
+	Configuration = &ConfigurationStruct{DedupeWindowMs: 1000}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, "refresh", http.MethodPost, "/api/v1/refresh", `{"scope":"all"}`)
+	addTestScheduleEvent(t, schedule, "refresh-again", http.MethodPost, "/api/v1/refresh", `{"scope":"all"}`)
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	if len(client.requests) != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 2)
+	}
+}
+
+func TestDedupeDoesNotShareFailures(t *testing.T) {
+	clearDedupeCalls()
+	Configuration = &ConfigurationStruct{DedupeWindowMs: 1000}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+
+	sends := 0
+	send := func() ([]byte, int, error) {
+		sends++
+		return []byte{}, http.StatusServiceUnavailable, ErrServerResponse{StatusCode: http.StatusServiceUnavailable}
+	}
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:48080/api/v1/ping", nil)
+	key := dedupeKey(req, nil)
+	sendDeduplicated(req.Context(), key, "first", send)
+	if _, _, _, shared := sendDeduplicated(req.Context(), key, "second", send); shared {
+		t.Error("expected a failed request to be sent again")
+	}
+	if sends != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, sends, 2)
+	}
+}
@@ -0,0 +1,47 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// set when the scheduler started without core-metadata and only runs the schedules of its configuration,
+// guarded by the schedule mutex
+var degradedMode bool
+
+func setDegradedMode(degraded bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	degradedMode = degraded
+}
+
+func isDegradedMode() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return degradedMode
+}
+
+// Load the schedules from core-metadata, retrying with a doubling backoff while it is unavailable
+func loadCoreMetadataInformationWithRetries() error {
+	retries := 0
+	backoff := time.Duration(0)
+	if Configuration != nil {
+		retries = Configuration.MetadataRetries
+		backoff = time.Duration(Configuration.MetadataRetryBackoff) * time.Millisecond
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = loadCoreMetadataInformation(); err == nil || attempt >= retries {
+			return err
+		}
+		LoggingClient.Warn(fmt.Sprintf("core-metadata is unavailable, retrying in %s (%d of %d) : %s", backoff, attempt+1, retries, err.Error()))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
@@ -0,0 +1,107 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestAddSchedulersRetriesCoreMetadata(t *testing.T) {
+	resetScheduler()
+	Configuration.MetadataRetries = 3
+	Configuration.MetadataRetryBackoff = 1
+	defer func() {
+		Configuration.MetadataRetries = 0
+		Configuration.MetadataRetryBackoff = 0
+	}()
+
+	schedule := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      TestScheduleName,
+		Start:     "20180101T000000",
+		Frequency: "P1D",
+	}
+	scheduleClient := &fakeScheduleClient{schedules: []models.Schedule{schedule}, failures: 2}
+	msc = scheduleClient
+	msec = &fakeScheduleEventClient{}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	if err := AddSchedulers(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if scheduleClient.calls != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleClient.calls, 3)
+	}
+	if _, err := querySchedule(schedule.Id.Hex()); err != nil {
+		t.Errorf("the schedule of core-metadata should be loaded : %s", err.Error())
+	}
+	if checkHealth().Degraded {
+		t.Error("the scheduler should not be degraded")
+	}
+}
+
+func TestAddSchedulersFailsWhenCoreMetadataStaysUnavailable(t *testing.T) {
+	resetScheduler()
+	Configuration.MetadataRetries = 2
+	defer func() { Configuration.MetadataRetries = 0 }()
+
+	scheduleClient := &fakeScheduleClient{err: errors.New("core-metadata is unreachable")}
+	msc = scheduleClient
+	msec = &fakeScheduleEventClient{}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	if err := AddSchedulers(); err == nil {
+		t.Error("expected an error when core-metadata stays unavailable")
+	}
+	if scheduleClient.calls != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleClient.calls, 3)
+	}
+}
+
+func TestAddSchedulersDegradedStart(t *testing.T) {
+	resetScheduler()
+	Configuration.AllowDegradedStart = true
+	Configuration.Schedules = map[string]config.ScheduleInfo{
+		"Midnight": {Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D"},
+	}
+	Configuration.ScheduleEvents = map[string]config.ScheduleEventInfo{
+		"Ping": {Name: TestScheduleEventName, Schedule: TestScheduleName, Host: "localhost", Port: 48080, Protocol: "http", Method: "GET", Path: "/api/v1/ping"},
+	}
+	defer func() {
+		Configuration.AllowDegradedStart = false
+		Configuration.Schedules = nil
+		Configuration.ScheduleEvents = nil
+		setDegradedMode(false)
+	}()
+
+	//the schedule and event clients of core-metadata must not be used to add the config
+	msc = &fakeScheduleClient{err: errors.New("core-metadata is unreachable")}
+	defer func() { msc = nil }()
+
+	if err := AddSchedulers(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if !checkHealth().Degraded {
+		t.Error("the scheduler should report the degraded mode")
+	}
+	if _, err := queryScheduleByName(TestScheduleName); err != nil {
+		t.Errorf("the config schedule should be loaded : %s", err.Error())
+	}
+	if _, err := queryScheduleEventByName(TestScheduleEventName); err != nil {
+		t.Errorf("the config schedule event should be loaded : %s", err.Error())
+	}
+}
@@ -0,0 +1,66 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DrainStatus reports the state of the scheduler after a drain or a resume
+type DrainStatus struct {
+	Draining bool  `json:"draining"`
+	WaitedMs int64 `json:"waitedMs"` // time the drain waited for the executions in flight, 0 for a resume
+}
+
+// guarded by the schedule mutex
+var draining bool
+
+// serializes the drains and resumes
+var drainMutex sync.Mutex
+
+func isDraining() bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return draining
+}
+
+// Stop the fires and wait for the executions in flight to finish, no schedule fires until the scheduler resumes.
+// The ticker keeps ticking so the scheduler stays healthy and keeps its leadership while it is drained.
+func drainScheduler() DrainStatus {
+	drainMutex.Lock()
+	defer drainMutex.Unlock()
+
+	mutex.Lock()
+	draining = true
+	mutex.Unlock()
+
+	LoggingClient.Info("draining the scheduler, waiting for the executions in flight")
+	start := time.Now()
+	waitForExecutions()
+	waited := time.Since(start)
+	LoggingClient.Info(fmt.Sprintf("drained the scheduler after %d ms", waited.Nanoseconds()/int64(time.Millisecond)))
+
+	return DrainStatus{Draining: true, WaitedMs: waited.Nanoseconds() / int64(time.Millisecond)}
+}
+
+// Let the schedules fire again from the next tick
+func resumeScheduler() DrainStatus {
+	drainMutex.Lock()
+	defer drainMutex.Unlock()
+
+	mutex.Lock()
+	wasDraining := draining
+	draining = false
+	mutex.Unlock()
+
+	if wasDraining {
+		LoggingClient.Info("resumed the scheduler after a drain")
+	}
+
+	return DrainStatus{Draining: false}
+}
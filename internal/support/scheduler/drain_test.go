@@ -0,0 +1,126 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func serveMaintenance(action string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/maintenance/"+action, nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeDrainStatus(t *testing.T, rec *httptest.ResponseRecorder) DrainStatus {
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	var status DrainStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("unexpected error decoding the drain status : %s", err.Error())
+	}
+	return status
+}
+
+func TestDrainWaitsForExecutionsInFlight(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	client := &hangingHTTPClient{calls: make(map[string]int), path: "/api/v1/hang", release: make(chan struct{})}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	hanging := addTestSchedule(t, "hanging")
+	addTestScheduleEvent(t, hanging, "hanging", http.MethodGet, "/api/v1/hang", "")
+	fast := addTestSchedule(t, "fast")
+	addTestScheduleEvent(t, fast, "fast", http.MethodGet, "/api/v1/ping", "")
+
+	fake.Advance(24 * time.Hour)
+	triggerSchedule()
+	waitForIterations(t, fast.Id.Hex(), 1)
+
+	drained := make(chan *httptest.ResponseRecorder)
+	go func() { drained <- serveMaintenance("drain") }()
+
+	select {
+	case <-drained:
+		t.Fatal("the drain returned while an execution was in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if !isDraining() {
+		t.Fatal("expected the scheduler to be draining")
+	}
+
+	//no new fire starts while draining
+	fake.Advance(24 * time.Hour)
+	triggerSchedule()
+	if _, err := triggerSchedulesByTag("nightly", ""); err == nil {
+		t.Error("expected the on demand trigger to be refused while draining")
+	}
+	if !checkHealth().Draining {
+		t.Error("expected the health to report the drain")
+	}
+
+	close(client.release)
+	status := decodeDrainStatus(t, <-drained)
+	if !status.Draining || status.WaitedMs <= 0 {
+		t.Errorf("unexpected drain status %+v", status)
+	}
+
+	client.mutex.Lock()
+	if client.calls["/api/v1/ping"] != 1 || client.calls["/api/v1/hang"] != 1 {
+		t.Errorf("unexpected requests %v", client.calls)
+	}
+	client.mutex.Unlock()
+
+	if status := decodeDrainStatus(t, serveMaintenance("resume")); status.Draining {
+		t.Errorf("unexpected resume status %+v", status)
+	}
+	triggerSchedule()
+	waitForIterations(t, fast.Id.Hex(), 2)
+	waitForExecutions()
+}
+
+func TestDrainedSchedulerStaysHealthy(t *testing.T) {
+	resetScheduler()
+	Configuration.ScheduleInterval = 10
+	defer func() { Configuration.ScheduleInterval = 0 }()
+
+	StartTicker()
+	defer StopTicker()
+
+	drainScheduler()
+	//the ticks go on while drained, the health does not go stale
+	time.Sleep(100 * time.Millisecond)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	var status HealthStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("unexpected error decoding the health : %s", err.Error())
+	}
+	if !status.Healthy || !status.TickerRunning || !status.Draining || status.LastTick == 0 {
+		t.Errorf("expected a healthy drained scheduler with its ticker running, got %+v", status)
+	}
+
+	//draining again is a no-op
+	drainScheduler()
+
+	resumeScheduler()
+	if status := checkHealth(); !status.TickerRunning || status.Draining {
+		t.Errorf("expected a resumed scheduler with its ticker running, got %+v", status)
+	}
+}
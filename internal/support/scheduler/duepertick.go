@@ -0,0 +1,38 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+)
+
+// the configured cap on the schedules dispatched by a single tick, 0 when they are all dispatched
+func maxDuePerTick() int {
+	if Configuration == nil || Configuration.MaxDuePerTick <= 0 {
+		return 0
+	}
+	return Configuration.MaxDuePerTick
+}
+
+// Keep the oldest due schedules up to the cap, the others go back to the queue and are picked up by the next
+// ticks. The caller holds the schedule mutex.
+func capDueContextsLocked(dueContexts []*ScheduleContext) []*ScheduleContext {
+	limit := maxDuePerTick()
+	if limit == 0 || len(dueContexts) <= limit {
+		return dueContexts
+	}
+
+	sort.SliceStable(dueContexts, func(i, j int) bool {
+		return dueContexts[i].NextTime.Before(dueContexts[j].NextTime)
+	})
+	for _, scheduleContext := range dueContexts[limit:] {
+		scheduleContext.Executing = false
+		scheduleQueue.Add(scheduleContext)
+	}
+	LoggingClient.Info(fmt.Sprintf("deferred %d due schedules to the next ticks, at most %d are dispatched per tick", len(dueContexts)-limit, limit))
+	return dueContexts[:limit]
+}
@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMaxDuePerTickSpreadsTheDueSchedules(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.MaxDuePerTick = 3
+	defer func() { Configuration.MaxDuePerTick = 0 }()
+
+	//the schedules are queued in an order unrelated to the time they are due at
+	const schedules = 10
+	now := time.Now()
+	for _, minutes := range []int{4, 9, 1, 7, 3, 10, 2, 6, 8, 5} {
+		name := fmt.Sprintf("due-%02d", minutes)
+		schedule := addTestSchedule(t, name)
+		addTestScheduleEvent(t, schedule, name, http.MethodGet, "/api/v1/"+name, "")
+		scheduleIdToContextMap[schedule.Id.Hex()].NextTime = now.Add(-time.Duration(minutes) * time.Minute)
+	}
+
+	var batches []int
+	for tick := 0; tick < 5; tick++ {
+		before := len(client.requests)
+		triggerSchedule()
+		waitForExecutions()
+		batches = append(batches, len(client.requests)-before)
+	}
+
+	expected := []int{3, 3, 3, 1, 0}
+	if fmt.Sprint(batches) != fmt.Sprint(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, fmt.Sprint(batches), fmt.Sprint(expected))
+	}
+	if scheduleQueue.Length() != schedules {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), schedules)
+	}
+
+	//each tick takes the oldest due schedules, the executions of a tick run concurrently
+	for tick, first := 0, 0; first < schedules; tick, first = tick+1, first+3 {
+		last := first + 3
+		if last > schedules {
+			last = schedules
+		}
+		sent := make(map[string]bool)
+		for _, req := range client.requests[first:last] {
+			sent[req.URL.Path] = true
+		}
+		for i := first; i < last; i++ {
+			path := fmt.Sprintf("/api/v1/due-%02d", schedules-i)
+			if !sent[path] {
+				t.Errorf("expected the tick %d to send %s, it sent %v", tick, path, sent)
+			}
+		}
+	}
+}
+
+func TestDuePerTickIsUnboundedByDefault(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("%s-%d", TestScheduleName, i)
+		schedule := addTestSchedule(t, name)
+		addTestScheduleEvent(t, schedule, name, http.MethodGet, "/api/v1/ping", "")
+		scheduleIdToContextMap[schedule.Id.Hex()].NextTime = time.Now().Add(-time.Second)
+	}
+
+	triggerSchedule()
+	waitForExecutions()
+
+	if len(client.requests) != 5 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 5)
+	}
+}
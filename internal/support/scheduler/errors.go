@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrTransport is returned when the request of a schedule event got no HTTP response at all
+type ErrTransport struct {
+	Url string
+	Err error
+}
+
+func (e ErrTransport) Error() string {
+	return fmt.Sprintf("no response from %s : %s", e.Url, e.Err.Error())
+}
+
+// ErrServerResponse is returned when the target of a schedule event answered with a 5xx status or throttled the
+// request with a 429 status
+type ErrServerResponse struct {
+	Url        string
+	StatusCode int
+	RetryAfter time.Duration // the wait asked for by the Retry-After header of the response, 0 without one
+}
+
+func (e ErrServerResponse) Error() string {
+	return fmt.Sprintf("%s answered with status code %d", e.Url, e.StatusCode)
+}
+
+// ErrUnexpectedResponse is returned when the response body of a schedule event does not match its ExpectResponse
+type ErrUnexpectedResponse struct {
+	Url      string
+	Expected string
+}
+
+func (e ErrUnexpectedResponse) Error() string {
+	return fmt.Sprintf("the response of %s does not match %q", e.Url, e.Expected)
+}
+
+// ErrUnexpectedStatus is returned when the target of a schedule event answered with a status outside of the success
+// statuses of the event
+type ErrUnexpectedStatus struct {
+	Url        string
+	StatusCode int
+}
+
+func (e ErrUnexpectedStatus) Error() string {
+	return fmt.Sprintf("%s answered with the unexpected status code %d", e.Url, e.StatusCode)
+}
+
+// ErrQueueFull is returned when a schedule can not be added because the queue is at its maximum depth
+type ErrQueueFull struct {
+	MaxQueueDepth int
+}
+
+func (e ErrQueueFull) Error() string {
+	return fmt.Sprintf("the schedule queue is full, it holds the maximum of %d schedules", e.MaxQueueDepth)
+}
+
+// ErrInvalidConfig lists every problem found in the configured schedules and schedule events
+type ErrInvalidConfig struct {
+	Problems []string
+}
+
+func (e ErrInvalidConfig) Error() string {
+	return fmt.Sprintf("the scheduler config has %d problems : %s", len(e.Problems), strings.Join(e.Problems, "; "))
+}
+
+// ErrInvalidCadence is returned when the new timing of a schedule can not be scheduled
+type ErrInvalidCadence struct {
+	Err error
+}
+
+func (e ErrInvalidCadence) Error() string {
+	return "invalid cadence : " + e.Err.Error()
+}
+
+// ErrDraining is returned when a schedule is triggered while the scheduler is drained for maintenance
+type ErrDraining struct{}
+
+func (e ErrDraining) Error() string {
+	return "the scheduler is drained for maintenance, it fires no schedule until it resumes"
+}
+
+// ErrMalformedId is returned when core-metadata answers an add with an id which is not an ObjectId
+type ErrMalformedId struct {
+	Kind string
+	Name string
+	Id   string
+}
+
+func (e ErrMalformedId) Error() string {
+	return fmt.Sprintf("core-metadata returned the malformed id %q for the %s %s, expected the hex form of an ObjectId", e.Id, e.Kind, e.Name)
+}
+
+// ErrNameConflict is returned when a schedule or a schedule event with the same name already exists
+type ErrNameConflict struct {
+	Kind string
+	Name string
+}
+
+func (e ErrNameConflict) Error() string {
+	return fmt.Sprintf("the %s %q already exists", e.Kind, e.Name)
+}
+
+// EventFailure is the error of a single schedule event within an execution
+type EventFailure struct {
+	ScheduleEventId string
+	Name            string
+	Err             error
+}
+
+// ErrExecution is returned when some events of a schedule execution failed, the other events were still executed.
+// The events left once the execution deadline passed are skipped, the execution is then partial.
+type ErrExecution struct {
+	ScheduleId string
+	Succeeded  []string // ids of the events which succeeded
+	Failures   []EventFailure
+	Skipped    []string // ids of the events which were not executed
+	Responded  []string // ids of the succeeded events whose response had a body
+}
+
+func (e ErrExecution) Error() string {
+	failures := make([]string, len(e.Failures))
+	for i, failure := range e.Failures {
+		failures[i] = fmt.Sprintf("%s : %s", failure.Name, failure.Err.Error())
+	}
+	msg := fmt.Sprintf("%d of %d events of the schedule %s failed", len(e.Failures), len(e.Failures)+len(e.Succeeded)+len(e.Skipped), e.ScheduleId)
+	if len(failures) > 0 {
+		msg += " : " + strings.Join(failures, "; ")
+	}
+	if e.Partial() {
+		msg += fmt.Sprintf(", %d were skipped past the execution deadline", len(e.Skipped))
+	}
+	return msg
+}
+
+func (e ErrExecution) Partial() bool {
+	return len(e.Skipped) > 0
+}
+
+// Failed reports whether an event failed or was skipped
+func (e ErrExecution) Failed() bool {
+	return len(e.Failures) > 0 || e.Partial()
+}
+
+// transport failures, throttled requests and responses asking for a retry with a Retry-After header are always
+// retried, server errors, unexpected statuses and unexpected responses only when RetryServerErrors is set
+func isRetryable(err error) bool {
+	switch e := err.(type) {
+	case ErrTransport:
+		return true
+	case ErrServerResponse:
+		return e.StatusCode == http.StatusTooManyRequests || e.RetryAfter > 0 ||
+			(Configuration != nil && Configuration.RetryServerErrors)
+	case ErrUnexpectedStatus, ErrUnexpectedResponse:
+		return Configuration != nil && Configuration.RetryServerErrors
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// the names of the schedules skipped by the last load as they had nothing left to fire, guarded by the schedule mutex
+var expiredScheduleNames = make(map[string]bool)
+
+// The iterations each schedule had run before the restart according to the state file, keyed by the schedule
+// so a schedule which changed since the snapshot starts over
+func stateIterations() map[string]int64 {
+	iterations := make(map[string]int64)
+	if Configuration == nil || Configuration.StateFile == "" {
+		return iterations
+	}
+	states, err := readState(Configuration.StateFile)
+	if err != nil {
+		return iterations
+	}
+	for _, state := range states {
+		iterations[stateKey(state.Schedule)] = state.CurrentIterations
+	}
+	return iterations
+}
+
+// the state holds the schedules as their contexts have them, a one-off is flagged run once there
+func stateKey(schedule models.Schedule) string {
+	if isOneOff(schedule) {
+		schedule.RunOnce = true
+	}
+	return schedule.String()
+}
+
+// Tell why a schedule being loaded has nothing left to fire, empty when it still has fires. A schedule is expired
+// once its End has passed or its next fire falls after it, or once it has run its MaxIterations before the restart,
+// which is its single fire for a run once or one-off schedule. A run once schedule whose start passed while the
+// scheduler was down has not fired and still does.
+func expiredAtLoad(schedule models.Schedule, iterations int64, now time.Time) string {
+	//a fired run once schedule is completed even when it is invalid otherwise
+	if (schedule.RunOnce || isOneOff(schedule)) && iterations >= 1 {
+		return CompletedRunOnce
+	}
+	scheduleContext := ScheduleContext{}
+	if err := scheduleContext.Reset(schedule); err != nil {
+		//the invalid schedule is reported when it is added
+		return ""
+	}
+	if scheduleContext.MaxIterations != 0 && iterations >= scheduleContext.MaxIterations {
+		return CompletedMaxIterations
+	}
+	if scheduleContext.isEnded(now) || scheduleContext.NextTime.Unix() > scheduleContext.EndTime.Unix() {
+		return CompletedEnd
+	}
+	return ""
+}
+
+// Report whether the schedule is expired and must not be loaded, the caller holds the schedule mutex
+func skipExpiredScheduleLocked(schedule models.Schedule, iterations map[string]int64) bool {
+	reason := expiredAtLoad(schedule, iterations[stateKey(schedule)], clock.Now())
+	if reason == "" {
+		delete(expiredScheduleNames, schedule.Name)
+		return false
+	}
+	expiredScheduleNames[schedule.Name] = true
+	LoggingClient.Info(fmt.Sprintf("the schedule %s has expired by its %s condition, it will not be loaded", schedule.Name, reason))
+	return true
+}
+
+func skipExpiredSchedule(schedule models.Schedule, iterations map[string]int64) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return skipExpiredScheduleLocked(schedule, iterations)
+}
+
+// Report whether the schedule with the given name was skipped by the last load as expired
+func isExpiredSchedule(scheduleName string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return expiredScheduleNames[scheduleName]
+}
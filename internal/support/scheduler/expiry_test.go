@@ -0,0 +1,217 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestAddSchedulersSkipsExpiredSchedules(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC))
+	defer restore()
+
+	active := models.Schedule{Id: bson.NewObjectId(), Name: "active", Start: "20180101T000000", Frequency: "P1D"}
+	ended := models.Schedule{Id: bson.NewObjectId(), Name: "ended", Start: "20180101T000000", End: "20180301T000000", Frequency: "P1D"}
+	//the next fire at midnight falls after the end
+	lastFired := models.Schedule{Id: bson.NewObjectId(), Name: "last-fired", Start: "20180101T000000", End: "20180601T180000", Frequency: "P1D"}
+	limited := models.Schedule{Id: bson.NewObjectId(), Name: "limited", Start: "20180101T000000", Frequency: "P1D", MaxIterations: 3}
+	exhausted := models.Schedule{Id: bson.NewObjectId(), Name: "exhausted", Start: "20180101T000000", Frequency: "P1D", MaxIterations: 3}
+	firedRunOnce := models.Schedule{Id: bson.NewObjectId(), Name: "fired-run-once", Start: "20180501T000000", Frequency: "P1D", RunOnce: true}
+	firedOneOff := models.Schedule{Id: bson.NewObjectId(), Name: "fired-one-off", Start: "20180501T000000"}
+	//the run once schedule has fired whatever makes it invalid otherwise
+	firedInvalid := models.Schedule{Id: bson.NewObjectId(), Name: "fired-invalid", Start: "20180501T000000", RunOnce: true, Timezone: "Nowhere/Invalid"}
+	pendingRunOnce := models.Schedule{Id: bson.NewObjectId(), Name: "pending-run-once", Start: "20180701T000000", Frequency: "P1D", RunOnce: true}
+	//the start of these passed while the scheduler was down, they did not fire
+	missedRunOnce := models.Schedule{Id: bson.NewObjectId(), Name: "missed-run-once", Start: "20180501T000000", Frequency: "P1D", RunOnce: true}
+	missedOneOff := models.Schedule{Id: bson.NewObjectId(), Name: "missed-one-off", Start: "20180501T000000"}
+
+	//the state file holds the iterations run before the restart
+	dir, err := ioutil.TempDir("", "scheduler")
+	if err != nil {
+		t.Fatalf("unexpected error creating the state dir : %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	Configuration.StateFile = filepath.Join(dir, "state.json")
+	defer func() { Configuration.StateFile = "" }()
+	states := []scheduleState{{Schedule: limited, CurrentIterations: 2}, {Schedule: exhausted, CurrentIterations: 3},
+		{Schedule: firedRunOnce, CurrentIterations: 1}, {Schedule: firedOneOff, CurrentIterations: 1}, {Schedule: firedInvalid, CurrentIterations: 1}}
+	if err := writeState(Configuration.StateFile, states); err != nil {
+		t.Fatalf("unexpected error writing the state : %s", err.Error())
+	}
+
+	scheduleEvents := []models.ScheduleEvent{
+		{Id: bson.NewObjectId(), Name: "active-ping", Schedule: active.Name, Service: "core-data"},
+		{Id: bson.NewObjectId(), Name: "ended-ping", Schedule: ended.Name, Service: "core-data"},
+	}
+	msc = &fakeScheduleClient{schedules: []models.Schedule{active, ended, lastFired, limited, exhausted, firedRunOnce, firedOneOff, firedInvalid, pendingRunOnce, missedRunOnce, missedOneOff}}
+	msec = &fakeScheduleEventClient{scheduleEvents: scheduleEvents}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	if err := AddSchedulers(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	if length := scheduleQueue.Length(); length != 5 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, length, 5)
+	}
+	for _, name := range []string{"active", "limited", "pending-run-once", "missed-run-once", "missed-one-off"} {
+		if _, err := queryScheduleByName(name); err != nil {
+			t.Errorf("the schedule %s should be queued : %s", name, err.Error())
+		}
+	}
+	for _, name := range []string{"ended", "last-fired", "exhausted", "fired-run-once", "fired-one-off", "fired-invalid"} {
+		if _, err := queryScheduleByName(name); err == nil {
+			t.Errorf("the expired schedule %s should not be queued", name)
+		}
+	}
+	if _, err := queryScheduleEventByName("ended-ping"); err == nil {
+		t.Error("the event of the expired schedule should not be loaded")
+	}
+	if _, err := queryScheduleEventByName("active-ping"); err != nil {
+		t.Errorf("the event of the active schedule should be loaded : %s", err.Error())
+	}
+}
+
+func TestLoadConfigSkipsExpiredSchedules(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC))
+	defer restore()
+
+	Configuration.AllowDegradedStart = true
+	Configuration.Schedules = map[string]config.ScheduleInfo{
+		"Midnight": {Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D"},
+		"Ended":    {Name: "ended", Start: "20180101T000000", End: "20180301T000000", Frequency: "P1D"},
+	}
+	Configuration.ScheduleEvents = map[string]config.ScheduleEventInfo{
+		"Ping":  {Name: TestScheduleEventName, Schedule: TestScheduleName, Host: "localhost", Port: 48080, Protocol: "http", Method: "GET", Path: "/api/v1/ping"},
+		"Ended": {Name: "ended-ping", Schedule: "ended", Host: "localhost", Port: 48080, Protocol: "http", Method: "GET", Path: "/api/v1/ping"},
+	}
+	defer func() {
+		Configuration.AllowDegradedStart = false
+		Configuration.Schedules = nil
+		Configuration.ScheduleEvents = nil
+		setDegradedMode(false)
+	}()
+	msc = &fakeScheduleClient{err: errors.New("core-metadata is unreachable")}
+	defer func() { msc = nil }()
+
+	if err := AddSchedulers(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	if length := scheduleQueue.Length(); length != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, length, 1)
+	}
+	if _, err := queryScheduleByName("ended"); err == nil {
+		t.Error("the expired config schedule should not be queued")
+	}
+	if _, err := queryScheduleEventByName("ended-ping"); err == nil {
+		t.Error("the event of the expired config schedule should not be loaded")
+	}
+	if _, err := queryScheduleEventByName(TestScheduleEventName); err != nil {
+		t.Errorf("the config schedule event should be loaded : %s", err.Error())
+	}
+}
+
+func TestLoadCoreMetadataCompletesTheFiredOneOffSchedules(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC))
+	defer restore()
+
+	keep := models.Schedule{Id: bson.NewObjectId(), Name: "keep", Start: "20180101T000000", Frequency: "PT1H"}
+	fired := models.Schedule{Id: bson.NewObjectId(), Name: "fired", Start: "20180501T000000"}
+	scheduleEvents := []models.ScheduleEvent{
+		{Id: bson.NewObjectId(), Name: "keep-ping", Schedule: keep.Name, Service: "core-data"},
+		{Id: bson.NewObjectId(), Name: "fired-ping", Schedule: fired.Name, Service: "core-data"},
+	}
+	msc = &fakeScheduleClient{schedules: []models.Schedule{keep, fired}}
+	msec = &fakeScheduleEventClient{scheduleEvents: scheduleEvents}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	//the state file tells the one-off schedule fired before the restart
+	dir, err := ioutil.TempDir("", "scheduler")
+	if err != nil {
+		t.Fatalf("unexpected error creating the state dir : %s", err.Error())
+	}
+	defer os.RemoveAll(dir)
+	Configuration.StateFile = filepath.Join(dir, "state.json")
+	defer func() { Configuration.StateFile = "" }()
+	if err := writeState(Configuration.StateFile, []scheduleState{{Schedule: fired, CurrentIterations: 1}}); err != nil {
+		t.Fatalf("unexpected error writing the state : %s", err.Error())
+	}
+
+	for _, load := range []func() error{loadCoreMetadataInformation, ReloadSchedulers} {
+		if err := load(); err != nil {
+			t.Fatalf("unexpected error : %s", err.Error())
+		}
+		if length := scheduleQueue.Length(); length != 1 {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, length, 1)
+		}
+		if _, err := queryScheduleEventByName("keep-ping"); err != nil {
+			t.Errorf("the event of the active schedule should be loaded : %s", err.Error())
+		}
+		if _, err := queryScheduleByName(fired.Name); err == nil {
+			t.Error("the fired one-off schedule should not be queued")
+		}
+		if !isExpiredSchedule(fired.Name) {
+			t.Error("expected the fired one-off schedule to be completed")
+		}
+	}
+}
+
+func TestRunOnceScheduleMissedWhileDownFiresAfterTheLoad(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	missed := newTestSchedule("missed")
+	missed.Start = "20180501T000000"
+	missed.RunOnce = true
+	scheduleEvent := newTestScheduleEvent(missed, "missed-ping", http.MethodGet, "/api/v1/ping", "")
+	msc = &fakeScheduleClient{schedules: []models.Schedule{missed}}
+	msec = &fakeScheduleEventClient{scheduleEvents: []models.ScheduleEvent{scheduleEvent}}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	//no state was saved, the schedule did not fire before the restart
+	if err := loadCoreMetadataInformation(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if isExpiredSchedule(missed.Name) {
+		t.Fatal("the run once schedule which did not fire should not be completed")
+	}
+	if _, err := queryScheduleByName(missed.Name); err != nil {
+		t.Fatalf("the run once schedule which did not fire should be queued : %s", err.Error())
+	}
+
+	triggerSchedule()
+	waitForExecutions()
+	if len(client.requests) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+}
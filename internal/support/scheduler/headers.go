@@ -0,0 +1,60 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// the Content-Type of the event requests without one in their headers, JSON unless configured otherwise
+func defaultContentType() string {
+	if Configuration == nil || Configuration.DefaultContentType == "" {
+		return ContentTypeJsonValue
+	}
+	return Configuration.DefaultContentType
+}
+
+// the static headers of every event request, such as a tenant or an environment tag
+func globalHeaders() map[string]string {
+	if Configuration == nil {
+		return nil
+	}
+	return Configuration.GlobalHeaders
+}
+
+// Set the headers of the request of a schedule event. The Content-Type is taken from the headers of the event,
+// then from the DefaultContentType of the service and falls back to JSON. The GlobalHeaders of the service are
+// set on every request below the headers of the event. The correlation id of the execution always replaces the
+// one of the event.
+func setEventHeaders(req *http.Request, scheduleEvent models.ScheduleEvent, correlationId string) {
+	req.Header.Set(ContentTypeKey, defaultContentType())
+	req.Header.Set(UserAgentKey, userAgent())
+	req.Header.Set(AcceptEncodingKey, GzipEncoding+", "+DeflateEncoding)
+	for name, value := range globalHeaders() {
+		req.Header.Set(name, value)
+	}
+	for name, value := range scheduleEvent.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set(CorrelationHeader, correlationId)
+}
+
+// Add a header to the rendered headers of an event unless the event sets it already, whatever its case
+func withDefaultHeader(headers map[string]string, name string, value string) map[string]string {
+	for header := range headers {
+		if http.CanonicalHeaderKey(header) == http.CanonicalHeaderKey(name) {
+			return headers
+		}
+	}
+
+	if headers == nil {
+		headers = make(map[string]string, 1)
+	}
+	headers[name] = value
+	return headers
+}
@@ -0,0 +1,154 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestContentTypePrecedence(t *testing.T) {
+	defer func() { Configuration.DefaultContentType = "" }()
+
+	tests := []struct {
+		name     string
+		fallback string
+		headers  map[string]string
+		expected string
+	}{
+		{"json fallback", "", nil, ContentTypeJsonValue},
+		{"service default", "application/xml", nil, "application/xml"},
+		{"event header", "application/xml", map[string]string{ContentTypeKey: "text/plain"}, "text/plain"},
+		{"event header any case", "", map[string]string{"content-type": "text/plain"}, "text/plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Configuration.DefaultContentType = tt.fallback
+			req, _ := http.NewRequest(http.MethodPost, "http://localhost:48080/api/v1/ping", nil)
+			setEventHeaders(req, models.ScheduleEvent{Headers: tt.headers}, "correlation")
+
+			if contentType := req.Header.Get(ContentTypeKey); contentType != tt.expected {
+				t.Errorf(TestUnexpectedMsgFormatStr, contentType, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEventHeadersKeepCorrelationId(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:48080/api/v1/ping", nil)
+	scheduleEvent := models.ScheduleEvent{Headers: map[string]string{CorrelationHeader: "stale", "X-Api-Key": "key"}}
+	setEventHeaders(req, scheduleEvent, "correlation")
+
+	if correlationId := req.Header.Get(CorrelationHeader); correlationId != "correlation" {
+		t.Errorf(TestUnexpectedMsgFormatStr, correlationId, "correlation")
+	}
+	if apiKey := req.Header.Get("X-Api-Key"); apiKey != "key" {
+		t.Errorf(TestUnexpectedMsgFormatStr, apiKey, "key")
+	}
+}
+
+func TestGlobalHeadersOnEveryRequest(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.GlobalHeaders = map[string]string{"X-Tenant-Id": "tenant-a", "X-Environment": "staging"}
+	defer func() { Configuration.GlobalHeaders = nil }()
+
+	first := addTestSchedule(t, "first")
+	addTestScheduleEvent(t, first, "first-ping", http.MethodGet, "/api/v1/ping", "")
+	second := addTestSchedule(t, "second")
+	overriding := models.ScheduleEvent{
+		Id:       bson.NewObjectId(),
+		Name:     "second-ping",
+		Schedule: second.Name,
+		Headers:  map[string]string{"x-tenant-id": "tenant-b"},
+		Addressable: models.Addressable{
+			Name:       "second-ping",
+			Protocol:   "http",
+			HTTPMethod: http.MethodGet,
+			Address:    "localhost",
+			Port:       48080,
+			Path:       "/api/v1/ping",
+		},
+	}
+	if err := addScheduleEvent(overriding); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+
+	executeSchedule(first.Id.Hex())
+	executeSchedule(second.Id.Hex())
+
+	if len(client.requests) != 2 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 2)
+	}
+	for i, tenant := range []string{"tenant-a", "tenant-b"} {
+		header := client.requests[i].Header
+		if value := header.Get("X-Tenant-Id"); value != tenant {
+			t.Errorf(TestUnexpectedMsgFormatStr, value, tenant)
+		}
+		if value := header.Get("X-Environment"); value != "staging" {
+			t.Errorf(TestUnexpectedMsgFormatStr, value, "staging")
+		}
+	}
+}
+
+func TestFormEventContentTypeReachesServer(t *testing.T) {
+	resetScheduler()
+	Configuration.DefaultContentType = "application/xml"
+	defer func() { Configuration.DefaultContentType = "" }()
+
+	var mutex sync.Mutex
+	var contentType, field string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		contentType = r.Header.Get(ContentTypeKey)
+		field = r.PostFormValue("field")
+	}))
+	defer server.Close()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := models.ScheduleEvent{
+		Id:         bson.NewObjectId(),
+		Name:       TestScheduleEventName,
+		Schedule:   schedule.Name,
+		Parameters: "field=value",
+		Headers:    map[string]string{ContentTypeKey: "application/x-www-form-urlencoded"},
+		Addressable: models.Addressable{
+			Name:       TestScheduleEventName,
+			Protocol:   "http",
+			HTTPMethod: http.MethodPost,
+			Address:    serverUrl.Hostname(),
+			Port:       port,
+			Path:       "/api/v1/form",
+		},
+	}
+	if err := addScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+
+	executeSchedule(schedule.Id.Hex())
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if contentType != "application/x-www-form-urlencoded" {
+		t.Errorf(TestUnexpectedMsgFormatStr, contentType, "application/x-www-form-urlencoded")
+	}
+	if field != "value" {
+		t.Errorf(TestUnexpectedMsgFormatStr, field, "value")
+	}
+}
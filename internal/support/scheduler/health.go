@@ -0,0 +1,64 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import "time"
+
+// HealthTickThreshold is the number of schedule intervals without a tick after which the scheduler is unhealthy
+const HealthTickThreshold = 3
+
+// HealthStatus reports whether the ticker is alive and processing the schedules
+type HealthStatus struct {
+	Healthy       bool  `json:"healthy"`
+	TickerRunning bool  `json:"tickerRunning"`
+	LastTick      int64 `json:"lastTick"` // milliseconds since the epoch, 0 before the first tick
+	QueueLength   int   `json:"queueLength"`
+	MaxQueueDepth int   `json:"maxQueueDepth"` // 0 when the queue is unbounded
+	Degraded      bool  `json:"degraded"`      // started without core-metadata
+	Draining      bool  `json:"draining"`      // drained for maintenance, no schedule fires until it resumes
+}
+
+// guarded by the schedule mutex
+var (
+	lastTick        time.Time
+	tickerRunning   bool
+	tickerStartedAt time.Time
+)
+
+func setTickerRunning(running bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	tickerRunning = running
+	if running {
+		tickerStartedAt = clock.Now()
+	}
+}
+
+func checkHealth() HealthStatus {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	status := HealthStatus{
+		TickerRunning: tickerRunning,
+		QueueLength:   scheduleQueue.Length(),
+		MaxQueueDepth: maxQueueDepth(),
+		Degraded:      degradedMode,
+		Draining:      draining,
+	}
+	if !lastTick.IsZero() {
+		status.LastTick = lastTick.UnixNano() / int64(time.Millisecond)
+	}
+
+	//the staleness is counted from the start of the ticker until its first tick, a ticker which never ticks is
+	//unhealthy once the threshold is past
+	threshold := HealthTickThreshold * scheduleInterval()
+	since := lastTick
+	if tickerStartedAt.After(since) {
+		since = tickerStartedAt
+	}
+	status.Healthy = tickerRunning && clock.Now().Sub(since) <= threshold
+	return status
+}
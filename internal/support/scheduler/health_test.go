@@ -0,0 +1,94 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func requestHealth(t *testing.T) (int, HealthStatus) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/health", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	var status HealthStatus
+	if err := json.NewDecoder(rec.Body).Decode(&status); err != nil {
+		t.Fatalf("unexpected error decoding the health status : %s", err.Error())
+	}
+	return rec.Code, status
+}
+
+func TestHealthFlipsWhenTickerStops(t *testing.T) {
+	resetScheduler()
+	Configuration.ScheduleInterval = 10
+	defer func() { Configuration.ScheduleInterval = 0 }()
+
+	StartTicker()
+	time.Sleep(50 * time.Millisecond)
+
+	code, status := requestHealth(t)
+	if code != http.StatusOK || !status.Healthy || !status.TickerRunning {
+		t.Errorf("expected a healthy running scheduler, got %d %+v", code, status)
+	}
+	if status.LastTick == 0 {
+		t.Error("expected the last tick to be reported")
+	}
+
+	StopTicker()
+
+	code, status = requestHealth(t)
+	if code != http.StatusServiceUnavailable || status.Healthy || status.TickerRunning {
+		t.Errorf("expected an unhealthy stopped scheduler, got %d %+v", code, status)
+	}
+}
+
+func TestHealthStaleTick(t *testing.T) {
+	resetScheduler()
+	Configuration.ScheduleInterval = 10
+	defer func() { Configuration.ScheduleInterval = 0 }()
+
+	setTickerRunning(true)
+	defer setTickerRunning(false)
+
+	triggerSchedule()
+
+	waitForExecutions()
+	if status := checkHealth(); !status.Healthy {
+		t.Errorf("expected a healthy scheduler right after a tick, got %+v", status)
+	}
+
+	time.Sleep(HealthTickThreshold*10*time.Millisecond + 20*time.Millisecond)
+	if status := checkHealth(); status.Healthy {
+		t.Errorf("expected an unhealthy scheduler once the last tick is older than the threshold, got %+v", status)
+	}
+}
+
+func TestHealthWithoutAFirstTick(t *testing.T) {
+	resetScheduler()
+	Configuration.ScheduleInterval = 10
+	defer func() { Configuration.ScheduleInterval = 0 }()
+	mutex.Lock()
+	lastTick = time.Time{}
+	mutex.Unlock()
+
+	//the ticker is marked running but never ticks
+	setTickerRunning(true)
+	defer setTickerRunning(false)
+
+	if status := checkHealth(); !status.Healthy {
+		t.Errorf("expected a healthy scheduler right after the ticker started, got %+v", status)
+	}
+
+	time.Sleep(HealthTickThreshold*10*time.Millisecond + 20*time.Millisecond)
+	code, status := requestHealth(t)
+	if code != http.StatusServiceUnavailable || status.Healthy || status.LastTick != 0 {
+		t.Errorf("expected an unhealthy scheduler once the threshold is past without a tick, got %d %+v", code, status)
+	}
+}
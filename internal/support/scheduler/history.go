@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import "sync"
+
+// DefaultHistoryDepth is the number of executions kept per schedule when HistoryDepth is not configured
+const DefaultHistoryDepth = 10
+
+// ExecutionRecord is a single execution of a schedule and the results of its events
+type ExecutionRecord struct {
+	CorrelationId string           `json:"correlationId"`
+	Time          int64            `json:"time"` // milliseconds since the epoch
+	Events        []EventExecution `json:"events"`
+	Partial       bool             `json:"partial,omitempty"` // events were skipped past the execution deadline
+}
+
+// EventExecution is the result of a schedule event within an execution of its schedule
+type EventExecution struct {
+	ScheduleEventId string `json:"scheduleEventId"`
+	LastRun
+}
+
+// executionHistory is a ring buffer of the latest executions of a schedule, the oldest one is overwritten once it is full
+type executionHistory struct {
+	records []ExecutionRecord
+	next    int
+	count   int
+}
+
+func (h *executionHistory) add(record ExecutionRecord) {
+	h.records[h.next] = record
+	h.next = (h.next + 1) % len(h.records)
+	if h.count < len(h.records) {
+		h.count++
+	}
+}
+
+// the records from the oldest to the newest
+func (h *executionHistory) list() []ExecutionRecord {
+	result := make([]ExecutionRecord, 0, h.count)
+	for i := 0; i < h.count; i++ {
+		result = append(result, h.records[(h.next-h.count+i+len(h.records))%len(h.records)])
+	}
+	return result
+}
+
+// the executions run concurrently and outside of the schedule mutex so the histories have a lock of their own
+var (
+	historyMutex           sync.Mutex
+	scheduleIdToHistoryMap = make(map[string]*executionHistory) // map : schedule id -> execution history
+)
+
+func historyDepth() int {
+	if Configuration == nil || Configuration.HistoryDepth <= 0 {
+		return DefaultHistoryDepth
+	}
+	return Configuration.HistoryDepth
+}
+
+func recordExecution(scheduleId string, record ExecutionRecord) {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	history, exists := scheduleIdToHistoryMap[scheduleId]
+	if !exists || len(history.records) != historyDepth() {
+		//keep the records when the depth changed, as many as still fit
+		resized := &executionHistory{records: make([]ExecutionRecord, historyDepth())}
+		if exists {
+			for _, previous := range history.list() {
+				resized.add(previous)
+			}
+		}
+		history = resized
+		scheduleIdToHistoryMap[scheduleId] = history
+	}
+	history.add(record)
+}
+
+func queryHistory(scheduleId string) ([]ExecutionRecord, error) {
+	if _, err := querySchedule(scheduleId); err != nil {
+		return nil, err
+	}
+
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+
+	history, exists := scheduleIdToHistoryMap[scheduleId]
+	if !exists {
+		return []ExecutionRecord{}, nil
+	}
+	return history.list(), nil
+}
+
+func removeHistory(scheduleId string) {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+	delete(scheduleIdToHistoryMap, scheduleId)
+}
+
+func clearHistories() {
+	historyMutex.Lock()
+	defer historyMutex.Unlock()
+	scheduleIdToHistoryMap = make(map[string]*executionHistory)
+}
@@ -0,0 +1,98 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestHistoryKeepsLatestExecutions(t *testing.T) {
+	resetScheduler()
+	SetHTTPClient(&mockHTTPClient{})
+	defer SetHTTPClient(nil)
+
+	Configuration.HistoryDepth = 3
+	defer func() { Configuration.HistoryDepth = 0 }()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	var correlationIds []string
+	for i := 0; i < 5; i++ {
+		executeSchedule(schedule.Id.Hex())
+		history, _ := queryHistory(schedule.Id.Hex())
+		correlationIds = append(correlationIds, history[len(history)-1].CorrelationId)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schedule/"+schedule.Id.Hex()+"/history", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	var history []ExecutionRecord
+	if err := json.NewDecoder(rec.Body).Decode(&history); err != nil {
+		t.Fatalf("unexpected error decoding the history : %s", err.Error())
+	}
+
+	if len(history) != 3 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(history), 3)
+	}
+	for i, record := range history {
+		if record.CorrelationId != correlationIds[i+2] {
+			t.Errorf(TestUnexpectedMsgFormatStr, record.CorrelationId, correlationIds[i+2])
+		}
+		if len(record.Events) != 1 {
+			t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(record.Events), 1)
+		}
+		if record.Events[0].ScheduleEventId != scheduleEvent.Id.Hex() || record.Events[0].StatusCode != http.StatusOK {
+			t.Errorf("unexpected event execution %+v", record.Events[0])
+		}
+	}
+}
+
+func TestHistoryConcurrentExecutions(t *testing.T) {
+	resetScheduler()
+	SetHTTPClient(&mockHTTPClient{})
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			recordExecution(schedule.Id.Hex(), ExecutionRecord{})
+			queryHistory(schedule.Id.Hex())
+		}()
+	}
+	wg.Wait()
+
+	history, err := queryHistory(schedule.Id.Hex())
+	if err != nil {
+		t.Fatalf("unexpected error querying the history : %s", err.Error())
+	}
+	if len(history) != DefaultHistoryDepth {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(history), DefaultHistoryDepth)
+	}
+}
+
+func TestHistoryUnknownSchedule(t *testing.T) {
+	resetScheduler()
+
+	if _, err := queryHistory(bson.NewObjectId().Hex()); err == nil {
+		t.Error("expected an error querying the history of an unknown schedule")
+	}
+}
@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DefaultMaxRequestsPerHost is the number of requests in flight to a single host when MaxRequestsPerHost is not configured
+const DefaultMaxRequestsPerHost = 16
+
+var (
+	hostSlotsMutex sync.Mutex
+	hostSlots      = make(map[string]chan struct{})
+)
+
+func maxRequestsPerHost() int {
+	if Configuration == nil || Configuration.MaxRequestsPerHost <= 0 {
+		return DefaultMaxRequestsPerHost
+	}
+	return Configuration.MaxRequestsPerHost
+}
+
+// the requests to an addressable are limited by its host and port
+func hostKey(addressable models.Addressable) string {
+	return fmt.Sprintf("%s:%d", addressable.Address, addressable.Port)
+}
+
+// get the slots bounding the requests in flight to a host, they are made again when the bound changes and the
+// requests in flight free the slots they took
+func getHostSlots(host string) chan struct{} {
+	hostSlotsMutex.Lock()
+	defer hostSlotsMutex.Unlock()
+
+	limit := maxRequestsPerHost()
+	slots := hostSlots[host]
+	if cap(slots) != limit {
+		slots = make(chan struct{}, limit)
+		hostSlots[host] = slots
+	}
+	return slots
+}
+
+// Wait for a slot of the host of the addressable or for the context to be done. The returned function frees the
+// slot once the request is over.
+func acquireHostSlot(ctx context.Context, addressable models.Addressable) (func(), error) {
+	slots := getHostSlots(hostKey(addressable))
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,89 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// inFlightHTTPClient holds every request for a while, keeping the highest number in flight to each host
+type inFlightHTTPClient struct {
+	mutex    sync.Mutex
+	delay    time.Duration
+	inFlight map[string]int
+	peak     map[string]int
+}
+
+func (c *inFlightHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.mutex.Lock()
+	c.inFlight[req.URL.Host]++
+	if c.inFlight[req.URL.Host] > c.peak[req.URL.Host] {
+		c.peak[req.URL.Host] = c.inFlight[req.URL.Host]
+	}
+	c.mutex.Unlock()
+
+	time.Sleep(c.delay)
+
+	c.mutex.Lock()
+	c.inFlight[req.URL.Host]--
+	c.mutex.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func addHostTestSchedule(t *testing.T, name string, host string) {
+	schedule := addTestSchedule(t, name)
+	scheduleEvent := newTestScheduleEvent(schedule, name+"-event", http.MethodGet, "/api/v1/ping", "")
+	scheduleEvent.Addressable.Address = host
+	mustAddScheduleEvent(t, scheduleEvent)
+}
+
+func TestMaxRequestsPerHost(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC))
+	defer restore()
+	Configuration = &ConfigurationStruct{MaxRequestsPerHost: 2}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+	client := &inFlightHTTPClient{delay: 100 * time.Millisecond, inFlight: make(map[string]int), peak: make(map[string]int)}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	for i := 0; i < 6; i++ {
+		addHostTestSchedule(t, fmt.Sprintf("busy-%d", i), "core-data")
+	}
+	addHostTestSchedule(t, "quiet-0", "export-client")
+	addHostTestSchedule(t, "quiet-1", "export-client")
+
+	fake.Advance(2 * time.Second)
+	triggerSchedule()
+	waitForExecutions()
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	if peak := client.peak["core-data:48080"]; peak != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, peak, 2)
+	}
+	//the other host is not held back by the busy one
+	if peak := client.peak["export-client:48080"]; peak != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, peak, 2)
+	}
+}
+
+func TestMaxRequestsPerHostDefault(t *testing.T) {
+	Configuration = &ConfigurationStruct{}
+	if limit := maxRequestsPerHost(); limit != DefaultMaxRequestsPerHost {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, limit, DefaultMaxRequestsPerHost)
+	}
+}
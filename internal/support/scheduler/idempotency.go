@@ -0,0 +1,35 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// the header carrying the idempotency key when IdempotencyHeader is not configured
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+// The idempotency key of the request of an event is derived from the schedule, the event and the fire time only,
+// so the retries of a fire and a duplicate fire from another replica carry the same key.
+func idempotencyKey(scheduleId string, eventId string, fireTime time.Time) string {
+	name := fmt.Sprintf("%s/%s/%d", scheduleId, eventId, fireTime.UnixNano())
+	return uuid.NewV5(uuid.NamespaceOID, name).String()
+}
+
+func idempotencyHeader() string {
+	if Configuration == nil || Configuration.IdempotencyHeader == "" {
+		return DefaultIdempotencyHeader
+	}
+	return Configuration.IdempotencyHeader
+}
+
+// Add the idempotency key to the rendered headers of an event, an idempotency header of the event itself is kept
+func withIdempotencyKey(headers map[string]string, key string) map[string]string {
+	return withDefaultHeader(headers, idempotencyHeader(), key)
+}
@@ -0,0 +1,81 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	scheduleId, eventId := bson.NewObjectId().Hex(), bson.NewObjectId().Hex()
+	fireTime := time.Date(2018, 1, 1, 0, 1, 0, 0, time.UTC)
+
+	key := idempotencyKey(scheduleId, eventId, fireTime)
+	if again := idempotencyKey(scheduleId, eventId, fireTime); again != key {
+		t.Errorf(TestUnexpectedMsgFormatStr, again, key)
+	}
+	if next := idempotencyKey(scheduleId, eventId, fireTime.Add(time.Minute)); next == key {
+		t.Error("expected the next fire to have another key")
+	}
+	if other := idempotencyKey(scheduleId, bson.NewObjectId().Hex(), fireTime); other == key {
+		t.Error("expected another event to have another key")
+	}
+}
+
+func TestIdempotencyKeyIsSharedByTheRetriesOfAFire(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{statusCode: http.StatusServiceUnavailable}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.MaxRetries = 1
+	Configuration.RetryServerErrors = true
+	defer func() {
+		Configuration.MaxRetries = 0
+		Configuration.RetryServerErrors = false
+	}()
+
+	scheduleId := addClockTestSchedule(t, TestScheduleName, "20180101T000100", "PT1M")
+	addTestScheduleEvent(t, scheduleIdToContextMap[scheduleId].Schedule, TestScheduleEventName, http.MethodPost, "/api/v1/charge", `{"amount":1}`)
+
+	for i := 0; i < 2; i++ {
+		fake.Advance(time.Minute)
+		triggerSchedule()
+		waitForExecutions()
+	}
+
+	if len(client.requests) != 4 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 4)
+	}
+	keys := make([]string, len(client.requests))
+	for i, req := range client.requests {
+		keys[i] = req.Header.Get(DefaultIdempotencyHeader)
+	}
+	if keys[0] == "" || keys[0] != keys[1] || keys[2] != keys[3] {
+		t.Errorf("expected the attempts of a fire to share their key, got %v", keys)
+	}
+	if keys[1] == keys[2] {
+		t.Errorf("expected the fires to have their own keys, got %v", keys)
+	}
+}
+
+func TestEventIdempotencyHeaderIsKept(t *testing.T) {
+	headers := withIdempotencyKey(map[string]string{"idempotency-key": "from-the-event"}, "generated")
+	if len(headers) != 1 || headers["idempotency-key"] != "from-the-event" {
+		t.Errorf(TestUnexpectedMsgFormatStr, headers, "the key of the event")
+	}
+
+	headers = withIdempotencyKey(nil, "generated")
+	if headers[DefaultIdempotencyHeader] != "generated" {
+		t.Errorf(TestUnexpectedMsgFormatStr, headers[DefaultIdempotencyHeader], "generated")
+	}
+}
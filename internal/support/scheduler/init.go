@@ -9,6 +9,7 @@ package scheduler
 import (
 	"fmt"
 	"github.com/edgexfoundry/edgex-go/internal/pkg/startup"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -24,12 +25,13 @@ import (
 
 var Configuration *ConfigurationStruct
 var LoggingClient logger.LoggingClient
-var msc metadata.ScheduleClient
-var msec metadata.ScheduleEventClient
-var mac metadata.AddressableClient
+var msc ScheduleClient
+var msec ScheduleEventClient
+var mac AddressableClient
 
 var chConfig chan interface{} //A channel for use by ConsulDecoder in detecting configuration mods.
-var ticker = time.NewTicker(time.Duration(ScheduleInterval) * time.Millisecond)
+var ticker *time.Ticker
+var tickerStop, tickerDone chan struct{} //closed to stop the tick loop, and by the tick loop once it is over
 
 func Retry(useConsul bool, useProfile string, timeout int, wait *sync.WaitGroup, ch chan error) {
 	now := time.Now()
@@ -76,6 +78,20 @@ func Init(useConsul bool) bool {
 		return false
 	}
 
+	// seed the source used to spread out schedule fire times
+	rand.Seed(time.Now().UnixNano())
+
+	// only the leader executes the schedules when the replicas share a leader lock
+	lock, err := leaderLockFromConfig()
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("failed to set up the leader election : %s", err.Error()))
+		return false
+	}
+	if lock != nil {
+		SetLeaderLock(lock)
+		LoggingClient.Info("the leader election is on, only the leader executes the schedules")
+	}
+
 	if useConsul {
 		chConfig = make(chan interface{})
 		go listenForConfigChanges()
@@ -91,6 +107,8 @@ func Destruct() {
 	if ticker != nil {
 		StopTicker()
 	}
+	closeLeaderLock()
+	StopScheduleDirWatcher()
 }
 
 func initializeConfiguration(useConsul bool, useProfile string) (*ConfigurationStruct, error) {
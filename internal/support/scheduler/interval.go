@@ -0,0 +1,83 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// Check the interval policy of the schedule and start its interval at the frequency. The multiplicative policy
+// needs a frequency which is not aligned, a factor and the limit the interval stops at.
+func (sc *ScheduleContext) resetIntervalPolicy() error {
+	sc.interval = sc.Frequency
+	sc.intervalLimit = 0
+
+	switch sc.Schedule.IntervalPolicy {
+	case "", IntervalConstant:
+		return nil
+	case IntervalMultiplicative:
+	default:
+		return fmt.Errorf("the schedule %s has an unknown interval policy %s", sc.Schedule.Name, sc.Schedule.IntervalPolicy)
+	}
+
+	if sc.cronSchedule != nil || sc.Schedule.RunOnce || sc.Schedule.AlignToInterval {
+		return fmt.Errorf("the schedule %s needs a frequency which is not aligned for the %s interval policy", sc.Schedule.Name, IntervalMultiplicative)
+	}
+	if sc.Schedule.IntervalFactor <= 0 || sc.Schedule.IntervalFactor == 1 {
+		return fmt.Errorf("the schedule %s has an invalid interval factor %v, it must be greater than zero and not 1", sc.Schedule.Name, sc.Schedule.IntervalFactor)
+	}
+	sc.intervalLimit = parseFrequency(sc.Schedule.IntervalLimit)
+	if sc.intervalLimit <= 0 {
+		return fmt.Errorf("the schedule %s has an invalid interval limit %q", sc.Schedule.Name, sc.Schedule.IntervalLimit)
+	}
+	if sc.Schedule.IntervalFactor > 1 && sc.intervalLimit < sc.Frequency {
+		return fmt.Errorf("the schedule %s has an interval limit %s below its frequency %s while the interval grows", sc.Schedule.Name, sc.Schedule.IntervalLimit, sc.Schedule.Frequency)
+	}
+	if sc.Schedule.IntervalFactor < 1 && sc.intervalLimit > sc.Frequency {
+		return fmt.Errorf("the schedule %s has an interval limit %s above its frequency %s while the interval shrinks", sc.Schedule.Name, sc.Schedule.IntervalLimit, sc.Schedule.Frequency)
+	}
+	return nil
+}
+
+func (sc *ScheduleContext) isMultiplicative() bool {
+	return sc.Schedule.IntervalPolicy == IntervalMultiplicative && sc.interval > 0
+}
+
+// the gap between the next fire and the one after
+func (sc *ScheduleContext) currentInterval() time.Duration {
+	if sc.isMultiplicative() {
+		return sc.interval
+	}
+	return sc.Frequency
+}
+
+// Multiply the interval by the factor of the schedule after a fire, stopping at its limit and never going
+// under the minimum interval
+func (sc *ScheduleContext) advanceInterval() {
+	if !sc.isMultiplicative() {
+		return
+	}
+
+	next := time.Duration(float64(sc.interval) * sc.Schedule.IntervalFactor)
+	if sc.Schedule.IntervalFactor > 1 && (next > sc.intervalLimit || next < sc.interval) {
+		next = sc.intervalLimit
+	} else if sc.Schedule.IntervalFactor < 1 && next < sc.intervalLimit {
+		next = sc.intervalLimit
+	}
+	if floor := minInterval(); next < floor {
+		next = floor
+	}
+	if next < time.Second {
+		next = time.Second
+	}
+	sc.interval = next
+}
+
+// go back to the frequency of the schedule
+func (sc *ScheduleContext) resetInterval() {
+	sc.interval = sc.Frequency
+}
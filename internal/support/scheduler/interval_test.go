@@ -0,0 +1,150 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func multiplicativeTestSchedule(factor float64, limit string) models.Schedule {
+	return models.Schedule{
+		Id:             bson.NewObjectId(),
+		Name:           TestScheduleName,
+		Start:          "20180101T000000",
+		Frequency:      "PT8M",
+		IntervalPolicy: IntervalMultiplicative,
+		IntervalFactor: factor,
+		IntervalLimit:  limit,
+	}
+}
+
+// the gaps between the next fires of the context
+func fireGaps(sc *ScheduleContext, fires int) []time.Duration {
+	gaps := make([]time.Duration, 0, fires)
+	for i := 0; i < fires; i++ {
+		before := sc.NextTime
+		sc.UpdateNextTime()
+		gaps = append(gaps, sc.NextTime.Sub(before))
+	}
+	return gaps
+}
+
+func assertGaps(t *testing.T, gaps []time.Duration, expected []time.Duration) {
+	if len(gaps) != len(expected) {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(gaps), len(expected))
+	}
+	for i := range gaps {
+		if gaps[i] != expected[i] {
+			t.Errorf("fire %d : "+TestUnexpectedMsgFormatStr, i, gaps[i], expected[i])
+		}
+	}
+}
+
+func TestMultiplicativeIntervalDoublesUpToTheLimit(t *testing.T) {
+	_, restore := useFakeClock(time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC))
+	defer restore()
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(multiplicativeTestSchedule(2, "PT1H")); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	expected := []time.Duration{8 * time.Minute, 16 * time.Minute, 32 * time.Minute, time.Hour, time.Hour}
+	assertGaps(t, fireGaps(&testScheduleContext, 5), expected)
+}
+
+func TestMultiplicativeIntervalShrinksDownToTheLimit(t *testing.T) {
+	_, restore := useFakeClock(time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC))
+	defer restore()
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(multiplicativeTestSchedule(0.5, "PT3M")); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	expected := []time.Duration{8 * time.Minute, 4 * time.Minute, 3 * time.Minute, 3 * time.Minute}
+	assertGaps(t, fireGaps(&testScheduleContext, 4), expected)
+}
+
+func TestConstantIntervalByDefault(t *testing.T) {
+	_, restore := useFakeClock(time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC))
+	defer restore()
+
+	schedule := multiplicativeTestSchedule(2, "PT1H")
+	schedule.IntervalPolicy = ""
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(schedule); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	assertGaps(t, fireGaps(&testScheduleContext, 3), []time.Duration{8 * time.Minute, 8 * time.Minute, 8 * time.Minute})
+}
+
+func TestInvalidIntervalPolicies(t *testing.T) {
+	aligned := multiplicativeTestSchedule(2, "PT1H")
+	aligned.AlignToInterval = true
+	cron := multiplicativeTestSchedule(2, "PT1H")
+	cron.Frequency = ""
+	cron.Cron = "@hourly"
+	unknown := multiplicativeTestSchedule(2, "PT1H")
+	unknown.IntervalPolicy = "exponential"
+
+	for _, schedule := range []models.Schedule{
+		unknown,
+		aligned,
+		cron,
+		multiplicativeTestSchedule(0, "PT1H"),
+		multiplicativeTestSchedule(1, "PT1H"),
+		multiplicativeTestSchedule(2, ""),
+		multiplicativeTestSchedule(2, "PT1M"),
+		multiplicativeTestSchedule(0.5, "PT1H"),
+	} {
+		testScheduleContext := ScheduleContext{}
+		if err := testScheduleContext.Reset(schedule); err == nil {
+			t.Errorf("expected the interval policy to be rejected : %s", schedule.String())
+		}
+	}
+}
+
+func TestResetIntervalOnResponse(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := multiplicativeTestSchedule(2, "PT1H")
+	schedule.ResetInterval = true
+	if err := addSchedule(schedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/reading", "")
+	scheduleContext := scheduleIdToContextMap[schedule.Id.Hex()]
+
+	var gaps []time.Duration
+	fire := func() {
+		before := scheduleContext.NextTime
+		executeSchedule(schedule.Id.Hex())
+		gaps = append(gaps, scheduleContext.NextTime.Sub(before))
+	}
+
+	//the empty responses let the interval grow, a response with a body brings it back to the frequency
+	fire()
+	fire()
+	client.response = `{"readings":3}`
+	fire()
+	client.response = ""
+	fire()
+
+	expected := []time.Duration{8 * time.Minute, 16 * time.Minute, 8 * time.Minute, 16 * time.Minute}
+	assertGaps(t, gaps, expected)
+}
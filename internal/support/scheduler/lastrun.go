@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LastRun is the result of the latest execution of a schedule event
+type LastRun struct {
+	Time          int64  `json:"time"`     // start of the execution in milliseconds since the epoch
+	Duration      int64  `json:"duration"` // length of the execution in milliseconds
+	StatusCode    int    `json:"statusCode"`
+	Error         string `json:"error,omitempty"`
+	RequestBytes  int64  `json:"requestBytes"`  // size of the body sent by the latest attempt
+	ResponseBytes int64  `json:"responseBytes"` // size of the body received by the latest attempt
+	RoundTripUs   int64  `json:"roundTripUs"`   // wall-clock time of the latest attempt in microseconds
+}
+
+// the events are executed outside of the schedule mutex so their results have a lock of their own
+var (
+	lastRunMutex                sync.RWMutex
+	scheduleEventIdToLastRunMap = make(map[string]LastRun) // map : schedule event id -> last run
+)
+
+func newLastRun(startTime time.Time, statusCode int, err error, stats *requestStats) LastRun {
+	lastRun := LastRun{
+		Time:          startTime.UnixNano() / int64(time.Millisecond),
+		Duration:      int64(clock.Now().Sub(startTime) / time.Millisecond),
+		StatusCode:    statusCode,
+		RequestBytes:  stats.requestBytes,
+		ResponseBytes: stats.responseBytes,
+		RoundTripUs:   int64(stats.roundTrip / time.Microsecond),
+	}
+	if err != nil {
+		lastRun.Error = err.Error()
+	}
+	return lastRun
+}
+
+func recordLastRun(scheduleEventId string, lastRun LastRun) {
+	lastRunMutex.Lock()
+	defer lastRunMutex.Unlock()
+	scheduleEventIdToLastRunMap[scheduleEventId] = lastRun
+}
+
+func removeLastRun(scheduleEventId string) {
+	lastRunMutex.Lock()
+	defer lastRunMutex.Unlock()
+	delete(scheduleEventIdToLastRunMap, scheduleEventId)
+}
+
+func clearLastRuns() {
+	lastRunMutex.Lock()
+	defer lastRunMutex.Unlock()
+	scheduleEventIdToLastRunMap = make(map[string]LastRun)
+}
+
+func queryLastRun(scheduleEventId string) (LastRun, error) {
+	if _, err := queryScheduleEvent(scheduleEventId); err != nil {
+		return LastRun{}, err
+	}
+
+	lastRunMutex.RLock()
+	defer lastRunMutex.RUnlock()
+	lastRun, exists := scheduleEventIdToLastRunMap[scheduleEventId]
+	if !exists {
+		return LastRun{}, fmt.Errorf("the schedule event with id : %s has not been executed yet", scheduleEventId)
+	}
+	return lastRun, nil
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestLastRunAfterExecution(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{statusCode: http.StatusAccepted}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+	executeSchedule(schedule.Id.Hex())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/scheduleevent/"+scheduleEvent.Id.Hex()+"/lastrun", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	var lastRun LastRun
+	if err := json.NewDecoder(rec.Body).Decode(&lastRun); err != nil {
+		t.Fatalf("unexpected error decoding the last run : %s", err.Error())
+	}
+	if lastRun.StatusCode != http.StatusAccepted {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, lastRun.StatusCode, http.StatusAccepted)
+	}
+	if lastRun.Time < before {
+		t.Errorf("last run time %d is before the execution started at %d", lastRun.Time, before)
+	}
+	if lastRun.Duration < 0 {
+		t.Errorf("unexpected negative duration %d", lastRun.Duration)
+	}
+	if lastRun.Error != "" {
+		t.Errorf(TestUnexpectedMsgFormatStr, lastRun.Error, "")
+	}
+}
+
+func TestLastRunRecordsRequestStats(t *testing.T) {
+	resetScheduler()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Write([]byte(`{"removed":42}`))
+	}))
+	defer server.Close()
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := models.ScheduleEvent{
+		Id:         bson.NewObjectId(),
+		Name:       TestScheduleEventName,
+		Schedule:   schedule.Name,
+		Parameters: `{"age":1}`,
+		Addressable: models.Addressable{
+			Name:       TestScheduleEventName,
+			Protocol:   "http",
+			HTTPMethod: http.MethodDelete,
+			Address:    serverUrl.Hostname(),
+			Port:       port,
+			Path:       "/api/v1/event/scrub",
+		},
+	}
+	if err := addScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	lastRun, err := queryLastRun(scheduleEvent.Id.Hex())
+	if err != nil {
+		t.Fatalf("unexpected error querying the last run : %s", err.Error())
+	}
+	history, _ := queryHistory(schedule.Id.Hex())
+	if len(history) != 1 || len(history[0].Events) != 1 {
+		t.Fatalf("expected a single execution of a single event, got %v", history)
+	}
+	for _, run := range []LastRun{lastRun, history[0].Events[0].LastRun} {
+		if run.RequestBytes != int64(len(`{"age":1}`)) {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, run.RequestBytes, len(`{"age":1}`))
+		}
+		if run.ResponseBytes != int64(len(`{"removed":42}`)) {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, run.ResponseBytes, len(`{"removed":42}`))
+		}
+		if run.RoundTripUs <= 0 {
+			t.Errorf("expected a round trip, got %d microseconds", run.RoundTripUs)
+		}
+	}
+}
+
+func TestLastRunRecordsError(t *testing.T) {
+	resetScheduler()
+	SetHTTPClient(&mockHTTPClient{})
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, "FETCH", "/api/v1/ping", "")
+
+	executeSchedule(schedule.Id.Hex())
+
+	lastRun, err := queryLastRun(scheduleEvent.Id.Hex())
+	if err != nil {
+		t.Fatalf("unexpected error querying the last run : %s", err.Error())
+	}
+	if lastRun.Error == "" {
+		t.Error("expected the last run to hold the execution error")
+	}
+}
+
+func TestLastRunNotFound(t *testing.T) {
+	resetScheduler()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	for _, id := range []string{bson.NewObjectId().Hex(), scheduleEvent.Id.Hex()} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/scheduleevent/"+id+"/lastrun", nil)
+		rec := httptest.NewRecorder()
+		LoadRestRoutes().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusNotFound)
+		}
+	}
+}
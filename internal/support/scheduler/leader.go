@@ -0,0 +1,163 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// the lease of the leader when LeaderLeaseMs is not configured
+const DefaultLeaderLeaseMs = 15000
+
+// LeaderLock is a lease shared by the replicas of the scheduler, held by at most one of them at a time.
+type LeaderLock interface {
+	// TryAcquire takes the lease for the instance, or extends it when the instance already holds it, and reports
+	// whether the instance holds the lease for the given duration.
+	TryAcquire(instanceId string, lease time.Duration) (bool, error)
+	// Release gives the lease up when it is held by the instance.
+	Release(instanceId string) error
+}
+
+// leaderElector tells whether this instance is the leader, the only one executing the schedules
+type leaderElector struct {
+	mutex      sync.Mutex
+	lock       LeaderLock
+	instanceId string
+	lease      time.Duration
+	expiresAt  time.Time
+	renewAt    time.Time
+}
+
+var (
+	electorMutex sync.Mutex
+	elector      *leaderElector
+)
+
+func newLeaderElector(lock LeaderLock, instanceId string, lease time.Duration) *leaderElector {
+	return &leaderElector{lock: lock, instanceId: instanceId, lease: lease}
+}
+
+// SetLeaderLock turns the leader election on, every replica sharing the lock runs the schedules in step but only
+// the leader executes them. A nil lock turns the election off. Init sets the lock of the configured Leader
+// database, the code embedding the scheduler may set another one before StartTicker.
+func SetLeaderLock(lock LeaderLock) {
+	electorMutex.Lock()
+	defer electorMutex.Unlock()
+
+	if lock == nil {
+		elector = nil
+		return
+	}
+	elector = newLeaderElector(lock, instanceId(), leaderLease())
+}
+
+// close the connection of the lock, once the leadership has been released
+func closeLeaderLock() {
+	electorMutex.Lock()
+	current := elector
+	electorMutex.Unlock()
+
+	if current == nil {
+		return
+	}
+	if closer, ok := current.lock.(interface {
+		Close()
+	}); ok {
+		closer.Close()
+	}
+}
+
+// Without an election the instance always leads
+func isLeading() bool {
+	electorMutex.Lock()
+	current := elector
+	electorMutex.Unlock()
+
+	if current == nil {
+		return true
+	}
+	return current.renew(clock.Now())
+}
+
+// give the lease up so a follower takes over without waiting for it to expire
+func releaseLeadership() {
+	electorMutex.Lock()
+	current := elector
+	electorMutex.Unlock()
+
+	if current != nil {
+		current.release()
+	}
+}
+
+func (e *leaderElector) isLeader(now time.Time) bool {
+	return now.Before(e.expiresAt)
+}
+
+// Try to take or extend the lease a few times per lease period and report whether the instance leads. When the
+// lock cannot be reached the leader keeps leading until its lease expires.
+func (e *leaderElector) renew(now time.Time) bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if now.Before(e.renewAt) {
+		return e.isLeader(now)
+	}
+	e.renewAt = now.Add(e.lease / 3)
+
+	acquired, err := e.lock.TryAcquire(e.instanceId, e.lease)
+	if err != nil {
+		LoggingClient.Warn(fmt.Sprintf("the instance %s failed to renew its leader lease : %s", e.instanceId, err.Error()))
+		return e.isLeader(now)
+	}
+
+	wasLeader := e.isLeader(now)
+	if acquired {
+		e.expiresAt = now.Add(e.lease)
+		if !wasLeader {
+			LoggingClient.Info(fmt.Sprintf("the instance %s is the leader, it executes the schedules", e.instanceId))
+		}
+	} else {
+		e.expiresAt = time.Time{}
+		if wasLeader {
+			LoggingClient.Info(fmt.Sprintf("the instance %s lost the leadership, it stops executing the schedules", e.instanceId))
+		}
+	}
+	return acquired
+}
+
+func (e *leaderElector) release() {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.expiresAt.IsZero() {
+		return
+	}
+	e.expiresAt = time.Time{}
+	e.renewAt = time.Time{}
+	if err := e.lock.Release(e.instanceId); err != nil {
+		LoggingClient.Warn(fmt.Sprintf("the instance %s failed to release its leader lease : %s", e.instanceId, err.Error()))
+	}
+}
+
+func leaderLease() time.Duration {
+	if Configuration == nil || Configuration.LeaderLeaseMs <= 0 {
+		return DefaultLeaderLeaseMs * time.Millisecond
+	}
+	return time.Duration(Configuration.LeaderLeaseMs) * time.Millisecond
+}
+
+// the configured instance id, a random one tells the replicas apart by default
+func instanceId() string {
+	if Configuration == nil || Configuration.InstanceID == "" {
+		return uuid.NewV4().String()
+	}
+	return Configuration.InstanceID
+}
@@ -0,0 +1,159 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockLeaderLock is an in-memory lease shared by the instances of a test
+type mockLeaderLock struct {
+	mutex     sync.Mutex
+	holder    string
+	expiresAt time.Time
+	err       error
+}
+
+func (l *mockLeaderLock) TryAcquire(instanceId string, lease time.Duration) (bool, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.err != nil {
+		return false, l.err
+	}
+	now := clock.Now()
+	if l.holder != "" && l.holder != instanceId && now.Before(l.expiresAt) {
+		return false, nil
+	}
+	l.holder = instanceId
+	l.expiresAt = now.Add(lease)
+	return true, nil
+}
+
+func (l *mockLeaderLock) Release(instanceId string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.holder == instanceId {
+		l.holder = ""
+	}
+	return nil
+}
+
+// tick as the given instance and report how many requests it sent
+func tickAs(instance *leaderElector, client *mockHTTPClient) int {
+	electorMutex.Lock()
+	elector = instance
+	electorMutex.Unlock()
+
+	before := len(client.requests)
+	triggerSchedule()
+	waitForExecutions()
+	return len(client.requests) - before
+}
+
+func TestOnlyTheLeaderFires(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+	defer SetLeaderLock(nil)
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	lock := &mockLeaderLock{}
+	first := newLeaderElector(lock, "first", 3*time.Minute)
+	second := newLeaderElector(lock, "second", 3*time.Minute)
+
+	scheduleId := addClockTestSchedule(t, TestScheduleName, "20180101T000100", "PT1M")
+	addTestScheduleEvent(t, scheduleIdToContextMap[scheduleId].Schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	fires := map[string]int{}
+	for i := 0; i < 6; i++ {
+		fake.Advance(time.Minute)
+		instance := first
+		if i%2 == 1 {
+			instance = second
+		}
+		fires[instance.instanceId] += tickAs(instance, client)
+	}
+
+	if fires["first"] != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, fires["first"], 3)
+	}
+	if fires["second"] != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, fires["second"], 0)
+	}
+	//the follower moved the schedule on instead of leaving the fire to be caught up later
+	if expected := time.Date(2018, 1, 1, 0, 7, 0, 0, time.UTC); !scheduleIdToContextMap[scheduleId].NextTime.Equal(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleIdToContextMap[scheduleId].NextTime, expected)
+	}
+}
+
+func TestLeaderFailover(t *testing.T) {
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+
+	lock := &mockLeaderLock{}
+	first := newLeaderElector(lock, "first", 30*time.Second)
+	second := newLeaderElector(lock, "second", 30*time.Second)
+
+	if !first.renew(fake.Now()) || second.renew(fake.Now()) {
+		t.Fatal("expected the first instance to lead")
+	}
+
+	//the leader goes away without releasing the lease, the follower takes over once it expires
+	fake.Advance(20 * time.Second)
+	if second.renew(fake.Now()) {
+		t.Error("expected the follower to wait for the lease to expire")
+	}
+	fake.Advance(15 * time.Second)
+	if !second.renew(fake.Now()) {
+		t.Error("expected the follower to take over the expired lease")
+	}
+	if first.renew(fake.Now()) {
+		t.Error("expected the former leader to follow")
+	}
+
+	//releasing the lease hands it over on the next renewal
+	second.release()
+	fake.Advance(10 * time.Second)
+	if !first.renew(fake.Now()) {
+		t.Error("expected the released lease to be taken over")
+	}
+}
+
+func TestLeaderKeepsLeadingUntilLeaseExpiresWhenLockFails(t *testing.T) {
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+
+	lock := &mockLeaderLock{}
+	leader := newLeaderElector(lock, "leader", 30*time.Second)
+	if !leader.renew(fake.Now()) {
+		t.Fatal("expected the instance to lead")
+	}
+
+	lock.err = errors.New("lock unreachable")
+	fake.Advance(20 * time.Second)
+	if !leader.renew(fake.Now()) {
+		t.Error("expected the leader to keep leading within its lease")
+	}
+	fake.Advance(15 * time.Second)
+	if leader.renew(fake.Now()) {
+		t.Error("expected the leader to stop leading once its lease expired")
+	}
+}
+
+func TestLeadingWithoutElection(t *testing.T) {
+	SetLeaderLock(nil)
+	if !isLeading() {
+		t.Error("expected the instance to lead without an election")
+	}
+}
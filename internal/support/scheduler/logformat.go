@@ -0,0 +1,61 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// the fields of an execution log line, written as a json object when JSONLogging is set
+type logFields struct {
+	Msg           string `json:"msg"`
+	ScheduleId    string `json:"scheduleId,omitempty"`
+	EventId       string `json:"eventId,omitempty"`
+	StatusCode    int    `json:"statusCode,omitempty"`
+	DurationMs    *int64 `json:"durationMs,omitempty"`
+	CorrelationId string `json:"correlationId,omitempty"`
+}
+
+func jsonLogging() bool {
+	return Configuration != nil && Configuration.JSONLogging
+}
+
+func (f logFields) withDuration(duration time.Duration) logFields {
+	durationMs := int64(duration / time.Millisecond)
+	f.DurationMs = &durationMs
+	return f
+}
+
+// Format the log line from its fields. The plain format is the correlation id followed by the message and
+// the fields which are set.
+func formatLogMsg(fields logFields) string {
+	if jsonLogging() {
+		data, err := json.Marshal(fields)
+		if err == nil {
+			return string(data)
+		}
+	}
+
+	msg := fields.Msg
+	if fields.CorrelationId != "" {
+		msg = fmt.Sprintf("%s: %s %s", CorrelationHeader, fields.CorrelationId, msg)
+	}
+	if fields.ScheduleId != "" {
+		msg += ", schedule id : " + fields.ScheduleId
+	}
+	if fields.EventId != "" {
+		msg += ", event id : " + fields.EventId
+	}
+	if fields.StatusCode != 0 {
+		msg += fmt.Sprintf(", status code : %d", fields.StatusCode)
+	}
+	if fields.DurationMs != nil {
+		msg += fmt.Sprintf(", duration : %d ms", *fields.DurationMs)
+	}
+	return msg
+}
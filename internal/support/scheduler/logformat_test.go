@@ -0,0 +1,73 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+)
+
+func TestJSONLogging(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{statusCode: http.StatusAccepted}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+	Configuration.JSONLogging = true
+	defer func() { Configuration.JSONLogging = false }()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodPost, "/api/v1/event/scrub", "")
+
+	logs := &captureLogger{}
+	LoggingClient = logs
+	defer func() { LoggingClient = logger.NewMockClient() }()
+
+	executeSchedule(schedule.Id.Hex())
+
+	var executed *logFields
+	for _, msg := range logs.messages {
+		var fields logFields
+		if err := json.Unmarshal([]byte(msg), &fields); err != nil {
+			t.Errorf("expected a json log line, got %q : %s", msg, err.Error())
+			continue
+		}
+		if fields.Msg == "executed the schedule event" {
+			executed = &fields
+		}
+	}
+	if executed == nil {
+		t.Fatal("expected a log line for the executed schedule event")
+	}
+	if executed.ScheduleId != schedule.Id.Hex() {
+		t.Errorf(TestUnexpectedMsgFormatStr, executed.ScheduleId, schedule.Id.Hex())
+	}
+	if executed.EventId != scheduleEvent.Id.Hex() {
+		t.Errorf(TestUnexpectedMsgFormatStr, executed.EventId, scheduleEvent.Id.Hex())
+	}
+	if executed.StatusCode != http.StatusAccepted {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, executed.StatusCode, http.StatusAccepted)
+	}
+	if executed.DurationMs == nil {
+		t.Error("expected the duration of the schedule event")
+	}
+	if len(client.requests) != 1 || executed.CorrelationId != client.requests[0].Header.Get(CorrelationHeader) {
+		t.Errorf("expected the correlation id of the request, got %q", executed.CorrelationId)
+	}
+}
+
+func TestPlainLoggingByDefault(t *testing.T) {
+	msg := formatLogMsg(logFields{Msg: "executed the schedule event", EventId: "5bc3c18fa493823224c12eb2", StatusCode: http.StatusOK, CorrelationId: "abc"}.withDuration(0))
+	expected := CorrelationHeader + ": abc executed the schedule event, event id : 5bc3c18fa493823224c12eb2, status code : 200, duration : 0 ms"
+	if msg != expected {
+		t.Errorf(TestUnexpectedMsgFormatStr, msg, expected)
+	}
+	if msg := executionLogMsg("abc", "requeue schedule"); msg != CorrelationHeader+": abc requeue schedule" {
+		t.Errorf(TestUnexpectedMsgFormatStr, msg, CorrelationHeader+": abc requeue schedule")
+	}
+}
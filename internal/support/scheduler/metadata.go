@@ -0,0 +1,64 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"os"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/internal"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ScheduleClient is the part of the core-metadata schedule client used by the scheduler
+type ScheduleClient interface {
+	Add(schedule *models.Schedule) (string, error)
+	Schedules() ([]models.Schedule, error)
+	Update(schedule models.Schedule) error
+	Delete(id string) error
+}
+
+// ScheduleEventClient is the part of the core-metadata schedule event client used by the scheduler
+type ScheduleEventClient interface {
+	Add(scheduleEvent *models.ScheduleEvent) (string, error)
+	ScheduleEvents() ([]models.ScheduleEvent, error)
+	Delete(id string) error
+}
+
+// AddressableClient is the part of the core-metadata addressable client used by the scheduler
+type AddressableClient interface {
+	Add(addressable *models.Addressable) (string, error)
+	AddressableForName(name string) (models.Addressable, error)
+}
+
+// the id recorded as the originator of the schedules and schedule events added to core-metadata, the configured
+// OriginatorID or InstanceID, otherwise the service key along with the host name
+func originatorId() string {
+	if Configuration != nil && Configuration.OriginatorID != "" {
+		return Configuration.OriginatorID
+	}
+	if Configuration != nil && Configuration.InstanceID != "" {
+		return Configuration.InstanceID
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		return internal.SupportSchedulerServiceKey
+	}
+	return internal.SupportSchedulerServiceKey + "@" + hostname
+}
+
+// Convert an id returned by core-metadata into an ObjectId. Core-metadata answers with the hex form of the id, the
+// raw 12 bytes of an ObjectId are accepted as well while anything else is malformed.
+func metadataObjectId(kind string, name string, id string) (bson.ObjectId, error) {
+	if hex := strings.TrimSpace(id); bson.IsObjectIdHex(hex) {
+		return bson.ObjectIdHex(hex), nil
+	}
+	if raw := bson.ObjectId(id); raw.Valid() {
+		return raw, nil
+	}
+	return "", ErrMalformedId{Kind: kind, Name: name, Id: id}
+}
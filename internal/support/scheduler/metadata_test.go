@@ -0,0 +1,428 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/types"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// fakeScheduleClient serves the schedules of core-metadata and keeps the added ones
+type fakeScheduleClient struct {
+	schedules []models.Schedule
+	err       error
+	failures  int // number of calls failing before the schedules are served
+	calls     int
+	delay     time.Duration
+	deleted   chan string // receives the ids of the deleted schedules when set
+}
+
+func (c *fakeScheduleClient) Schedules() ([]models.Schedule, error) {
+	time.Sleep(c.delay)
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, errors.New("core-metadata is unreachable")
+	}
+	return c.schedules, c.err
+}
+
+func (c *fakeScheduleClient) Add(schedule *models.Schedule) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	schedule.Id = bson.NewObjectId()
+	c.schedules = append(c.schedules, *schedule)
+	return schedule.Id.Hex(), nil
+}
+
+func (c *fakeScheduleClient) Update(schedule models.Schedule) error {
+	if c.err != nil {
+		return c.err
+	}
+	for i := range c.schedules {
+		if c.schedules[i].Id == schedule.Id {
+			c.schedules[i] = schedule
+			return nil
+		}
+	}
+	return errors.New("schedule not found")
+}
+
+func (c *fakeScheduleClient) Delete(id string) error {
+	if c.deleted != nil {
+		c.deleted <- id
+	}
+	if c.err != nil {
+		return c.err
+	}
+	for i := range c.schedules {
+		if c.schedules[i].Id.Hex() == id {
+			c.schedules = append(c.schedules[:i], c.schedules[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// fakeScheduleEventClient serves the schedule events of core-metadata and keeps the added ones
+type fakeScheduleEventClient struct {
+	scheduleEvents []models.ScheduleEvent
+	err            error
+}
+
+func (c *fakeScheduleEventClient) ScheduleEvents() ([]models.ScheduleEvent, error) {
+	return c.scheduleEvents, nil
+}
+
+func (c *fakeScheduleEventClient) Add(scheduleEvent *models.ScheduleEvent) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+	scheduleEvent.Id = bson.NewObjectId()
+	c.scheduleEvents = append(c.scheduleEvents, *scheduleEvent)
+	return scheduleEvent.Id.Hex(), nil
+}
+
+func (c *fakeScheduleEventClient) Delete(id string) error {
+	if c.err != nil {
+		return c.err
+	}
+	for i := range c.scheduleEvents {
+		if c.scheduleEvents[i].Id.Hex() == id {
+			c.scheduleEvents = append(c.scheduleEvents[:i], c.scheduleEvents[i+1:]...)
+			return nil
+		}
+	}
+	return types.NewErrServiceClient(http.StatusNotFound, []byte("schedule event not found"))
+}
+
+// fakeAddressableClient keeps the addressables added to core-metadata
+type fakeAddressableClient struct {
+	addressables []models.Addressable
+	id           string // answered by the adds instead of the id of the addressable when set
+}
+
+func (c *fakeAddressableClient) AddressableForName(name string) (models.Addressable, error) {
+	for _, addressable := range c.addressables {
+		if addressable.Name == name {
+			return addressable, nil
+		}
+	}
+	return models.Addressable{}, errors.New("addressable not found")
+}
+
+func (c *fakeAddressableClient) Add(addressable *models.Addressable) (string, error) {
+	addressable.Id = bson.NewObjectId()
+	c.addressables = append(c.addressables, *addressable)
+	if c.id != "" {
+		return c.id, nil
+	}
+	return addressable.Id.Hex(), nil
+}
+
+// useFakeMetadataClients replaces the core-metadata clients until the returned function is called
+func useFakeMetadataClients() (*fakeScheduleClient, *fakeScheduleEventClient, *fakeAddressableClient, func()) {
+	scheduleClient := &fakeScheduleClient{}
+	scheduleEventClient := &fakeScheduleEventClient{}
+	addressableClient := &fakeAddressableClient{}
+	msc, msec, mac = scheduleClient, scheduleEventClient, addressableClient
+	return scheduleClient, scheduleEventClient, addressableClient, func() {
+		msc, msec, mac = nil, nil, nil
+	}
+}
+
+func TestLoadConfigSchedules(t *testing.T) {
+	resetScheduler()
+	scheduleClient, _, _, restore := useFakeMetadataClients()
+	defer restore()
+
+	existing := addTestSchedule(t, "existing")
+	Configuration.Schedules = map[string]config.ScheduleInfo{
+		"Midnight": {Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D"},
+		"Existing": {Name: existing.Name, Start: "20180101T000000", Frequency: "PT1H"},
+	}
+	defer func() { Configuration.Schedules = nil }()
+
+	if err := loadConfigSchedules(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	//only the new schedule is added to core-metadata
+	if len(scheduleClient.schedules) != 1 || scheduleClient.schedules[0].Name != TestScheduleName {
+		t.Errorf("unexpected schedules in core-metadata : %v", scheduleClient.schedules)
+	}
+	if _, err := queryScheduleByName(TestScheduleName); err != nil {
+		t.Errorf("the new schedule should be loaded : %s", err.Error())
+	}
+	schedule, _ := queryScheduleByName(existing.Name)
+	if schedule.Frequency != existing.Frequency {
+		t.Errorf(TestUnexpectedMsgFormatStr, schedule.Frequency, existing.Frequency)
+	}
+}
+
+func TestLoadConfigScheduleEvents(t *testing.T) {
+	resetScheduler()
+	_, scheduleEventClient, addressableClient, restore := useFakeMetadataClients()
+	defer restore()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	existing := addTestScheduleEvent(t, schedule, "existing", http.MethodGet, "/api/v1/ping", "")
+	addressableClient.addressables = []models.Addressable{{Id: bson.NewObjectId(), Name: "schedule-" + TestScheduleEventName}}
+
+	Configuration.ScheduleEvents = map[string]config.ScheduleEventInfo{
+		"New":      {Name: TestScheduleEventName, Schedule: TestScheduleName, Host: "localhost", Port: 48080, Protocol: "http", Method: http.MethodGet, Path: "/api/v1/ping"},
+		"Other":    {Name: "other", Schedule: TestScheduleName, Host: "localhost", Port: 48080, Protocol: "http", Method: http.MethodGet, Path: "/api/v1/ping"},
+		"Existing": {Name: existing.Name, Schedule: TestScheduleName, Host: "localhost", Port: 48080, Protocol: "http", Method: http.MethodPost, Path: "/api/v1/changed"},
+	}
+	defer func() { Configuration.ScheduleEvents = nil }()
+
+	if err := loadConfigScheduleEvents(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	if len(scheduleEventClient.scheduleEvents) != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(scheduleEventClient.scheduleEvents), 2)
+	}
+	//the addressable of the first event was already in core-metadata
+	if len(addressableClient.addressables) != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(addressableClient.addressables), 2)
+	}
+	for _, name := range []string{TestScheduleEventName, "other"} {
+		if _, err := queryScheduleEventByName(name); err != nil {
+			t.Errorf("the schedule event %s should be loaded : %s", name, err.Error())
+		}
+	}
+	scheduleEvent, _ := queryScheduleEventByName(existing.Name)
+	if scheduleEvent.Addressable.Path != existing.Addressable.Path {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleEvent.Addressable.Path, existing.Addressable.Path)
+	}
+}
+
+func TestMetadataAddsCarryTheOriginator(t *testing.T) {
+	resetScheduler()
+	scheduleClient, scheduleEventClient, _, restore := useFakeMetadataClients()
+	defer restore()
+
+	Configuration.OriginatorID = "scheduler-a"
+	Configuration.Schedules = map[string]config.ScheduleInfo{
+		"Midnight": {Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D"},
+	}
+	Configuration.ScheduleEvents = map[string]config.ScheduleEventInfo{
+		"Ping": {Name: TestScheduleEventName, Schedule: TestScheduleName, Host: "localhost", Port: 48080, Protocol: "http", Method: http.MethodGet, Path: "/api/v1/ping"},
+	}
+	defer func() {
+		Configuration.OriginatorID = ""
+		Configuration.Schedules = nil
+		Configuration.ScheduleEvents = nil
+	}()
+
+	if err := loadConfigSchedules(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if err := loadConfigScheduleEvents(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	if len(scheduleClient.schedules) != 1 || scheduleClient.schedules[0].Originator != "scheduler-a" {
+		t.Errorf("expected the schedule to be added with its originator, got %v", scheduleClient.schedules)
+	}
+	if len(scheduleEventClient.scheduleEvents) != 1 || scheduleEventClient.scheduleEvents[0].Originator != "scheduler-a" {
+		t.Errorf("expected the schedule event to be added with its originator, got %v", scheduleEventClient.scheduleEvents)
+	}
+	//the scheduler keeps the same version as core-metadata so a reload finds no change
+	if schedule, _ := queryScheduleByName(TestScheduleName); schedule.Originator != "scheduler-a" {
+		t.Errorf(TestUnexpectedMsgFormatStr, schedule.Originator, "scheduler-a")
+	}
+	if scheduleEvent, _ := queryScheduleEventByName(TestScheduleEventName); scheduleEvent.Originator != "scheduler-a" {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleEvent.Originator, "scheduler-a")
+	}
+}
+
+func TestDefaultOriginatorID(t *testing.T) {
+	Configuration.InstanceID = "replica-2"
+	defer func() { Configuration.InstanceID = "" }()
+	if id := originatorId(); id != "replica-2" {
+		t.Errorf(TestUnexpectedMsgFormatStr, id, "replica-2")
+	}
+
+	Configuration.InstanceID = ""
+	if id := originatorId(); !strings.HasPrefix(id, internal.SupportSchedulerServiceKey) {
+		t.Errorf("expected the originator %s to start with the service key", id)
+	}
+	if originatorId() != originatorId() {
+		t.Error("expected the default originator to be stable")
+	}
+}
+
+// idFormatScheduleClient answers the adds with the id in the given format
+type idFormatScheduleClient struct {
+	fakeScheduleClient
+	format func(id bson.ObjectId) string
+}
+
+func (c *idFormatScheduleClient) Add(schedule *models.Schedule) (string, error) {
+	if _, err := c.fakeScheduleClient.Add(schedule); err != nil {
+		return "", err
+	}
+	return c.format(schedule.Id), nil
+}
+
+func TestLoadConfigSchedulesConvertsTheMetadataId(t *testing.T) {
+	formats := map[string]func(id bson.ObjectId) string{
+		"hex": func(id bson.ObjectId) string { return id.Hex() },
+		"raw": func(id bson.ObjectId) string { return string(id) },
+	}
+	for name, format := range formats {
+		resetScheduler()
+		scheduleClient := &idFormatScheduleClient{format: format}
+		msc = scheduleClient
+		Configuration.Schedules = map[string]config.ScheduleInfo{
+			"Midnight": {Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D"},
+		}
+
+		if err := loadConfigSchedules(); err != nil {
+			t.Fatalf("unexpected error loading the schedule with a %s id : %s", name, err.Error())
+		}
+		expected := scheduleClient.schedules[0].Id.Hex()
+		schedule, err := querySchedule(expected)
+		if err != nil {
+			t.Errorf("the schedule with a %s id should be found by its id : %s", name, err.Error())
+		} else if schedule.Id.Hex() != expected {
+			t.Errorf(TestUnexpectedMsgFormatStr, schedule.Id.Hex(), expected)
+		}
+	}
+	msc = nil
+	Configuration.Schedules = nil
+}
+
+func TestLoadConfigSchedulesRejectsMalformedMetadataId(t *testing.T) {
+	resetScheduler()
+	msc = &idFormatScheduleClient{format: func(id bson.ObjectId) string { return "not-an-id" }}
+	defer func() { msc = nil }()
+	Configuration.Schedules = map[string]config.ScheduleInfo{
+		"Midnight": {Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D"},
+	}
+	defer func() { Configuration.Schedules = nil }()
+
+	err := loadConfigSchedules()
+	if err == nil || !strings.Contains(err.Error(), `malformed id "not-an-id" for the schedule `+TestScheduleName) {
+		t.Errorf("expected an error for the malformed id, got %v", err)
+	}
+	if _, err := queryScheduleByName(TestScheduleName); err == nil {
+		t.Error("the schedule with a malformed id should not be loaded")
+	}
+}
+
+func TestAddScheduleEventRejectsMalformedAddressableId(t *testing.T) {
+	resetScheduler()
+	_, scheduleEventClient, addressableClient, restore := useFakeMetadataClients()
+	defer restore()
+	addressableClient.id = "not-an-id"
+	addTestSchedule(t, TestScheduleName)
+
+	body := `{"name":"` + TestScheduleEventName + `","schedule":"` + TestScheduleName + `",` +
+		`"addressable":{"name":"` + TestScheduleEventName + `","protocol":"http","method":"GET","address":"localhost","port":48080,"path":"/api/v1/ping"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/scheduleevent", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusInternalServerError)
+	}
+	if len(scheduleEventClient.scheduleEvents) != 0 {
+		t.Errorf("the schedule event should not be added to core-metadata : %v", scheduleEventClient.scheduleEvents)
+	}
+	if _, err := queryScheduleEventByName(TestScheduleEventName); err == nil {
+		t.Error("the schedule event with a malformed addressable id should not be loaded")
+	}
+
+	err := addAddressableToCoreMetadata(&models.Addressable{Name: "other"})
+	if _, ok := err.(ErrMalformedId); !ok {
+		t.Errorf("expected an ErrMalformedId, got %v", err)
+	}
+}
+
+func TestMetadataObjectId(t *testing.T) {
+	id := bson.NewObjectId()
+	for _, received := range []string{id.Hex(), id.Hex() + "\n", string(id)} {
+		converted, err := metadataObjectId("schedule", TestScheduleName, received)
+		if err != nil {
+			t.Errorf("unexpected error converting %q : %s", received, err.Error())
+		} else if converted != id {
+			t.Errorf(TestUnexpectedMsgFormatStr, converted.Hex(), id.Hex())
+		}
+	}
+	for _, received := range []string{"", "5bc3c18f", id.Hex() + "00"} {
+		if _, err := metadataObjectId("schedule", TestScheduleName, received); err == nil {
+			t.Errorf("expected an error converting %q", received)
+		}
+	}
+}
+
+func TestGetMetadataSchedules(t *testing.T) {
+	schedules := []models.Schedule{{Id: bson.NewObjectId(), Name: TestScheduleName}}
+	msc = &fakeScheduleClient{schedules: schedules}
+	defer func() { msc = nil }()
+
+	received, err := getMetadataSchedules()
+	if err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if len(received) != 1 || received[0].Name != TestScheduleName {
+		t.Errorf("unexpected schedules : %v", received)
+	}
+
+	msc = &fakeScheduleClient{err: errors.New("core-metadata is unreachable")}
+	if _, err := getMetadataSchedules(); err == nil {
+		t.Error("expected the error of core-metadata")
+	}
+}
+
+func TestLoadCoreMetadataSkipsTheSchedulesWhichFailToAdd(t *testing.T) {
+	resetScheduler()
+	valid := models.Schedule{Id: bson.NewObjectId(), Name: "valid", Start: "20180101T000000", Frequency: "P1D"}
+	invalid := models.Schedule{Id: bson.NewObjectId(), Name: "invalid", Start: "20180301T000000", End: "20180101T000000", Frequency: "P1D"}
+	scheduleEvents := []models.ScheduleEvent{
+		{Id: bson.NewObjectId(), Name: "valid-ping", Schedule: valid.Name, Service: "core-data"},
+		{Id: bson.NewObjectId(), Name: "invalid-ping", Schedule: invalid.Name, Service: "core-data"},
+	}
+	msc = &fakeScheduleClient{schedules: []models.Schedule{invalid, valid}}
+	msec = &fakeScheduleEventClient{scheduleEvents: scheduleEvents}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	if err := loadCoreMetadataInformation(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	if length := scheduleQueue.Length(); length != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, length, 1)
+	}
+	if _, err := queryScheduleByName(valid.Name); err != nil {
+		t.Errorf("the valid schedule should be queued : %s", err.Error())
+	}
+	if _, err := queryScheduleEventByName("valid-ping"); err != nil {
+		t.Errorf("the event of the valid schedule should be loaded : %s", err.Error())
+	}
+	if _, err := queryScheduleByName(invalid.Name); err == nil {
+		t.Error("the invalid schedule should not be queued")
+	}
+	if _, err := queryScheduleEventByName("invalid-ping"); err == nil {
+		t.Error("the event of the invalid schedule should not be loaded")
+	}
+}
@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PrometheusContentType is the version of the text exposition format served on /metrics
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Metrics is fed by the ticks and the executions of the scheduler. The Prometheus registry served on /metrics is
+// used unless another backend is set with SetMetrics.
+type Metrics interface {
+	// SetQueueState is called after every tick with the queued and the tracked schedules
+	SetQueueState(queueLength int, schedules int)
+	// ObserveExecution is called once the events of an execution have run
+	ObserveExecution(scheduleId string, duration time.Duration)
+	// IncFailure is called for every failed event, the status class is 4xx, 5xx and so on or error without a response
+	IncFailure(statusClass string)
+}
+
+// the buckets in seconds of the execution duration histogram
+var executionDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+var (
+	metricsMutex sync.RWMutex
+	metrics      Metrics = newPrometheusMetrics()
+)
+
+// SetMetrics replaces the backend of the metrics, a nil backend restores the Prometheus registry
+func SetMetrics(m Metrics) {
+	metricsMutex.Lock()
+	defer metricsMutex.Unlock()
+
+	if m == nil {
+		m = newPrometheusMetrics()
+	}
+	metrics = m
+}
+
+func getMetrics() Metrics {
+	metricsMutex.RLock()
+	defer metricsMutex.RUnlock()
+	return metrics
+}
+
+// the status class of a failed event, the failures without a response are errors
+func statusClass(statusCode int) string {
+	if statusCode < 100 {
+		return "error"
+	}
+	return fmt.Sprintf("%dxx", statusCode/100)
+}
+
+type prometheusMetrics struct {
+	mutex        sync.Mutex
+	queueLength  int
+	schedules    int
+	executions   uint64
+	failures     map[string]uint64
+	bucketCounts []uint64
+	durationSum  float64
+}
+
+func newPrometheusMetrics() *prometheusMetrics {
+	return &prometheusMetrics{
+		failures:     make(map[string]uint64),
+		bucketCounts: make([]uint64, len(executionDurationBuckets)),
+	}
+}
+
+func (m *prometheusMetrics) SetQueueState(queueLength int, schedules int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.queueLength = queueLength
+	m.schedules = schedules
+}
+
+func (m *prometheusMetrics) ObserveExecution(scheduleId string, duration time.Duration) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	seconds := duration.Seconds()
+	m.executions++
+	m.durationSum += seconds
+	for i, bound := range executionDurationBuckets {
+		if seconds <= bound {
+			m.bucketCounts[i]++
+		}
+	}
+}
+
+func (m *prometheusMetrics) IncFailure(statusClass string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.failures[statusClass]++
+}
+
+// Write the metrics in the Prometheus text exposition format
+func (m *prometheusMetrics) write(w io.Writer) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	writeMetricHeader(w, "scheduler_queue_length", "Number of schedules waiting in the queue.", "gauge")
+	fmt.Fprintf(w, "scheduler_queue_length %d\n", m.queueLength)
+
+	writeMetricHeader(w, "scheduler_schedules", "Number of schedules tracked by the scheduler.", "gauge")
+	fmt.Fprintf(w, "scheduler_schedules %d\n", m.schedules)
+
+	writeMetricHeader(w, "scheduler_executions_total", "Total number of schedule executions.", "counter")
+	fmt.Fprintf(w, "scheduler_executions_total %d\n", m.executions)
+
+	writeMetricHeader(w, "scheduler_event_failures_total", "Total number of failed schedule events by status class.", "counter")
+	classes := make([]string, 0, len(m.failures))
+	for class := range m.failures {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+	for _, class := range classes {
+		fmt.Fprintf(w, "scheduler_event_failures_total{class=%q} %d\n", class, m.failures[class])
+	}
+
+	writeMetricHeader(w, "scheduler_execution_duration_seconds", "Duration of the schedule executions in seconds.", "histogram")
+	for i, bound := range executionDurationBuckets {
+		fmt.Fprintf(w, "scheduler_execution_duration_seconds_bucket{le=%q} %d\n", strconv.FormatFloat(bound, 'g', -1, 64), m.bucketCounts[i])
+	}
+	fmt.Fprintf(w, "scheduler_execution_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.executions)
+	fmt.Fprintf(w, "scheduler_execution_duration_seconds_sum %s\n", strconv.FormatFloat(m.durationSum, 'g', -1, 64))
+	fmt.Fprintf(w, "scheduler_execution_duration_seconds_count %d\n", m.executions)
+}
+
+func writeMetricHeader(w io.Writer, name string, help string, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
@@ -0,0 +1,132 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	queueLengths []int
+	executions   []string
+	failures     []string
+}
+
+func (m *recordingMetrics) SetQueueState(queueLength int, schedules int) {
+	m.queueLengths = append(m.queueLengths, queueLength)
+}
+
+func (m *recordingMetrics) ObserveExecution(scheduleId string, duration time.Duration) {
+	m.executions = append(m.executions, scheduleId)
+}
+
+func (m *recordingMetrics) IncFailure(statusClass string) {
+	m.failures = append(m.failures, statusClass)
+}
+
+func scrapeMetrics(t *testing.T) string {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	if contentType := rec.Header().Get(ContentTypeKey); contentType != PrometheusContentType {
+		t.Errorf(TestUnexpectedMsgFormatStr, contentType, PrometheusContentType)
+	}
+	return rec.Body.String()
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	resetScheduler()
+	SetMetrics(nil)
+	fake, restore := useFakeClock(time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{statusCode: http.StatusServiceUnavailable}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	addTestSchedule(t, "idle")
+
+	fake.Advance(2 * time.Second)
+	triggerSchedule()
+	waitForExecutions()
+	triggerSchedule()
+
+	body := scrapeMetrics(t)
+	expected := []string{
+		"# HELP scheduler_queue_length Number of schedules waiting in the queue.",
+		"# TYPE scheduler_queue_length gauge",
+		"scheduler_queue_length 2",
+		"# HELP scheduler_schedules Number of schedules tracked by the scheduler.",
+		"# TYPE scheduler_schedules gauge",
+		"scheduler_schedules 2",
+		"# HELP scheduler_executions_total Total number of schedule executions.",
+		"# TYPE scheduler_executions_total counter",
+		"scheduler_executions_total 2",
+		"# HELP scheduler_event_failures_total Total number of failed schedule events by status class.",
+		"# TYPE scheduler_event_failures_total counter",
+		`scheduler_event_failures_total{class="5xx"} 1`,
+		"# HELP scheduler_execution_duration_seconds Duration of the schedule executions in seconds.",
+		"# TYPE scheduler_execution_duration_seconds histogram",
+		`scheduler_execution_duration_seconds_bucket{le="0.005"} 2`,
+		`scheduler_execution_duration_seconds_bucket{le="+Inf"} 2`,
+		"scheduler_execution_duration_seconds_count 2",
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("expected the metrics to contain %q, got\n%s", line, body)
+		}
+	}
+}
+
+func TestMetricsBackend(t *testing.T) {
+	resetScheduler()
+	backend := &recordingMetrics{}
+	SetMetrics(backend)
+	defer SetMetrics(nil)
+	client := &mockHTTPClient{statusCode: http.StatusInternalServerError}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	executeSchedule(schedule.Id.Hex())
+	triggerSchedule()
+
+	if len(backend.executions) != 1 || backend.executions[0] != schedule.Id.Hex() {
+		t.Errorf(TestUnexpectedMsgFormatStr, backend.executions, []string{schedule.Id.Hex()})
+	}
+	if len(backend.failures) != 1 || backend.failures[0] != "5xx" {
+		t.Errorf(TestUnexpectedMsgFormatStr, backend.failures, []string{"5xx"})
+	}
+	if len(backend.queueLengths) != 1 || backend.queueLengths[0] != scheduleQueue.Length() {
+		t.Errorf("expected the tick to report the queue length %d, got %v", scheduleQueue.Length(), backend.queueLengths)
+	}
+
+	//the Prometheus registry is not fed anymore
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestStatusClass(t *testing.T) {
+	tests := map[int]string{0: "error", 200: "2xx", 404: "4xx", 503: "5xx"}
+	for statusCode, expected := range tests {
+		if class := statusClass(statusCode); class != expected {
+			t.Errorf(TestUnexpectedMsgFormatStr, class, expected)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/db"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// LeaderLockDatabase is the key of the Databases entry holding the leader lock, the election is off without it
+const LeaderLockDatabase = "Leader"
+
+// LeaderLockCollection is the Mongo collection holding the lease of the leader
+const LeaderLockCollection = "schedulerLeader"
+
+// mongoLeaderLock keeps the lease of the leader in a single document of a Mongo collection shared by the replicas.
+// The lease expires by the clock of the replicas, which must be kept in sync.
+type mongoLeaderLock struct {
+	session  *mgo.Session
+	database string
+	key      string
+}
+
+func newMongoLeaderLock(info config.DatabaseInfo) (*mongoLeaderLock, error) {
+	session, err := mgo.DialWithInfo(&mgo.DialInfo{
+		Addrs:    []string{info.Host + ":" + strconv.Itoa(info.Port)},
+		Timeout:  time.Duration(info.Timeout) * time.Millisecond,
+		Database: info.Name,
+		Username: info.Username,
+		Password: info.Password,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mongoLeaderLock{session: session, database: info.Name, key: internal.SupportSchedulerServiceKey}, nil
+}
+
+// Take the lease when it is free or expired, or extend it when the instance holds it. The lease held by another
+// instance makes the upsert insert a second document with the same id, which fails as a duplicate.
+func (l *mongoLeaderLock) TryAcquire(instanceId string, lease time.Duration) (bool, error) {
+	s := l.session.Copy()
+	defer s.Close()
+
+	now := time.Now()
+	selector := bson.M{
+		"_id": l.key,
+		"$or": []bson.M{{"holder": instanceId}, {"expiresAt": bson.M{"$lte": now}}},
+	}
+	update := bson.M{"$set": bson.M{"holder": instanceId, "expiresAt": now.Add(lease)}}
+	if _, err := s.DB(l.database).C(LeaderLockCollection).Upsert(selector, update); err != nil {
+		if mgo.IsDup(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *mongoLeaderLock) Release(instanceId string) error {
+	s := l.session.Copy()
+	defer s.Close()
+
+	err := s.DB(l.database).C(LeaderLockCollection).Remove(bson.M{"_id": l.key, "holder": instanceId})
+	if err == mgo.ErrNotFound {
+		return nil
+	}
+	return err
+}
+
+func (l *mongoLeaderLock) Close() {
+	l.session.Close()
+}
+
+// The lock of the configured Leader database, nil when none is configured
+func leaderLockFromConfig() (LeaderLock, error) {
+	if Configuration == nil {
+		return nil, nil
+	}
+	info, exists := Configuration.Databases[LeaderLockDatabase]
+	if !exists || info.Type == "" {
+		return nil, nil
+	}
+	switch info.Type {
+	case db.MongoDB:
+		lock, err := newMongoLeaderLock(info)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to the leader lock database %s:%d : %s", info.Host, info.Port, err.Error())
+		}
+		return lock, nil
+	default:
+		return nil, fmt.Errorf("unsupported leader lock database type %q, expected %q", info.Type, db.MongoDB)
+	}
+}
@@ -0,0 +1,65 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// +build mongoRunning
+
+// This test will only be executed if the tag mongoRunning is added when running
+// the tests with a command like:
+// go test -tags mongoRunning
+
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+)
+
+func TestMongoLeaderLock(t *testing.T) {
+
+	t.Log("This test needs to have a running mongo on localhost")
+
+	info := config.DatabaseInfo{Type: "mongodb", Host: "0.0.0.0", Port: 27017, Name: "scheduler", Timeout: 1000}
+	first, err := newMongoLeaderLock(info)
+	if err != nil {
+		t.Fatalf("could not connect : %s", err.Error())
+	}
+	defer first.Close()
+	second, err := newMongoLeaderLock(info)
+	if err != nil {
+		t.Fatalf("could not connect : %s", err.Error())
+	}
+	defer second.Close()
+	first.session.DB(info.Name).C(LeaderLockCollection).DropCollection()
+
+	lease := time.Second
+	if acquired, err := first.TryAcquire("first", lease); err != nil || !acquired {
+		t.Fatalf("expected the first instance to lead, got %v %v", acquired, err)
+	}
+	if acquired, err := second.TryAcquire("second", lease); err != nil || acquired {
+		t.Fatalf("expected the second instance to follow, got %v %v", acquired, err)
+	}
+	if acquired, err := first.TryAcquire("first", lease); err != nil || !acquired {
+		t.Fatalf("expected the leader to renew its lease, got %v %v", acquired, err)
+	}
+
+	//the release hands the leadership over
+	if err := first.Release("first"); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if acquired, err := second.TryAcquire("second", lease); err != nil || !acquired {
+		t.Fatalf("expected the second instance to take over, got %v %v", acquired, err)
+	}
+
+	//so does the expiry of the lease
+	time.Sleep(lease + 100*time.Millisecond)
+	if acquired, err := first.TryAcquire("first", lease); err != nil || !acquired {
+		t.Fatalf("expected the first instance to take over the expired lease, got %v %v", acquired, err)
+	}
+	if err := second.Release("second"); err != nil {
+		t.Fatalf("the release of a lost lease should not fail : %s", err.Error())
+	}
+}
@@ -0,0 +1,53 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+)
+
+func TestLeaderLockIsOffWithoutADatabase(t *testing.T) {
+	defer func() { Configuration.Databases = nil }()
+
+	for _, databases := range []map[string]config.DatabaseInfo{
+		nil,
+		{LeaderLockDatabase: {Host: "localhost", Port: 27017}},
+	} {
+		Configuration.Databases = databases
+		lock, err := leaderLockFromConfig()
+		if err != nil {
+			t.Fatalf("unexpected error : %s", err.Error())
+		}
+		if lock != nil {
+			t.Errorf("expected no leader lock for the databases %v", databases)
+		}
+	}
+}
+
+func TestLeaderLockRejectsAnUnknownDatabaseType(t *testing.T) {
+	Configuration.Databases = map[string]config.DatabaseInfo{LeaderLockDatabase: {Type: "redis"}}
+	defer func() { Configuration.Databases = nil }()
+
+	lock, err := leaderLockFromConfig()
+	if err == nil || !strings.Contains(err.Error(), "redis") {
+		t.Fatalf("expected the unknown type to be rejected, got %v", err)
+	}
+	if lock != nil {
+		t.Error("expected no leader lock")
+	}
+}
+
+func TestInitFailsWhenTheLeaderLockIsMisconfigured(t *testing.T) {
+	Configuration.Databases = map[string]config.DatabaseInfo{LeaderLockDatabase: {Type: "redis"}}
+	defer func() { Configuration.Databases = nil }()
+
+	if Init(false) {
+		t.Error("expected Init to fail")
+	}
+}
@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	MQTT "github.com/eclipse/paho.mqtt.golang"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// MQTTProtocol is the addressable protocol of the schedule events published to an MQTT broker
+const MQTTProtocol = "MQTT"
+
+// MQTTPublisher is the interface used to publish the schedule events which target an MQTT addressable
+type MQTTPublisher interface {
+	Publish(addressable models.Addressable, payload []byte) error
+}
+
+// the publisher used to execute MQTT schedule events, a default one is used when it is not set
+var mqttPublisher MQTTPublisher
+
+// SetMQTTPublisher replaces the publisher used to execute MQTT schedule events
+func SetMQTTPublisher(publisher MQTTPublisher) {
+	mqttPublisher = publisher
+}
+
+func getMQTTPublisher() MQTTPublisher {
+	if mqttPublisher != nil {
+		return mqttPublisher
+	}
+	return pahoPublisher{}
+}
+
+func isMQTTAddressable(addressable models.Addressable) bool {
+	return strings.ToUpper(strings.TrimSpace(addressable.Protocol)) == MQTTProtocol
+}
+
+// Publish the parameters of a single schedule event to the topic of its addressable
+func publishScheduleEvent(scheduleEvent models.ScheduleEvent, correlationId string) (int, error) {
+	eventId := scheduleEvent.Id.Hex()
+	addressable := scheduleEvent.Addressable
+	LoggingClient.Debug(executionLogMsg(correlationId, "the event with id : "+eventId+" will publish to topic : "+addressable.Topic+" at broker : "+getMQTTBrokerUrl(addressable)), correlationId)
+
+	if isDryRun() {
+		LoggingClient.Info(executionLogMsg(correlationId, fmt.Sprintf("dry run, the event with id : %s would publish to topic : %s with payload : %s", eventId, addressable.Topic, scheduleEvent.Parameters)), correlationId)
+		return 0, nil
+	}
+
+	if err := waitForRequestToken(context.Background()); err != nil {
+		return 0, err
+	}
+
+	if err := getMQTTPublisher().Publish(addressable, []byte(scheduleEvent.Parameters)); err != nil {
+		logMsg := fmt.Sprintf("the event with id : %s failed : %s", eventId, err.Error())
+		LoggingClient.Error(executionLogMsg(correlationId, logMsg), correlationId)
+		return 0, errors.New(logMsg)
+	}
+
+	LoggingClient.Debug(executionLogMsg(correlationId, "the event with id : "+eventId+" has been published"), correlationId)
+	return 0, nil
+}
+
+func getMQTTBrokerUrl(addressable models.Addressable) string {
+	return "tcp://" + addressable.Address + ":" + strconv.Itoa(addressable.Port)
+}
+
+// pahoPublisher connects to the broker for every publish, schedule events fire too rarely to keep a connection open
+type pahoPublisher struct{}
+
+func (pahoPublisher) Publish(addressable models.Addressable, payload []byte) error {
+	timeout := time.Duration(Configuration.Service.Timeout) * time.Millisecond
+
+	opts := MQTT.NewClientOptions()
+	opts.AddBroker(getMQTTBrokerUrl(addressable))
+	opts.SetClientID(addressable.Publisher)
+	opts.SetUsername(addressable.User)
+	opts.SetPassword(addressable.Password)
+	opts.SetAutoReconnect(false)
+	if timeout > 0 {
+		opts.SetConnectTimeout(timeout)
+	}
+
+	client := MQTT.NewClient(opts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return fmt.Errorf("could not connect to the mqtt broker %s : %s", getMQTTBrokerUrl(addressable), token.Error().Error())
+	}
+	defer client.Disconnect(250)
+
+	token := client.Publish(addressable.Topic, 0, false, payload)
+	token.Wait()
+	return token.Error()
+}
@@ -0,0 +1,167 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+type publishedMessage struct {
+	topic   string
+	payload string
+}
+
+// testBroker understands just enough of MQTT to accept connections and QoS 0 publishes
+type testBroker struct {
+	listener  net.Listener
+	published chan publishedMessage
+}
+
+func newTestBroker(t *testing.T) *testBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected error starting the test broker : %s", err.Error())
+	}
+
+	broker := &testBroker{listener: listener, published: make(chan publishedMessage, 10)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+	return broker
+}
+
+func (b *testBroker) port() int {
+	return b.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (b *testBroker) close() {
+	b.listener.Close()
+}
+
+func (b *testBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		header, err := reader.ReadByte()
+		if err != nil {
+			return
+		}
+
+		//the remaining length is encoded in up to four bytes, seven bits each
+		length, multiplier := 0, 1
+		for {
+			digit, err := reader.ReadByte()
+			if err != nil {
+				return
+			}
+			length += int(digit&127) * multiplier
+			multiplier *= 128
+			if digit&128 == 0 {
+				break
+			}
+		}
+
+		body := make([]byte, length)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return
+		}
+
+		switch header >> 4 {
+		case 1: //CONNECT
+			conn.Write([]byte{0x20, 0x02, 0x00, 0x00})
+		case 3: //PUBLISH
+			topicLength := int(body[0])<<8 | int(body[1])
+			b.published <- publishedMessage{
+				topic:   string(body[2 : 2+topicLength]),
+				payload: string(body[2+topicLength:]),
+			}
+		case 12: //PINGREQ
+			conn.Write([]byte{0xd0, 0x00})
+		case 14: //DISCONNECT
+			return
+		}
+	}
+}
+
+func TestExecutePublishesMQTTEvent(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	broker := newTestBroker(t)
+	defer broker.close()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := models.ScheduleEvent{
+		Id:         bson.NewObjectId(),
+		Name:       TestScheduleEventName,
+		Schedule:   schedule.Name,
+		Parameters: `{"age":1}`,
+		Addressable: models.Addressable{
+			Name:      TestScheduleEventName,
+			Protocol:  "mqtt",
+			Address:   "127.0.0.1",
+			Port:      broker.port(),
+			Publisher: "scheduler-test",
+			Topic:     "edgex/scheduler",
+		},
+	}
+	if err := addScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	select {
+	case message := <-broker.published:
+		if message.topic != "edgex/scheduler" {
+			t.Errorf(TestUnexpectedMsgFormatStr, message.topic, "edgex/scheduler")
+		}
+		if message.payload != `{"age":1}` {
+			t.Errorf(TestUnexpectedMsgFormatStr, message.payload, `{"age":1}`)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the schedule event was not published to the broker")
+	}
+
+	if len(client.requests) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 0)
+	}
+}
+
+func TestExecuteDefaultsToHTTP(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+}
@@ -0,0 +1,79 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MaxPendingObserverCalls is the number of observer notifications which can run at the same time,
+// the notifications over the limit are dropped so slow observers never hold up the executions
+const MaxPendingObserverCalls = 100
+
+// ExecutionObserver is notified after each schedule event has been executed
+type ExecutionObserver interface {
+	OnExecuted(scheduleId, eventId string, statusCode int, duration time.Duration, err error)
+}
+
+var (
+	observerMutex      sync.RWMutex
+	executionObservers []ExecutionObserver
+	observerSlots      = make(chan struct{}, MaxPendingObserverCalls)
+)
+
+// RegisterExecutionObserver adds an observer which is notified after each schedule event execution
+func RegisterExecutionObserver(observer ExecutionObserver) {
+	if observer == nil {
+		return
+	}
+
+	observerMutex.Lock()
+	defer observerMutex.Unlock()
+	executionObservers = append(executionObservers, observer)
+}
+
+func clearExecutionObservers() {
+	observerMutex.Lock()
+	defer observerMutex.Unlock()
+	executionObservers = nil
+}
+
+// notify the observers outside of the execution, dropping the notification when too many are pending
+func notifyExecutionObservers(scheduleId, eventId string, statusCode int, duration time.Duration, err error) {
+	observerMutex.RLock()
+	observers := executionObservers
+	observerMutex.RUnlock()
+
+	if len(observers) == 0 {
+		return
+	}
+
+	select {
+	case observerSlots <- struct{}{}:
+	default:
+		LoggingClient.Warn(fmt.Sprintf("too many pending execution observer calls, dropping the notification for schedule event %s", eventId))
+		return
+	}
+
+	go func() {
+		defer func() { <-observerSlots }()
+		for _, observer := range observers {
+			callExecutionObserver(observer, scheduleId, eventId, statusCode, duration, err)
+		}
+	}()
+}
+
+// a panicking observer must not take down the scheduler or skip the other observers
+func callExecutionObserver(observer ExecutionObserver, scheduleId, eventId string, statusCode int, duration time.Duration, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			LoggingClient.Error(fmt.Sprintf("execution observer failed for schedule event %s : %v", eventId, r))
+		}
+	}()
+	observer.OnExecuted(scheduleId, eventId, statusCode, duration, err)
+}
@@ -0,0 +1,109 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+type executionNotification struct {
+	scheduleId string
+	eventId    string
+	statusCode int
+	duration   time.Duration
+	err        error
+}
+
+// channelObserver forwards the notifications it receives to a channel
+type channelObserver struct {
+	notifications chan executionNotification
+}
+
+func (o *channelObserver) OnExecuted(scheduleId, eventId string, statusCode int, duration time.Duration, err error) {
+	o.notifications <- executionNotification{scheduleId, eventId, statusCode, duration, err}
+}
+
+type panickingObserver struct{}
+
+func (o panickingObserver) OnExecuted(scheduleId, eventId string, statusCode int, duration time.Duration, err error) {
+	panic("observer failure")
+}
+
+// blockingObserver does not return until it is released
+type blockingObserver struct {
+	release chan struct{}
+}
+
+func (o *blockingObserver) OnExecuted(scheduleId, eventId string, statusCode int, duration time.Duration, err error) {
+	<-o.release
+}
+
+func TestExecuteNotifiesObservers(t *testing.T) {
+	resetScheduler()
+	defer clearExecutionObservers()
+	SetHTTPClient(&mockHTTPClient{statusCode: http.StatusAccepted})
+	defer SetHTTPClient(nil)
+
+	observer := &channelObserver{notifications: make(chan executionNotification, 1)}
+	RegisterExecutionObserver(panickingObserver{})
+	RegisterExecutionObserver(observer)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	executeSchedule(schedule.Id.Hex())
+
+	select {
+	case notification := <-observer.notifications:
+		if notification.scheduleId != schedule.Id.Hex() {
+			t.Errorf(TestUnexpectedMsgFormatStr, notification.scheduleId, schedule.Id.Hex())
+		}
+		if notification.eventId != scheduleEvent.Id.Hex() {
+			t.Errorf(TestUnexpectedMsgFormatStr, notification.eventId, scheduleEvent.Id.Hex())
+		}
+		if notification.statusCode != http.StatusAccepted {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, notification.statusCode, http.StatusAccepted)
+		}
+		if notification.duration < 0 {
+			t.Errorf("unexpected negative duration : %s", notification.duration)
+		}
+		if notification.err != nil {
+			t.Errorf("unexpected error : %s", notification.err.Error())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the observer was not notified of the execution")
+	}
+}
+
+func TestSlowObserverDoesNotBlockExecution(t *testing.T) {
+	resetScheduler()
+	defer clearExecutionObservers()
+	SetHTTPClient(&mockHTTPClient{})
+	defer SetHTTPClient(nil)
+
+	observer := &blockingObserver{release: make(chan struct{})}
+	RegisterExecutionObserver(observer)
+	defer close(observer.release)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < MaxPendingObserverCalls+10; i++ {
+			executeSchedule(schedule.Id.Hex())
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the executions were blocked by the observer")
+	}
+}
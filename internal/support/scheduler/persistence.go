@@ -0,0 +1,183 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// scheduleState is the snapshot of a schedule context and its progress kept in the state file
+type scheduleState struct {
+	Schedule          models.Schedule        `json:"schedule"`
+	ScheduleEvents    []models.ScheduleEvent `json:"scheduleEvents"`
+	NextTime          time.Time              `json:"nextTime"`
+	CurrentIterations int64                  `json:"currentIterations"`
+	Paused            bool                   `json:"paused"`
+}
+
+// set whenever the schedules or their progress change, guarded by the schedule mutex
+var stateChanged bool
+
+func markStateChanged() {
+	stateChanged = true
+}
+
+// Write the schedules and their progress to the configured state file if anything changed since the last write
+func persistState() error {
+	if Configuration == nil || Configuration.StateFile == "" {
+		return nil
+	}
+
+	mutex.Lock()
+	if !stateChanged {
+		mutex.Unlock()
+		return nil
+	}
+	states := snapshotState()
+	stateChanged = false
+	mutex.Unlock()
+
+	if err := writeState(Configuration.StateFile, states); err != nil {
+		LoggingClient.Error(fmt.Sprintf("failed to persist the scheduler state to %s : %s", Configuration.StateFile, err.Error()))
+		return err
+	}
+	return nil
+}
+
+// must be called with the schedule mutex held
+func snapshotState() []scheduleState {
+	states := make([]scheduleState, 0, len(scheduleIdToContextMap))
+	for _, scheduleContext := range scheduleIdToContextMap {
+		if scheduleContext.MarkedDeleted {
+			continue
+		}
+		state := scheduleState{
+			Schedule:          scheduleContext.Schedule,
+			NextTime:          scheduleContext.NextTime,
+			CurrentIterations: scheduleContext.CurrentIterations,
+			Paused:            scheduleContext.Paused,
+		}
+		for _, scheduleEvent := range scheduleContext.ScheduleEventsMap {
+			state.ScheduleEvents = append(state.ScheduleEvents, scheduleEvent)
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+func writeState(path string, states []scheduleState) error {
+	data, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first so a crash never leaves a partial state file behind
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path))
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func readState(path string) ([]scheduleState, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var states []scheduleState
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// Restore the schedules and their progress from the configured state file.
+// Schedules not known yet are added back, known ones get their next time and iteration count back.
+func restoreState() error {
+	if Configuration == nil || Configuration.StateFile == "" {
+		return nil
+	}
+
+	states, err := readState(Configuration.StateFile)
+	if os.IsNotExist(err) {
+		LoggingClient.Info(fmt.Sprintf("no scheduler state to restore from %s", Configuration.StateFile))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		scheduleId := state.Schedule.Id.Hex()
+		if isExpiredSchedule(state.Schedule.Name) {
+			continue
+		}
+
+		if _, err := querySchedule(scheduleId); err != nil {
+			if err := addSchedule(state.Schedule); err != nil {
+				LoggingClient.Warn(fmt.Sprintf("could not restore the schedule %s : %s", state.Schedule.Name, err.Error()))
+				continue
+			}
+		}
+		for _, scheduleEvent := range state.ScheduleEvents {
+			if _, err := queryScheduleEvent(scheduleEvent.Id.Hex()); err != nil {
+				if err := addScheduleEvent(scheduleEvent); err != nil {
+					LoggingClient.Warn(fmt.Sprintf("could not restore the schedule event %s : %s", scheduleEvent.Name, err.Error()))
+				}
+			}
+		}
+
+		restoreScheduleProgress(scheduleId, state)
+	}
+
+	LoggingClient.Info(fmt.Sprintf("restored %d schedules from %s", len(states), Configuration.StateFile))
+	return nil
+}
+
+func restoreScheduleProgress(scheduleId string, state scheduleState) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	scheduleContext, exists := scheduleIdToContextMap[scheduleId]
+	if !exists {
+		return
+	}
+
+	// a schedule which changed since the snapshot starts over
+	if scheduleContext.Schedule.String() != state.Schedule.String() {
+		return
+	}
+
+	scheduleContext.NextTime = state.NextTime
+	scheduleContext.CurrentIterations = state.CurrentIterations
+	scheduleContext.Paused = state.Paused
+
+	// stale entries which ended while the scheduler was down are dropped
+	if scheduleContext.IsEnded() ||
+		(scheduleContext.MaxIterations != 0 && scheduleContext.CurrentIterations >= scheduleContext.MaxIterations) {
+		LoggingClient.Info(fmt.Sprintf("the restored schedule %s has already completed, removing it", state.Schedule.Name))
+		if err := removeScheduleLocked(scheduleId); err != nil {
+			LoggingClient.Warn(fmt.Sprintf("could not remove the completed schedule %s : %s", state.Schedule.Name, err.Error()))
+		}
+	}
+}
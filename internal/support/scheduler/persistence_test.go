@@ -0,0 +1,145 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestPersistAndRestoreState(t *testing.T) {
+	resetScheduler()
+	dir, err := ioutil.TempDir("", "scheduler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	Configuration = &ConfigurationStruct{StateFile: filepath.Join(dir, "state.json")}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	nextTime := time.Now().Add(3 * time.Hour).Truncate(time.Second)
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	context.NextTime = nextTime
+	context.CurrentIterations = 7
+
+	if err := persistState(); err != nil {
+		t.Fatalf("unexpected error persisting the state : %s", err.Error())
+	}
+
+	resetScheduler()
+
+	if err := restoreState(); err != nil {
+		t.Fatalf("unexpected error restoring the state : %s", err.Error())
+	}
+
+	restored, exists := scheduleIdToContextMap[schedule.Id.Hex()]
+	if !exists {
+		t.Fatal("the schedule was not restored")
+	}
+	if !restored.NextTime.Equal(nextTime) {
+		t.Errorf(TestUnexpectedMsgFormatStr, restored.NextTime, nextTime)
+	}
+	if restored.CurrentIterations != 7 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, restored.CurrentIterations, 7)
+	}
+	if _, exists := restored.ScheduleEventsMap[scheduleEvent.Id.Hex()]; !exists {
+		t.Error("the schedule event was not restored")
+	}
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+}
+
+func TestRestoreStateDropsEndedSchedules(t *testing.T) {
+	resetScheduler()
+	dir, err := ioutil.TempDir("", "scheduler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	Configuration = &ConfigurationStruct{StateFile: filepath.Join(dir, "state.json")}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+
+	ended := scheduleState{
+		Schedule: models.Schedule{
+			Id:        bson.NewObjectId(),
+			Name:      TestScheduleName,
+			Start:     "20180101T000000",
+			End:       "20180201T000000",
+			Frequency: "P1D",
+		},
+		NextTime: time.Date(2018, 1, 10, 0, 0, 0, 0, time.UTC),
+	}
+	if err := writeState(Configuration.StateFile, []scheduleState{ended}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := restoreState(); err != nil {
+		t.Fatalf("unexpected error restoring the state : %s", err.Error())
+	}
+
+	if _, exists := scheduleIdToContextMap[ended.Schedule.Id.Hex()]; exists {
+		t.Error("the ended schedule should not have been restored")
+	}
+}
+
+func TestRestoreStateRemovesTheEventsOfCompletedSchedules(t *testing.T) {
+	resetScheduler()
+	dir, err := ioutil.TempDir("", "scheduler")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	Configuration = &ConfigurationStruct{StateFile: filepath.Join(dir, "state.json")}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+
+	schedule := newTestSchedule(TestScheduleName)
+	schedule.MaxIterations = 3
+	completed := scheduleState{
+		Schedule:          schedule,
+		NextTime:          time.Now().Add(time.Hour).Truncate(time.Second),
+		CurrentIterations: 3,
+		ScheduleEvents:    []models.ScheduleEvent{newTestScheduleEvent(schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")},
+	}
+	if err := writeState(Configuration.StateFile, []scheduleState{completed}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := restoreState(); err != nil {
+		t.Fatalf("unexpected error restoring the state : %s", err.Error())
+	}
+
+	if _, exists := scheduleIdToContextMap[schedule.Id.Hex()]; exists {
+		t.Error("the completed schedule should not have been restored")
+	}
+	if len(scheduleEventIdToScheduleIdMap) != 0 || len(scheduleEventNameToScheduleIdMap) != 0 || len(scheduleEventNameToScheduleEventIdMap) != 0 {
+		t.Errorf("the events of the completed schedule are still mapped : %v %v %v", scheduleEventIdToScheduleIdMap,
+			scheduleEventNameToScheduleIdMap, scheduleEventNameToScheduleEventIdMap)
+	}
+	if _, err := queryScheduleEventByName(TestScheduleEventName); err == nil {
+		t.Error("the event of the completed schedule should not be found")
+	}
+}
+
+func TestRestoreStateWithoutFile(t *testing.T) {
+	resetScheduler()
+	Configuration = &ConfigurationStruct{StateFile: filepath.Join(os.TempDir(), bson.NewObjectId().Hex())}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+
+	if err := restoreState(); err != nil {
+		t.Errorf("unexpected error restoring a missing state file : %s", err.Error())
+	}
+}
@@ -0,0 +1,111 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// The proxy of the event requests. Without a configured proxy the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+// environment variables are used, otherwise the HTTPS requests go through the HTTPSProxy, or the HTTPProxy
+// when it is not set, and the hosts of NoProxy are reached directly.
+func newProxyFunc(settings clientSettings) func(*http.Request) (*url.URL, error) {
+	if settings.httpProxy == "" && settings.httpsProxy == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	httpProxy, err := parseProxyUrl(settings.httpProxy)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+	}
+	httpsProxy, err := parseProxyUrl(settings.httpsProxy)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+	}
+	if httpsProxy == nil {
+		httpsProxy = httpProxy
+	}
+	noProxy := splitNoProxy(settings.noProxy)
+
+	return func(req *http.Request) (*url.URL, error) {
+		if bypassProxy(req.URL, noProxy) {
+			return nil, nil
+		}
+		if req.URL.Scheme == "https" {
+			return httpsProxy, nil
+		}
+		return httpProxy, nil
+	}
+}
+
+// the proxies are given as a URL or as host:port which is proxied over HTTP
+func parseProxyUrl(proxy string) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	if !strings.Contains(proxy, "://") {
+		proxy = "http://" + proxy
+	}
+	proxyUrl, err := url.Parse(proxy)
+	if err != nil || proxyUrl.Host == "" {
+		return nil, fmt.Errorf("invalid proxy address %q, the requests are sent directly", proxy)
+	}
+	return proxyUrl, nil
+}
+
+func splitNoProxy(noProxy string) []string {
+	var entries []string
+	for _, entry := range strings.Split(noProxy, ",") {
+		if entry = strings.ToLower(strings.TrimSpace(entry)); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// Match the host of the request against the NoProxy entries, which are a host or a domain and its sub domains
+// with an optional port, an IP address, a CIDR block or * for every host
+func bypassProxy(target *url.URL, noProxy []string) bool {
+	host, port := target.Hostname(), target.Port()
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		if entry == "*" {
+			return true
+		}
+		if _, block, err := net.ParseCIDR(entry); err == nil {
+			if ip != nil && block.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		entryHost := entry
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entryHost = h
+		}
+		if entryIp := net.ParseIP(entryHost); entryIp != nil {
+			if ip != nil && entryIp.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		entryHost = strings.TrimPrefix(entryHost, ".")
+		name := strings.ToLower(host)
+		if name == entryHost || strings.HasSuffix(name, "."+entryHost) {
+			return true
+		}
+	}
+	return false
+}
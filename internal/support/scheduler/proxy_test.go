@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// a proxy answering every request itself, recording the absolute URLs it was asked for
+type stubProxy struct {
+	mutex sync.Mutex
+	urls  []string
+}
+
+func (p *stubProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mutex.Lock()
+	p.urls = append(p.urls, r.URL.String())
+	p.mutex.Unlock()
+	io.WriteString(w, "proxied")
+}
+
+func (p *stubProxy) requests() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]string(nil), p.urls...)
+}
+
+func sendScheduleEvent(host string, port int) (int, error) {
+	scheduleEvent := models.ScheduleEvent{
+		Id:   bson.NewObjectId(),
+		Name: TestScheduleEventName,
+		Addressable: models.Addressable{
+			Name:       TestScheduleEventName,
+			Protocol:   "http",
+			HTTPMethod: http.MethodGet,
+			Address:    host,
+			Port:       port,
+			Path:       "/api/v1/ping",
+		},
+	}
+	return executeScheduleEvent(context.Background(), scheduleEvent, "")
+}
+
+func TestEventRequestTransitsConfiguredProxy(t *testing.T) {
+	proxy := &stubProxy{}
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+	Configuration = &ConfigurationStruct{HTTPProxy: proxyServer.URL}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+
+	//the target only exists behind the proxy
+	statusCode, err := sendScheduleEvent("device-service.edgex.invalid", 49990)
+	if err != nil {
+		t.Fatalf("unexpected error sending through the proxy : %s", err.Error())
+	}
+	if statusCode != http.StatusOK {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusOK)
+	}
+
+	expected := "http://device-service.edgex.invalid:49990/api/v1/ping"
+	if urls := proxy.requests(); len(urls) != 1 || urls[0] != expected {
+		t.Errorf(TestUnexpectedMsgFormatStr, urls, expected)
+	}
+}
+
+func TestEventRequestBypassesProxyForNoProxyHosts(t *testing.T) {
+	proxy := &stubProxy{}
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "direct")
+	}))
+	defer target.Close()
+	Configuration = &ConfigurationStruct{HTTPProxy: proxyServer.URL, NoProxy: "edgex.invalid, 127.0.0.0/8"}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+
+	targetUrl, _ := url.Parse(target.URL)
+	port, _ := strconv.Atoi(targetUrl.Port())
+	if _, err := sendScheduleEvent(targetUrl.Hostname(), port); err != nil {
+		t.Fatalf("unexpected error sending directly : %s", err.Error())
+	}
+	if urls := proxy.requests(); len(urls) != 0 {
+		t.Errorf("expected the request to bypass the proxy, got %v", urls)
+	}
+}
+
+func TestBypassProxy(t *testing.T) {
+	tests := []struct {
+		target   string
+		noProxy  string
+		expected bool
+	}{
+		{"http://core-data:48080", "", false},
+		{"http://core-data:48080", "*", true},
+		{"http://core-data:48080", "core-data", true},
+		{"http://core-data:48080", "core-data:48081", false},
+		{"http://core-data:48080", "core-data:48080", true},
+		{"http://core-data.edgex.local:48080", ".edgex.local", true},
+		{"http://core-data.edgex.local:48080", "edgex.local", true},
+		{"http://notedgex.local:48080", "edgex.local", false},
+		{"http://10.0.3.7:48080", "10.0.0.0/16", true},
+		{"http://10.1.3.7:48080", "10.0.0.0/16", false},
+		{"http://[::1]:48080", "::1", true},
+		{"http://CORE-DATA:48080", "core-data", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.noProxy, func(t *testing.T) {
+			target, _ := url.Parse(tt.target)
+			if bypass := bypassProxy(target, splitNoProxy(tt.noProxy)); bypass != tt.expected {
+				t.Errorf("expected the bypass of %s with %q to be %t, got %t", tt.target, tt.noProxy, tt.expected, bypass)
+			}
+		})
+	}
+}
+
+func TestHTTPSRequestsFallBackToHTTPProxy(t *testing.T) {
+	proxyFunc := newProxyFunc(clientSettings{httpProxy: "proxy.corp:3128"})
+	req, _ := http.NewRequest(http.MethodGet, "https://export.edgex.invalid/api/v1/ping", nil)
+	proxyUrl, err := proxyFunc(req)
+	if err != nil || proxyUrl == nil || proxyUrl.String() != "http://proxy.corp:3128" {
+		t.Errorf(TestUnexpectedMsgFormatStr, proxyUrl, "http://proxy.corp:3128")
+	}
+}
@@ -0,0 +1,83 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket holding a single token, so the requests are spread evenly at the configured rate
+type rateLimiter struct {
+	mutex    sync.Mutex
+	rate     int
+	interval time.Duration
+	next     time.Time // when the next token becomes available
+}
+
+func newRateLimiter(rate int) *rateLimiter {
+	return &rateLimiter{rate: rate, interval: time.Second / time.Duration(rate)}
+}
+
+// Block until a token is available or the context is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	reserved := l.next
+	l.next = l.next.Add(l.interval)
+	l.mutex.Unlock()
+
+	delay := reserved.Sub(now)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		//hand the unused token back
+		l.mutex.Lock()
+		l.next = l.next.Add(-l.interval)
+		l.mutex.Unlock()
+		return ctx.Err()
+	}
+}
+
+var (
+	requestLimiterMutex sync.Mutex
+	requestLimiter      *rateLimiter
+)
+
+// get the limiter of the configured MaxRequestsPerSecond, nil when the requests are not limited
+func getRequestLimiter() *rateLimiter {
+	requestLimiterMutex.Lock()
+	defer requestLimiterMutex.Unlock()
+
+	if Configuration == nil || Configuration.MaxRequestsPerSecond <= 0 {
+		requestLimiter = nil
+		return nil
+	}
+	if requestLimiter == nil || requestLimiter.rate != Configuration.MaxRequestsPerSecond {
+		requestLimiter = newRateLimiter(Configuration.MaxRequestsPerSecond)
+	}
+	return requestLimiter
+}
+
+// Wait for the rate limit before sending a request
+func waitForRequestToken(ctx context.Context) error {
+	limiter := getRequestLimiter()
+	if limiter == nil {
+		return nil
+	}
+	return limiter.wait(ctx)
+}
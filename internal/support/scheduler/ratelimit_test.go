@@ -0,0 +1,69 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterSpreadsBurst(t *testing.T) {
+	resetScheduler()
+	client := &countingHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.MaxRequestsPerSecond = 20
+	defer func() { Configuration.MaxRequestsPerSecond = 0 }()
+
+	const schedules = 10
+	for i := 0; i < schedules; i++ {
+		name := fmt.Sprintf("%s-%d", TestScheduleName, i)
+		schedule := addTestSchedule(t, name)
+		addTestScheduleEvent(t, schedule, name, http.MethodGet, "/api/v1/ping", "")
+		scheduleIdToContextMap[schedule.Id.Hex()].NextTime = time.Now().Add(-time.Second)
+	}
+
+	begin := time.Now()
+	triggerSchedule()
+	waitForExecutions()
+	elapsed := time.Since(begin)
+
+	if client.calls != schedules {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, client.calls, schedules)
+	}
+	//the first request goes out right away, the others one every 50ms
+	if elapsed < 400*time.Millisecond {
+		t.Errorf("%d requests took %s, faster than the limit of 20 per second", schedules, elapsed)
+	}
+}
+
+func TestRateLimiterRespectsCancellation(t *testing.T) {
+	limiter := newRateLimiter(1)
+	if err := limiter.wait(context.Background()); err != nil {
+		t.Fatalf("unexpected error taking the first token : %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	if err := limiter.wait(ctx); err == nil {
+		t.Error("expected an error waiting with a cancelled context")
+	}
+	if elapsed := time.Since(begin); elapsed > 500*time.Millisecond {
+		t.Errorf("waiting took %s after the context was cancelled", elapsed)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	if limiter := getRequestLimiter(); limiter != nil {
+		t.Error("expected no limiter when MaxRequestsPerSecond is not configured")
+	}
+}
@@ -0,0 +1,144 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+)
+
+const RedactedValue = "***"
+
+// the bodies of the requests and responses are only logged when LogBodies is set
+func logBodies() bool {
+	return Configuration != nil && Configuration.LogBodies
+}
+
+// Copy the headers, masking the values of the configured RedactHeaders
+func redactHeaders(header http.Header) http.Header {
+	redacted := make(http.Header, len(header))
+	for name, values := range header {
+		if containsFold(redactHeaderNames(), name) {
+			values = []string{RedactedValue}
+		}
+		redacted[name] = values
+	}
+	return redacted
+}
+
+// Mask the values of the configured RedactFields at any depth of a JSON body. The bodies which are not JSON are
+// returned unchanged.
+func redactBody(body string) string {
+	fields := redactFieldNames()
+	if len(fields) == 0 || strings.TrimSpace(body) == "" {
+		return body
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(redactValue(value, fields)); err != nil {
+		return body
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+func redactValue(value interface{}, fields []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if containsFold(fields, key) {
+				v[key] = RedactedValue
+			} else {
+				v[key] = redactValue(field, fields)
+			}
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i], fields)
+		}
+	}
+	return value
+}
+
+// Copy the configuration served by the config endpoint, masking the signing secret, the passwords of the proxy and
+// of the databases, and the values of the global headers which often carry credentials
+func redactedConfiguration(configuration *ConfigurationStruct) *ConfigurationStruct {
+	if configuration == nil {
+		return nil
+	}
+	redacted := *configuration
+	if redacted.SigningSecret != "" {
+		redacted.SigningSecret = RedactedValue
+	}
+	redacted.HTTPProxy = redactProxy(redacted.HTTPProxy)
+	if len(configuration.GlobalHeaders) > 0 {
+		redacted.GlobalHeaders = make(map[string]string, len(configuration.GlobalHeaders))
+		for name := range configuration.GlobalHeaders {
+			redacted.GlobalHeaders[name] = RedactedValue
+		}
+	}
+	if len(configuration.Databases) > 0 {
+		redacted.Databases = make(map[string]config.DatabaseInfo, len(configuration.Databases))
+		for name, database := range configuration.Databases {
+			if database.Password != "" {
+				database.Password = RedactedValue
+			}
+			redacted.Databases[name] = database
+		}
+	}
+	return &redacted
+}
+
+// mask the password of a proxy url, a proxy which does not parse is masked whole
+func redactProxy(proxy string) string {
+	if proxy == "" {
+		return proxy
+	}
+	proxyUrl, err := url.Parse(proxy)
+	if err != nil {
+		return RedactedValue
+	}
+	if _, hasPassword := proxyUrl.User.Password(); hasPassword {
+		proxyUrl.User = url.UserPassword(proxyUrl.User.Username(), RedactedValue)
+	}
+	return proxyUrl.String()
+}
+
+func redactHeaderNames() []string {
+	if Configuration == nil {
+		return nil
+	}
+	return Configuration.RedactHeaders
+}
+
+func redactFieldNames() []string {
+	if Configuration == nil {
+		return nil
+	}
+	return Configuration.RedactFields
+}
+
+func containsFold(names []string, name string) bool {
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+	return false
+}
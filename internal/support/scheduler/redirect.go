@@ -0,0 +1,51 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DefaultMaxRedirects is the number of requests sent along the redirects when the event does not set its
+// MaxRedirects, as many as the default client of net/http
+const DefaultMaxRedirects = 10
+
+// how the redirects of an event request are handled, carried by the context of the request since the
+// redirects of the default client are checked for every request it sends
+type redirectPolicy struct {
+	noRedirects  bool
+	maxRedirects int
+}
+
+type redirectPolicyKey struct{}
+
+func withRedirectPolicy(ctx context.Context, scheduleEvent models.ScheduleEvent) context.Context {
+	policy := redirectPolicy{noRedirects: scheduleEvent.NoRedirects, maxRedirects: scheduleEvent.MaxRedirects}
+	return context.WithValue(ctx, redirectPolicyKey{}, policy)
+}
+
+// The CheckRedirect of the default client. An event with NoRedirects gets the redirect response itself, the
+// others stop once their MaxRedirects requests were sent, via holds the requests already sent like for the
+// default policy of net/http. An injected client handles the redirects on its own.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	policy, _ := req.Context().Value(redirectPolicyKey{}).(redirectPolicy)
+	if policy.noRedirects {
+		return http.ErrUseLastResponse
+	}
+
+	maxRedirects := policy.maxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// a target redirecting /start to /middle and then to /target
+func newRedirectingServer(targetHits *int32) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/middle", http.StatusFound)
+	})
+	mux.HandleFunc("/middle", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/target", http.StatusFound)
+	})
+	mux.HandleFunc("/target", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(targetHits, 1)
+		w.WriteHeader(http.StatusOK)
+	})
+	return httptest.NewServer(mux)
+}
+
+func redirectTestEvent(server *httptest.Server, noRedirects bool, maxRedirects int) models.ScheduleEvent {
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	return models.ScheduleEvent{
+		Id:           bson.NewObjectId(),
+		Name:         TestScheduleEventName,
+		NoRedirects:  noRedirects,
+		MaxRedirects: maxRedirects,
+		Addressable: models.Addressable{
+			Name:       TestScheduleEventName,
+			Protocol:   "http",
+			HTTPMethod: http.MethodPost,
+			Address:    serverUrl.Hostname(),
+			Port:       port,
+			Path:       "/start",
+		},
+	}
+}
+
+func TestEventFollowsRedirectsByDefault(t *testing.T) {
+	var targetHits int32
+	server := newRedirectingServer(&targetHits)
+	defer server.Close()
+
+	statusCode, err := executeScheduleEvent(context.Background(), redirectTestEvent(server, false, 0), "")
+	if err != nil {
+		t.Fatalf("unexpected error following the redirects : %s", err.Error())
+	}
+	if statusCode != http.StatusOK {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusOK)
+	}
+	if hits := atomic.LoadInt32(&targetHits); hits != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, hits, 1)
+	}
+}
+
+func TestEventWithNoRedirects(t *testing.T) {
+	var targetHits int32
+	server := newRedirectingServer(&targetHits)
+	defer server.Close()
+
+	statusCode, err := executeScheduleEvent(context.Background(), redirectTestEvent(server, true, 0), "")
+	if err != nil {
+		t.Fatalf("unexpected error returning the redirect : %s", err.Error())
+	}
+	if statusCode != http.StatusFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusFound)
+	}
+	if hits := atomic.LoadInt32(&targetHits); hits != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, hits, 0)
+	}
+}
+
+func TestEventMaxRedirects(t *testing.T) {
+	var targetHits int32
+	server := newRedirectingServer(&targetHits)
+	defer server.Close()
+
+	//the two redirects take three requests, the limit is reached at the second redirect
+	if _, err := executeScheduleEvent(context.Background(), redirectTestEvent(server, false, 2), ""); err == nil {
+		t.Error("expected the redirect past the limit to fail the request")
+	}
+	if hits := atomic.LoadInt32(&targetHits); hits != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, hits, 0)
+	}
+	if _, err := executeScheduleEvent(context.Background(), redirectTestEvent(server, false, 3), ""); err != nil {
+		t.Errorf("unexpected error following the redirects up to the limit : %s", err.Error())
+	}
+	if hits := atomic.LoadInt32(&targetHits); hits != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, hits, 1)
+	}
+}
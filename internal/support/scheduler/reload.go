@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// ReloadSchedulers brings the scheduler in line with core-metadata and the configuration. Unlike AddSchedulers
+// only the schedules and schedule events which changed are touched, the others keep their next fire time and
+// iterations and the scheduler is never emptied in the meantime.
+func ReloadSchedulers() error {
+	LoggingClient.Info("Reloading schedules, schedule events, and addressables ...")
+
+	schedules, err := getMetadataSchedules()
+	if err != nil {
+		return err
+	}
+
+	scheduleEvents, err := getMetadataScheduleEvents()
+	if err != nil {
+		return err
+	}
+
+	if err := reloadSchedules(schedules, scheduleEvents); err != nil {
+		return err
+	}
+
+	// the configured schedules and events missing from core-metadata are added back
+	if err := validateConfig(Configuration.Schedules, Configuration.ScheduleEvents); err != nil {
+		LoggingClient.Error(err.Error())
+		return err
+	}
+	if err := loadConfigSchedules(); err != nil {
+		return err
+	}
+	if err := loadConfigScheduleEvents(); err != nil {
+		return err
+	}
+
+	LoggingClient.Info("completed reloading schedules, schedule events, and addressables")
+
+	return nil
+}
+
+// Apply the difference between the given schedules and events and the ones in the scheduler
+func reloadSchedules(schedules []models.Schedule, scheduleEvents []models.ScheduleEvent) error {
+	//the schedule files are not in core-metadata, what they define is kept
+	scheduleIds, scheduleEventIds := scheduleFileIds()
+	iterations := stateIterations()
+	for _, schedule := range schedules {
+		if isDeviceName(schedule.Name) {
+			continue
+		}
+		scheduleIds[schedule.Id.Hex()] = true
+
+		current, err := querySchedule(schedule.Id.Hex())
+		if err != nil {
+			//a schedule which completed before the last load is not brought back
+			if skipExpiredSchedule(schedule, iterations) {
+				continue
+			}
+			LoggingClient.Debug("reload adds the schedule with id : " + schedule.Id.Hex())
+			if err := addSchedule(schedule); err != nil {
+				return err
+			}
+		} else if current.String() != schedule.String() {
+			LoggingClient.Debug("reload updates the schedule with id : " + schedule.Id.Hex())
+			if err := updateSchedule(schedule); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, scheduleEvent := range scheduleEvents {
+		if isDeviceName(scheduleEvent.Service) {
+			continue
+		}
+		scheduleEventIds[scheduleEvent.Id.Hex()] = true
+
+		current, err := queryScheduleEvent(scheduleEvent.Id.Hex())
+		if err != nil {
+			if isExpiredSchedule(scheduleEvent.Schedule) {
+				continue
+			}
+			LoggingClient.Debug("reload adds the schedule event with id : " + scheduleEvent.Id.Hex())
+			if err := addScheduleEvent(scheduleEvent); err != nil {
+				return err
+			}
+		} else if current.String() != scheduleEvent.String() {
+			LoggingClient.Debug("reload updates the schedule event with id : " + scheduleEvent.Id.Hex())
+			if err := updateScheduleEvent(scheduleEvent); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, scheduleEventId := range currentScheduleEventIds() {
+		if !scheduleEventIds[scheduleEventId] {
+			LoggingClient.Debug("reload removes the schedule event with id : " + scheduleEventId)
+			if err := removeScheduleEvent(scheduleEventId); err != nil {
+				LoggingClient.Error("reload could not remove a stale entry : " + err.Error())
+			}
+		}
+	}
+
+	for _, scheduleId := range currentScheduleIds() {
+		if !scheduleIds[scheduleId] {
+			LoggingClient.Debug("reload removes the schedule with id : " + scheduleId)
+			if err := removeSchedule(scheduleId); err != nil {
+				LoggingClient.Error("reload could not remove a stale entry : " + err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+func currentScheduleIds() []string {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var ids []string
+	for id := range scheduleIdToContextMap {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func currentScheduleEventIds() []string {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var ids []string
+	for id := range scheduleEventIdToScheduleIdMap {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// the schedules and events of device services are not run by the scheduler
+func isDeviceName(name string) bool {
+	matched, err := regexp.MatchString("device.*", name)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("error matching the name %s : %s", name, err.Error()))
+		return false
+	}
+	return matched
+}
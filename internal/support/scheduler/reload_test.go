@@ -0,0 +1,204 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestReloadSchedulesAppliesDifference(t *testing.T) {
+	resetScheduler()
+
+	kept := addTestSchedule(t, TestScheduleName)
+	keptEvent := addTestScheduleEvent(t, kept, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	removed := addTestSchedule(t, "removed")
+	removedEvent := addTestScheduleEvent(t, removed, "removed event", http.MethodGet, "/api/v1/ping", "")
+
+	keptContext := scheduleIdToContextMap[kept.Id.Hex()]
+	keptContext.CurrentIterations = 5
+	nextTime := keptContext.NextTime
+
+	added := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      "added",
+		Start:     "20180101T000000",
+		Frequency: "PT1H",
+	}
+	addedEvent := models.ScheduleEvent{
+		Id:       bson.NewObjectId(),
+		Name:     "added event",
+		Schedule: added.Name,
+		Addressable: models.Addressable{
+			Protocol:   "http",
+			HTTPMethod: http.MethodGet,
+			Address:    "localhost",
+			Port:       48080,
+			Path:       "/api/v1/ping",
+		},
+	}
+
+	err := reloadSchedules([]models.Schedule{kept, added}, []models.ScheduleEvent{keptEvent, addedEvent})
+	if err != nil {
+		t.Fatalf("unexpected error reloading the schedules : %s", err.Error())
+	}
+
+	if scheduleIdToContextMap[kept.Id.Hex()] != keptContext {
+		t.Fatal("the unchanged schedule context should not be replaced")
+	}
+	if !keptContext.NextTime.Equal(nextTime) {
+		t.Errorf(TestUnexpectedMsgFormatStr, keptContext.NextTime, nextTime)
+	}
+	if keptContext.CurrentIterations != 5 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, keptContext.CurrentIterations, 5)
+	}
+
+	if _, err := querySchedule(removed.Id.Hex()); err == nil {
+		t.Error("the removed schedule should no longer be scheduled")
+	}
+	if _, err := queryScheduleEvent(removedEvent.Id.Hex()); err == nil {
+		t.Error("the removed schedule event should no longer be scheduled")
+	}
+
+	if _, err := querySchedule(added.Id.Hex()); err != nil {
+		t.Errorf("the added schedule should be scheduled : %s", err.Error())
+	}
+	if _, err := queryScheduleEvent(addedEvent.Id.Hex()); err != nil {
+		t.Errorf("the added schedule event should be scheduled : %s", err.Error())
+	}
+}
+
+func TestReloadSchedulesUpdatesChanged(t *testing.T) {
+	resetScheduler()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleIdToContextMap[schedule.Id.Hex()].CurrentIterations = 5
+
+	schedule.Frequency = "PT1H"
+	if err := reloadSchedules([]models.Schedule{schedule}, nil); err != nil {
+		t.Fatalf("unexpected error reloading the schedules : %s", err.Error())
+	}
+
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	if context.Schedule.Frequency != "PT1H" {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.Schedule.Frequency, "PT1H")
+	}
+	if context.CurrentIterations != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.CurrentIterations, 0)
+	}
+}
+
+func TestAddSchedulersSwapsConsistently(t *testing.T) {
+	resetScheduler()
+	old := addTestSchedule(t, "old")
+
+	replacement := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      "new",
+		Start:     "20180101T000000",
+		Frequency: "P1D",
+	}
+	msc = &fakeScheduleClient{schedules: []models.Schedule{replacement}, delay: 50 * time.Millisecond}
+	msec = &fakeScheduleEventClient{}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	done := make(chan struct{})
+	observations := make(chan []string, 10000)
+	go func() {
+		for {
+			select {
+			case <-done:
+				close(observations)
+				return
+			default:
+				observations <- currentScheduleIds()
+			}
+		}
+	}()
+
+	err := AddSchedulers()
+	close(done)
+	if err != nil {
+		t.Fatalf("unexpected error adding the schedulers : %s", err.Error())
+	}
+
+	for ids := range observations {
+		if len(ids) != 1 || (ids[0] != old.Id.Hex() && ids[0] != replacement.Id.Hex()) {
+			t.Fatalf("a query during the reload saw the schedules %v", ids)
+		}
+	}
+
+	if ids := currentScheduleIds(); len(ids) != 1 || ids[0] != replacement.Id.Hex() {
+		t.Errorf("expected only the new schedule after the reload, got %v", ids)
+	}
+}
+
+func TestAddSchedulersKeepsStateOnMetadataFailure(t *testing.T) {
+	resetScheduler()
+	old := addTestSchedule(t, "old")
+
+	msc = &fakeScheduleClient{err: errors.New("core-metadata is unreachable")}
+	msec = &fakeScheduleEventClient{}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	AddSchedulers()
+
+	if _, err := querySchedule(old.Id.Hex()); err != nil {
+		t.Errorf("the schedules should be kept when core-metadata fails : %s", err.Error())
+	}
+}
+
+// run with -race, the queries read the maps which AddSchedulers and clearMaps replace
+func TestQueriesDuringAddSchedulers(t *testing.T) {
+	resetScheduler()
+	schedule := addTestSchedule(t, TestScheduleName)
+
+	msc = &fakeScheduleClient{schedules: []models.Schedule{schedule}}
+	msec = &fakeScheduleEventClient{}
+	defer func() {
+		msc = nil
+		msec = nil
+	}()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					querySchedule(schedule.Id.Hex())
+					queryScheduleByName(schedule.Name)
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		if err := AddSchedulers(); err != nil {
+			t.Errorf("unexpected error adding the schedulers : %s", err.Error())
+		}
+		clearMaps()
+	}
+	close(done)
+	wg.Wait()
+}
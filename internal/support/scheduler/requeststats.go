@@ -0,0 +1,41 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// the sizes and the round trip of the latest request sent for an event, carried by the context of the request
+// so they are measured where the request is sent
+type requestStats struct {
+	requestBytes  int64
+	responseBytes int64
+	roundTrip     time.Duration
+}
+
+type requestStatsKey struct{}
+
+func withRequestStats(ctx context.Context) (context.Context, *requestStats) {
+	stats := &requestStats{}
+	return context.WithValue(ctx, requestStatsKey{}, stats), stats
+}
+
+// record the stats of a request which got a response, a later attempt overwrites those of the previous one
+func recordRequestStats(req *http.Request, responseBytes int, roundTrip time.Duration) {
+	stats, ok := req.Context().Value(requestStatsKey{}).(*requestStats)
+	if !ok {
+		return
+	}
+	stats.requestBytes = 0
+	if req.ContentLength > 0 {
+		stats.requestBytes = req.ContentLength
+	}
+	stats.responseBytes = int64(responseBytes)
+	stats.roundTrip = roundTrip
+}
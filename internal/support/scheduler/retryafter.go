@@ -0,0 +1,68 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// Parse the Retry-After header of a response, given either in delta-seconds or as an HTTP-date.
+// Returns 0 when the header is missing or invalid, or when its date has already passed.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	date, err := http.ParseTime(value)
+	if err != nil || !date.After(now) {
+		return 0
+	}
+	return date.Sub(now)
+}
+
+// the wait before the next attempt of a failed event, the Retry-After of a throttled or unavailable target
+// replaces the backoff but never goes past the max wait when there is one
+func retryWait(err error, backoff time.Duration, maxWait time.Duration) time.Duration {
+	serverErr, ok := err.(ErrServerResponse)
+	if !ok || serverErr.RetryAfter <= 0 {
+		return backoff
+	}
+	if maxWait > 0 && serverErr.RetryAfter > maxWait {
+		return maxWait
+	}
+	return serverErr.RetryAfter
+}
+
+// The Retry-After waits are bounded by the interval of the schedule, a cron schedule uses the gap between its
+// next two fires. A run once schedule is only bounded by its execution deadline.
+func retryAfterBound(schedule models.Schedule) time.Duration {
+	if schedule.RunOnce {
+		return 0
+	}
+	if frequency := parseFrequency(schedule.Frequency); frequency > 0 {
+		return frequency
+	}
+	if schedule.Cron == "" {
+		return 0
+	}
+	cronSchedule, err := parseCron(schedule.Cron)
+	if err != nil {
+		return 0
+	}
+	next := cronSchedule.Next(clock.Now())
+	return cronSchedule.Next(next).Sub(next)
+}
@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestRetryAfterOnTooManyRequests(t *testing.T) {
+	resetScheduler()
+	var mutex sync.Mutex
+	var attempts []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		attempts = append(attempts, time.Now())
+		if len(attempts) == 1 {
+			w.Header().Set(RetryAfterHeader, "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	//the backoff alone would retry right away
+	Configuration.MaxRetries = 1
+	Configuration.RetryBackoff = 1
+	defer func() {
+		Configuration.MaxRetries = 0
+		Configuration.RetryBackoff = 0
+	}()
+
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := models.ScheduleEvent{
+		Id:       bson.NewObjectId(),
+		Name:     TestScheduleEventName,
+		Schedule: schedule.Name,
+		Addressable: models.Addressable{
+			Name:       TestScheduleEventName,
+			Protocol:   "http",
+			HTTPMethod: http.MethodGet,
+			Address:    serverUrl.Hostname(),
+			Port:       port,
+			Path:       "/api/v1/ping",
+		},
+	}
+	if err := addScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("expected the retry to succeed, got %s", err.Error())
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(attempts) != 2 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(attempts), 2)
+	}
+	if wait := attempts[1].Sub(attempts[0]); wait < 2*time.Second || wait > 3*time.Second {
+		t.Errorf("expected the retry to wait about 2s as asked by the target, waited %s", wait)
+	}
+}
+
+func TestTooManyRequestsIsRetryable(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:48080/api/v1/ping", nil)
+	_, statusCode, err := sendRequestAndGetResponse(&mockHTTPClient{statusCode: http.StatusTooManyRequests}, req)
+	if statusCode != http.StatusTooManyRequests {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusTooManyRequests)
+	}
+	if _, ok := err.(ErrServerResponse); !ok {
+		t.Fatalf("expected a server response error, got %v", err)
+	}
+	if !isRetryable(err) {
+		t.Error("a throttled request should be retried without RetryServerErrors")
+	}
+}
+
+func TestUnavailableWithRetryAfterIsRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(RetryAfterHeader, "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, statusCode, err := sendRequestAndGetResponse(&http.Client{}, req)
+	if statusCode != http.StatusServiceUnavailable {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusServiceUnavailable)
+	}
+	if _, ok := err.(ErrServerResponse); !ok {
+		t.Fatalf("expected a server response error, got %v", err)
+	}
+	if !isRetryable(err) {
+		t.Error("a service asking for a retry should be retried without RetryServerErrors")
+	}
+
+	//without the header the server error is left to RetryServerErrors
+	if isRetryable(ErrServerResponse{StatusCode: http.StatusServiceUnavailable}) {
+		t.Error("a server error should not be retried without RetryServerErrors")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := map[string]time.Duration{
+		"":                              0,
+		"2":                             2 * time.Second,
+		" 120 ":                         2 * time.Minute,
+		"-1":                            0,
+		"soon":                          0,
+		"Mon, 01 Jan 2018 00:00:30 GMT": 30 * time.Second,
+		"Sun, 31 Dec 2017 23:59:00 GMT": 0,
+	}
+	for value, expected := range tests {
+		if wait := parseRetryAfter(value, now); wait != expected {
+			t.Errorf("expected the Retry-After %q to wait %s, got %s", value, expected, wait)
+		}
+	}
+}
+
+func TestRetryWaitIsBoundedByTheInterval(t *testing.T) {
+	throttled := ErrServerResponse{StatusCode: http.StatusServiceUnavailable, RetryAfter: time.Hour}
+	if wait := retryWait(throttled, time.Second, time.Minute); wait != time.Minute {
+		t.Errorf(TestUnexpectedMsgFormatStr, wait, time.Minute)
+	}
+	if wait := retryWait(ErrServerResponse{StatusCode: http.StatusServiceUnavailable}, time.Second, time.Minute); wait != time.Second {
+		t.Errorf(TestUnexpectedMsgFormatStr, wait, time.Second)
+	}
+
+	if bound := retryAfterBound(models.Schedule{Frequency: "PT30S"}); bound != 30*time.Second {
+		t.Errorf(TestUnexpectedMsgFormatStr, bound, 30*time.Second)
+	}
+	if bound := retryAfterBound(models.Schedule{Cron: "0 */5 * * * *"}); bound != 5*time.Minute {
+		t.Errorf(TestUnexpectedMsgFormatStr, bound, 5*time.Minute)
+	}
+	if bound := retryAfterBound(models.Schedule{RunOnce: true}); bound != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStr, bound, time.Duration(0))
+	}
+}
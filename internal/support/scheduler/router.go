@@ -15,6 +15,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"runtime"
+	"strings"
 
 	"github.com/edgexfoundry/edgex-go/internal"
 	"github.com/edgexfoundry/edgex-go/pkg/clients"
@@ -34,15 +35,68 @@ func LoadRestRoutes() http.Handler {
 	// Metrics
 	mux.Get(clients.ApiMetricsRoute, http.HandlerFunc(replyMetrics))
 
+	// Metrics in the Prometheus exposition format
+	mux.Get("/metrics", http.HandlerFunc(replyPrometheusMetrics))
+
 	// default api route
 	mv1 := mux.Prefix("/api/v1")
 
+	// health of the scheduler ticker
+	mv1.Get("/health", http.HandlerFunc(replyHealth))
+
 	// info
 	mv1.Get("/info/:name", http.HandlerFunc(replyInfo))
 
 	// flush reload schedules
 	mv1.Get("/flush", http.HandlerFunc(replyFlushScheduler))
 
+	// reload only the changed schedules
+	mv1.Post("/config/reload", http.HandlerFunc(replyReloadScheduler))
+
+	// drain and resume the scheduler for maintenance
+	mv1.Post("/maintenance/drain", http.HandlerFunc(replyDrainScheduler))
+	mv1.Post("/maintenance/resume", http.HandlerFunc(replyResumeScheduler))
+
+	// add schedule events
+	mv1.Post("/scheduleevent", http.HandlerFunc(replyAddScheduleEvent))
+	mv1.Get("/scheduleevent", http.HandlerFunc(replyAllScheduleEvents))
+
+	// remove schedules, along with their events, and schedule events
+	mv1.Delete("/schedule/:id", http.HandlerFunc(replyRemoveSchedule))
+	mv1.Delete("/scheduleevent/:id", http.HandlerFunc(replyRemoveScheduleEvent))
+
+	// change the cadence of schedules
+	mv1.Put("/schedule/:id", http.HandlerFunc(replyUpdateScheduleCadence))
+
+	// pause and resume schedules
+	mv1.Put("/schedule/:id/pause", http.HandlerFunc(replyPauseSchedule))
+	mv1.Put("/schedule/:id/resume", http.HandlerFunc(replyResumeSchedule))
+
+	// copy a schedule under a new name
+	mv1.Post("/schedule/:id/clone", http.HandlerFunc(replyCloneSchedule))
+
+	// a schedule with its next fire, by name
+	mv1.Get("/schedule/name/:name", http.HandlerFunc(replyScheduleByName))
+
+	// the schedule firing next
+	mv1.Get("/schedule/next", http.HandlerFunc(replyNextFire))
+
+	// recent executions of schedules
+	mv1.Get("/schedule/:id/history", http.HandlerFunc(replyScheduleHistory))
+
+	// trigger the schedules with a tag
+	mv1.Post("/schedule/trigger", http.HandlerFunc(replyTriggerSchedules))
+
+	// events of a schedule
+	mv1.Get("/schedule/:name/events", http.HandlerFunc(replyScheduleEvents))
+
+	// last execution result of schedule events
+	mv1.Get("/scheduleevent/:id/lastrun", http.HandlerFunc(replyScheduleEventLastRun))
+
+	// schedule events which failed all of their retries
+	mv1.Get("/deadletter", http.HandlerFunc(replyDeadLetters))
+	mv1.Delete("/deadletter", http.HandlerFunc(replyClearDeadLetters))
+
 	// callbacks
 	mv1.Post("/callbacks", http.HandlerFunc(addCallbackAlert))
 	mv1.Put("/callbacks", http.HandlerFunc(updateCallbackAlert))
@@ -58,18 +112,44 @@ func replyPing(w http.ResponseWriter, _ *http.Request) {
 	io.WriteString(w, str)
 }
 
+func replyHealth(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Set(ContentTypeKey, ContentTypeJsonValue)
+
+	status := checkHealth()
+	if status.Healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(status); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+	}
+}
+
 func replyConfig(w http.ResponseWriter, r *http.Request) {
 
 	if r.Body != nil {
 		defer r.Body.Close()
 	}
 
-	encode(Configuration, w)
+	encode(redactedConfiguration(Configuration), w)
+}
+
+// the telemetry of the service along with the depth of the schedule queue
+type schedulerTelemetry struct {
+	internal.Telemetry
+	QueueLength   int
+	MaxQueueDepth int
+	AutoPaused    int // schedules paused by their failures
 }
 
 func replyMetrics(w http.ResponseWriter, r *http.Request) {
 
-	var t internal.Telemetry
+	var t schedulerTelemetry
 
 	if r.Body != nil {
 		defer r.Body.Close()
@@ -92,6 +172,12 @@ func replyMetrics(w http.ResponseWriter, r *http.Request) {
 	// Live objects = Mallocs - Frees
 	t.LiveObjects = t.Mallocs - t.Frees
 
+	mutex.Lock()
+	t.QueueLength = scheduleQueue.Length()
+	t.AutoPaused = autoPausedCountLocked()
+	mutex.Unlock()
+	t.MaxQueueDepth = maxQueueDepth()
+
 	encode(t, w)
 
 	return
@@ -133,6 +219,441 @@ func replyFlushScheduler(w http.ResponseWriter, r *http.Request){
 	io.WriteString(w, str)
 }
 
+func replyReloadScheduler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	if err := ReloadSchedulers(); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error reloading the changed schedules, scheduleEvents, or addressables: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, `{"reload" : "success"}`)
+}
+
+func replyAddScheduleEvent(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("read request body error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var scheduleEvent models.ScheduleEvent
+	if err := json.Unmarshal(data, &scheduleEvent); err != nil {
+		LoggingClient.Error(fmt.Sprintf("failed to parse schedule event : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateScheduleEvent(scheduleEvent); err != nil {
+		LoggingClient.Error(fmt.Sprintf("invalid schedule event : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if _, err := queryScheduleByName(scheduleEvent.Schedule); err != nil {
+		http.Error(w, fmt.Sprintf("schedule %q not found for event %q", scheduleEvent.Schedule, scheduleEvent.Name), http.StatusNotFound)
+		return
+	}
+	if _, err := queryScheduleEventByName(scheduleEvent.Name); err == nil {
+		http.Error(w, fmt.Sprintf("the schedule event %q already exists", scheduleEvent.Name), http.StatusConflict)
+		return
+	}
+
+	// core-metadata is the system of record, the event is only scheduled once it has been stored there
+	if err := addAddressableToCoreMetadata(&scheduleEvent.Addressable); err != nil {
+		if _, ok := err.(ErrMalformedId); ok {
+			http.Error(w, "invalid addressable id from core-metadata", http.StatusInternalServerError)
+			return
+		}
+		LoggingClient.Error(fmt.Sprintf("error adding the addressable into core-metadata : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	id, err := addScheduleEventToCoreMetadata(&scheduleEvent)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	scheduleEvent.Id, err = metadataObjectId("schedule event", scheduleEvent.Name, id)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		http.Error(w, "invalid schedule event id from core-metadata", http.StatusInternalServerError)
+		return
+	}
+
+	if err := addScheduleEvent(scheduleEvent); err != nil {
+		LoggingClient.Error(fmt.Sprintf("add schedule event error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, id)
+}
+
+func replyRemoveSchedule(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	id := bone.GetValue(r, "id")
+	if _, err := querySchedule(id); err != nil {
+		LoggingClient.Error(fmt.Sprintf("remove schedule error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := deleteSchedule(id); err != nil {
+		LoggingClient.Error(fmt.Sprintf("remove schedule error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, `{"remove" : "success"}`)
+}
+
+func replyRemoveScheduleEvent(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	id := bone.GetValue(r, "id")
+	if _, err := queryScheduleEvent(id); err != nil {
+		LoggingClient.Error(fmt.Sprintf("remove schedule event error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err := deleteScheduleEvent(id); err != nil {
+		LoggingClient.Error(fmt.Sprintf("remove schedule event error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, `{"remove" : "success"}`)
+}
+
+func replyUpdateScheduleCadence(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id := bone.GetValue(r, "id")
+	if _, err := querySchedule(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var cadence ScheduleCadence
+	if err := json.NewDecoder(r.Body).Decode(&cadence); err != nil {
+		LoggingClient.Error(fmt.Sprintf("failed to parse the schedule cadence : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := updateScheduleCadence(id, cadence)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("update schedule error : %s", err.Error()))
+		if _, ok := err.(ErrInvalidCadence); ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+		return
+	}
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(schedule); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyCloneSchedule(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id := bone.GetValue(r, "id")
+	if _, err := querySchedule(id); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var request ScheduleClone
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		LoggingClient.Error(fmt.Sprintf("failed to parse the schedule clone : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	request.Name = strings.TrimSpace(request.Name)
+	if request.Name == "" {
+		http.Error(w, "the schedule clone has no name", http.StatusBadRequest)
+		return
+	}
+
+	schedule, err := cloneSchedule(id, request)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("clone schedule error : %s", err.Error()))
+		if _, ok := err.(ErrNameConflict); ok {
+			http.Error(w, err.Error(), http.StatusConflict)
+		} else if _, ok := err.(ErrInvalidCadence); ok {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		} else {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		}
+		return
+	}
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(schedule); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyPauseSchedule(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	id := bone.GetValue(r, "id")
+	if err := pauseSchedule(id); err != nil {
+		LoggingClient.Error(fmt.Sprintf("pause schedule error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, `{"pause" : "success"}`)
+}
+
+func replyResumeSchedule(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	id := bone.GetValue(r, "id")
+	if err := resumeSchedule(id); err != nil {
+		LoggingClient.Error(fmt.Sprintf("resume schedule error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, `{"resume" : "success"}`)
+}
+
+func replyScheduleHistory(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	id := bone.GetValue(r, "id")
+	history, err := queryHistory(id)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("read history request error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(history); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Serve the Prometheus registry, the metrics are not available here when another backend is set
+func replyPrometheusMetrics(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	registry, ok := getMetrics().(*prometheusMetrics)
+	if !ok {
+		http.Error(w, "the metrics are sent to another backend", http.StatusNotFound)
+		return
+	}
+	w.Header().Set(ContentTypeKey, PrometheusContentType)
+	registry.write(w)
+}
+
+func replyTriggerSchedules(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	tag := r.URL.Query().Get("tag")
+	if tag == "" {
+		http.Error(w, "the tag of the schedules to trigger is missing", http.StatusBadRequest)
+		return
+	}
+	//a callback triggering schedules could call itself back endlessly
+	if r.Header.Get(CallbackHeader) != "" {
+		LoggingClient.Warn(fmt.Sprintf("refused to trigger the schedules with tag %s from the callback of the schedule event %s", tag, r.Header.Get(CallbackHeader)))
+		http.Error(w, "schedules can not be triggered from a schedule event callback", http.StatusLoopDetected)
+		return
+	}
+
+	results, err := triggerSchedulesByTag(tag, r.Header.Get(TraceParentHeader))
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("trigger schedules error : %s", err.Error()))
+		if _, ok := err.(ErrDraining); ok {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(results); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyDrainScheduler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(drainScheduler()); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyResumeScheduler(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(resumeScheduler()); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyScheduleEvents(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	name := bone.GetValue(r, "name")
+	scheduleEvents, err := queryScheduleEventsOfSchedule(name)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("read schedule events request error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(scheduleEvents); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyAllScheduleEvents(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	details := queryAllScheduleEvents(r.URL.Query().Get("service"))
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(details); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyScheduleByName(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	name := bone.GetValue(r, "name")
+	detail, err := queryScheduleDetailByName(name)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("read schedule request error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(detail); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyNextFire(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	next, exists := queryNextFire()
+	if !exists {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(next); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyScheduleEventLastRun(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	id := bone.GetValue(r, "id")
+	lastRun, err := queryLastRun(id)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("read last run request error : %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(lastRun); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyDeadLetters(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(queryDeadLetters()); err != nil {
+		LoggingClient.Error(fmt.Sprintf("Error encoding the data: %s", err.Error()))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func replyClearDeadLetters(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	w.Header().Add(ContentTypeKey, ContentTypeJsonValue)
+
+	clearDeadLetters()
+
+	w.WriteHeader(http.StatusOK)
+	io.WriteString(w, `{"clear" : "success"}`)
+}
+
 func addCallbackAlert(rw http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
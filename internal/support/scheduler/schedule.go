@@ -7,22 +7,36 @@
 package scheduler
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"net/url"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/edgexfoundry/edgex-go"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/satori/go.uuid"
 	queueV1 "gopkg.in/eapache/queue.v1"
 	"gopkg.in/mgo.v2/bson"
 )
 
 const (
-	ScheduleInterval = 500
+	// DefaultScheduleInterval is the tick rate in milliseconds used when ScheduleInterval is not configured
+	DefaultScheduleInterval = 500
+
+	// DefaultMaxResponseBytes is the cap on the response body read from a schedule event when MaxResponseBytes is not configured
+	DefaultMaxResponseBytes = 1 << 20
 )
 
 //the schedule specific shared variables
@@ -34,18 +48,107 @@ var (
 	scheduleEventIdToScheduleIdMap        = make(map[string]string)           // map : schedule event id -> schedule id
 	scheduleEventNameToScheduleIdMap      = make(map[string]string)           // map : schedule event name -> schedule id
 	scheduleEventNameToScheduleEventIdMap = make(map[string]string)           // map : schedule event name -> schedule event id
+	tagToScheduleIdsMap                   = make(map[string]map[string]bool)  // map : tag -> schedule ids
+)
+
+// the executions started by the ticks
+var (
+	executionSlotsMutex sync.Mutex
+	executionSlots      chan struct{}
+	executionsInFlight  sync.WaitGroup
 )
 
+// HTTPClient is the interface of the client used to send the requests of the schedule events
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// the client used to execute schedule events, a default one is created when it is not set
+var httpClient HTTPClient
+
+// SetHTTPClient replaces the client used to execute schedule events
+func SetHTTPClient(client HTTPClient) {
+	httpClient = client
+}
+
+func getHTTPClient() HTTPClient {
+	if httpClient != nil {
+		return httpClient
+	}
+	return getDefaultHTTPClient()
+}
+
 func StartTicker() {
-	go func() {
-		for range ticker.C {
-			triggerSchedule()
+	ticker = newTicker()
+	setTickerRunning(true)
+
+	//the ticks within the startup delay are dropped so the downstream services have the time to come up
+	delay := startupDelay()
+	if delay > 0 {
+		LoggingClient.Info(fmt.Sprintf("the ticker processes the schedules after a startup delay of %s", delay))
+	}
+	readyAt := time.Now().Add(delay)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	tickerStop, tickerDone = stop, done
+	go func(ticks <-chan time.Time) {
+		defer close(done)
+		for {
+			select {
+			case tick := <-ticks:
+				if tick.Before(readyAt) {
+					continue
+				}
+				triggerSchedule()
+				persistState()
+			case <-stop:
+				return
+			}
 		}
-	}()
+	}(ticker.C)
 }
 
+// StopTicker returns once a tick in progress is over
 func StopTicker() {
 	ticker.Stop()
+	if tickerStop != nil {
+		close(tickerStop)
+		<-tickerDone
+		tickerStop, tickerDone = nil, nil
+	}
+	setTickerRunning(false)
+	releaseLeadership()
+}
+
+// get the configured tick rate, falling back to the default when unset or invalid
+func scheduleInterval() time.Duration {
+	interval := DefaultScheduleInterval
+	if Configuration != nil && Configuration.ScheduleInterval != 0 {
+		if Configuration.ScheduleInterval > 0 {
+			interval = Configuration.ScheduleInterval
+		} else {
+			LoggingClient.Warn(fmt.Sprintf("invalid schedule interval %d, using the default of %d milliseconds", Configuration.ScheduleInterval, DefaultScheduleInterval))
+		}
+	}
+	return time.Duration(interval) * time.Millisecond
+}
+
+// get the slots bounding the executions running at the same time across the ticks, nil when they are not bounded.
+// The slots are made again when the bound changes, the running executions free the slots they took.
+func getExecutionSlots() chan struct{} {
+	executionSlotsMutex.Lock()
+	defer executionSlotsMutex.Unlock()
+
+	if Configuration == nil || Configuration.MaxConcurrentExecutions <= 0 {
+		executionSlots = nil
+	} else if cap(executionSlots) != Configuration.MaxConcurrentExecutions {
+		executionSlots = make(chan struct{}, Configuration.MaxConcurrentExecutions)
+	}
+	return executionSlots
+}
+
+func newTicker() *time.Ticker {
+	return time.NewTicker(scheduleInterval())
 }
 
 // utility function
@@ -60,11 +163,21 @@ func clearQueue() {
 
 // utility function
 func clearMaps() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
 	scheduleIdToContextMap = make(map[string]*ScheduleContext)   // map : schedule id -> schedule context
 	scheduleNameToContextMap = make(map[string]*ScheduleContext) // map : schedule name -> schedule context
 	scheduleEventIdToScheduleIdMap = make(map[string]string)     // map : schedule event id -> schedule id
 	scheduleEventNameToScheduleIdMap = make(map[string]string)   // map : schedule event name -> schedule id
 	scheduleEventNameToScheduleEventIdMap = make(map[string]string)
+	tagToScheduleIdsMap = make(map[string]map[string]bool)
+	expiredScheduleNames = make(map[string]bool)
+	clearLastRuns()
+	clearHistories()
+	clearScheduleFiles()
+	clearDedupeCalls()
+	clearBodySources()
 }
 
 //endregion
@@ -72,22 +185,34 @@ func clearMaps() {
 func addScheduleOperation(scheduleId models.Schedule, context *ScheduleContext) {
 	scheduleIdToContextMap[scheduleId.Id.Hex()] = context
 	scheduleNameToContextMap[scheduleId.Name] = context
+	indexScheduleTagsLocked(scheduleId)
 	scheduleQueue.Add(context)
+	markStateChanged()
 }
 
 func deleteScheduleOperation(schedule models.Schedule, scheduleContext *ScheduleContext) {
 	scheduleContext.MarkedDeleted = true
-	scheduleIdToContextMap[schedule.Id.Hex()] = scheduleContext
-	scheduleNameToContextMap[schedule.Name] = scheduleContext
 	delete(scheduleIdToContextMap, schedule.Id.Hex())
+	//the name may have been taken over by a newer schedule
+	if scheduleNameToContextMap[schedule.Name] == scheduleContext {
+		delete(scheduleNameToContextMap, schedule.Name)
+	}
+	unindexScheduleTagsLocked(schedule)
+	markStateChanged()
 }
 
-func addScheduleEventOperation(schedule models.Schedule, scheduleEvent models.ScheduleEvent) {
-	scheduleContext, _ := scheduleIdToContextMap[schedule.Id.Hex()]
+func addScheduleEventOperation(schedule models.Schedule, scheduleEvent models.ScheduleEvent) error {
+	scheduleContext, exists := scheduleIdToContextMap[schedule.Id.Hex()]
+	if !exists {
+		return fmt.Errorf("can not find schedule context with schedule id : %s for the schedule event with id : %s", schedule.Id.Hex(), scheduleEvent.Id.Hex())
+	}
+
 	scheduleContext.ScheduleEventsMap[scheduleEvent.Id.Hex()] = scheduleEvent
 	scheduleEventIdToScheduleIdMap[scheduleEvent.Id.Hex()] = schedule.Id.Hex()
 	scheduleEventNameToScheduleIdMap[scheduleEvent.Name] = schedule.Id.Hex()
 	scheduleEventNameToScheduleEventIdMap[scheduleEvent.Name] = scheduleEvent.Id.Hex()
+	markStateChanged()
+	return nil
 }
 
 func querySchedule(scheduleId string) (models.Schedule, error) {
@@ -122,10 +247,133 @@ func queryScheduleByName(scheduleName string) (models.Schedule, error) {
 	return scheduleContext.Schedule, nil
 }
 
+// ScheduleDetail is a schedule along with the state of its firing
+type ScheduleDetail struct {
+	Schedule   models.Schedule `json:"schedule"`
+	NextTime   int64           `json:"nextTime"` // the next fire in milliseconds since the epoch
+	Iterations int64           `json:"iterations"`
+	EventCount int             `json:"eventCount"`
+	Paused     bool            `json:"paused"`
+}
+
+// Query the schedule with the given name along with its next fire and its iterations
+func queryScheduleDetailByName(scheduleName string) (ScheduleDetail, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	scheduleContext, exists := scheduleNameToContextMap[scheduleName]
+	if !exists || scheduleContext.MarkedDeleted {
+		return ScheduleDetail{}, fmt.Errorf("scheduler could not find schedule with name : %s", scheduleName)
+	}
+
+	return newScheduleDetail(scheduleContext), nil
+}
+
+// the detail of a schedule, the caller holds the schedule mutex
+func newScheduleDetail(scheduleContext *ScheduleContext) ScheduleDetail {
+	return ScheduleDetail{
+		Schedule:   scheduleContext.Schedule,
+		NextTime:   scheduleContext.NextTime.UnixNano() / int64(time.Millisecond),
+		Iterations: scheduleContext.CurrentIterations,
+		EventCount: len(scheduleContext.ScheduleEventsMap),
+		Paused:     scheduleContext.Paused,
+	}
+}
+
+// NextFire is the schedule with the soonest upcoming fire
+type NextFire struct {
+	ScheduleDetail
+	UntilMs int64 `json:"untilMs"` // milliseconds until the fire, 0 when it is already due
+}
+
+// Query the schedule firing next among the schedules which are neither paused, deleted nor complete. Reports
+// false when there is none.
+func queryNextFire() (NextFire, bool) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	now := clock.Now()
+	var next *ScheduleContext
+	for _, context := range scheduleIdToContextMap {
+		if context.MarkedDeleted || context.Paused || context.isComplete(now) {
+			continue
+		}
+		if next == nil || context.NextTime.Before(next.NextTime) ||
+			(context.NextTime.Equal(next.NextTime) && context.Schedule.Id < next.Schedule.Id) {
+			next = context
+		}
+	}
+	if next == nil {
+		return NextFire{}, false
+	}
+
+	until := next.NextTime.Sub(now)
+	if until < 0 {
+		until = 0
+	}
+	return NextFire{ScheduleDetail: newScheduleDetail(next), UntilMs: int64(until / time.Millisecond)}, true
+}
+
+// Query the events of the schedule with the given name in their execution order
+func queryScheduleEventsOfSchedule(scheduleName string) ([]models.ScheduleEvent, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	scheduleContext, exists := scheduleNameToContextMap[scheduleName]
+	if !exists {
+		return nil, fmt.Errorf("scheduler could not find schedule with name : %s", scheduleName)
+	}
+	if scheduleContext.MarkedDeleted {
+		return nil, fmt.Errorf("the schedule with name : %s has been deleted", scheduleName)
+	}
+
+	return orderedScheduleEvents(scheduleContext.ScheduleEventsMap), nil
+}
+
+// ScheduleEventDetail is a schedule event along with its owning schedule and the next fire of the schedule
+type ScheduleEventDetail struct {
+	ScheduleEvent models.ScheduleEvent `json:"scheduleEvent"`
+	Schedule      string               `json:"schedule"`
+	NextTime      int64                `json:"nextTime"` // the next fire in milliseconds since the epoch
+}
+
+// Query the events of all the schedules ordered by the name of their schedule and then in their execution
+// order. When service is not empty only the events targeting that service are returned.
+func queryAllScheduleEvents(service string) []ScheduleEventDetail {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	scheduleNames := make([]string, 0, len(scheduleNameToContextMap))
+	for scheduleName, scheduleContext := range scheduleNameToContextMap {
+		if !scheduleContext.MarkedDeleted {
+			scheduleNames = append(scheduleNames, scheduleName)
+		}
+	}
+	sort.Strings(scheduleNames)
+
+	details := make([]ScheduleEventDetail, 0)
+	for _, scheduleName := range scheduleNames {
+		scheduleContext := scheduleNameToContextMap[scheduleName]
+		nextTime := scheduleContext.NextTime.UnixNano() / int64(time.Millisecond)
+		for _, scheduleEvent := range orderedScheduleEvents(scheduleContext.ScheduleEventsMap) {
+			if service != "" && scheduleEvent.Service != service {
+				continue
+			}
+			details = append(details, ScheduleEventDetail{ScheduleEvent: scheduleEvent, Schedule: scheduleName, NextTime: nextTime})
+		}
+	}
+	return details
+}
+
 func addSchedule(schedule models.Schedule) error {
 	mutex.Lock()
 	defer mutex.Unlock()
 
+	return addScheduleLocked(schedule)
+}
+
+// add the schedule, the caller holds the schedule mutex
+func addScheduleLocked(schedule models.Schedule) error {
 	scheduleId := schedule.Id.Hex()
 	LoggingClient.Debug(fmt.Sprintf("adding the schedule with id : %s at time %s", scheduleId, schedule.Start))
 
@@ -134,13 +382,28 @@ func addSchedule(schedule models.Schedule) error {
 		return nil
 	}
 
+	//a second schedule with the same name would take over the name of the first one
+	if _, exists := scheduleNameToContextMap[schedule.Name]; exists {
+		err := ErrNameConflict{Kind: "schedule", Name: schedule.Name}
+		LoggingClient.Error(fmt.Sprintf("the schedule with id : %s will not be scheduled : %s", scheduleId, err.Error()))
+		return err
+	}
+
+	if err := checkQueueCapacityLocked(); err != nil {
+		LoggingClient.Error(fmt.Sprintf("the schedule with id : %s will not be scheduled : %s", scheduleId, err.Error()))
+		return err
+	}
+
 	context := ScheduleContext{
 		ScheduleEventsMap: make(map[string]models.ScheduleEvent),
 		MarkedDeleted:     false,
 	}
 
 	LoggingClient.Debug(fmt.Sprintf("resetting the schedule with id : %s", scheduleId))
-	context.Reset(schedule)
+	if err := context.Reset(schedule); err != nil {
+		LoggingClient.Error(fmt.Sprintf("the schedule with id : %s will not be scheduled : %s", scheduleId, err.Error()))
+		return err
+	}
 
 	addScheduleOperation(schedule, &context)
 
@@ -149,7 +412,30 @@ func addSchedule(schedule models.Schedule) error {
 	return nil
 }
 
+// Reject growing the queue past the configured depth, the caller holds the schedule mutex
+func checkQueueCapacityLocked() error {
+	maxDepth := maxQueueDepth()
+	if maxDepth > 0 && scheduleQueue.Length() >= maxDepth {
+		return ErrQueueFull{MaxQueueDepth: maxDepth}
+	}
+	return nil
+}
+
+// the configured maximum number of queued schedules, 0 when it is unbounded
+func maxQueueDepth() int {
+	if Configuration == nil || Configuration.MaxQueueDepth <= 0 {
+		return 0
+	}
+	return Configuration.MaxQueueDepth
+}
+
 func updateSchedule(schedule models.Schedule) error {
+	return resetSchedule(schedule, false)
+}
+
+// Reschedule the schedule with its new definition, an invalid one leaves the schedule as it was. The iterations
+// start over unless they are kept, as they are when only the timing of the schedule changes.
+func resetSchedule(schedule models.Schedule, keepIterations bool) error {
 	mutex.Lock()
 	defer mutex.Unlock()
 
@@ -162,8 +448,27 @@ func updateSchedule(schedule models.Schedule) error {
 		return errors.New("the schedule context with id " + scheduleId + " does not exist ")
 	}
 
+	//the current schedule stays as it is when the new one is invalid
+	candidate := ScheduleContext{}
+	if err := candidate.Reset(schedule); err != nil {
+		LoggingClient.Error("the schedule with id " + scheduleId + " keeps its current definition : " + err.Error())
+		return err
+	}
+
 	LoggingClient.Debug("resetting the schedule with id " + scheduleId)
-	context.Reset(schedule)
+	previous := context.Schedule
+	iterations := context.CurrentIterations
+	if err := context.Reset(schedule); err != nil {
+		return err
+	}
+	if keepIterations {
+		context.CurrentIterations = iterations
+	}
+	context.rescheduled = context.Executing
+	unindexScheduleTagsLocked(previous)
+	indexScheduleTagsLocked(schedule)
+
+	markStateChanged()
 
 	LoggingClient.Debug("updated the schedule with id : " + scheduleId)
 
@@ -174,6 +479,11 @@ func removeSchedule(scheduleId string) error {
 	mutex.Lock()
 	defer mutex.Unlock()
 
+	return removeScheduleLocked(scheduleId)
+}
+
+// remove the schedule along with its events, the caller holds the schedule mutex
+func removeScheduleLocked(scheduleId string) error {
 	LoggingClient.Debug("removing the schedule with id : " + scheduleId)
 
 	scheduleContext, exists := scheduleIdToContextMap[scheduleId]
@@ -182,18 +492,63 @@ func removeSchedule(scheduleId string) error {
 		return errors.New(logMsg)
 	}
 
-	LoggingClient.Debug("removing all the mappings of schedule event id to schedule id : " + scheduleId)
-	for eventId := range scheduleContext.ScheduleEventsMap {
-		delete(scheduleEventIdToScheduleIdMap, eventId)
+	LoggingClient.Debug("removing all the mappings of the schedule events of schedule : " + scheduleId)
+	for eventId, scheduleEvent := range scheduleContext.ScheduleEventsMap {
+		removeScheduleEventMappingsLocked(eventId, scheduleEvent.Name)
+		removeLastRun(eventId)
 	}
 
 	deleteScheduleOperation(scheduleContext.Schedule, scheduleContext)
+	removeHistory(scheduleId)
 
 	LoggingClient.Debug("removed the schedule with id : " + scheduleId)
 
 	return nil
 }
 
+func pauseSchedule(scheduleId string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	scheduleContext, exists := scheduleIdToContextMap[scheduleId]
+	if !exists {
+		logMsg := fmt.Sprintf("scheduler could not find schedule context with schedule id : %s", scheduleId)
+		return errors.New(logMsg)
+	}
+
+	scheduleContext.Paused = true
+	markStateChanged()
+
+	LoggingClient.Info("paused the schedule with id : " + scheduleId)
+
+	return nil
+}
+
+func resumeSchedule(scheduleId string) error {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	scheduleContext, exists := scheduleIdToContextMap[scheduleId]
+	if !exists {
+		logMsg := fmt.Sprintf("scheduler could not find schedule context with schedule id : %s", scheduleId)
+		return errors.New(logMsg)
+	}
+
+	if scheduleContext.Paused {
+		//the fires missed while paused are not replayed
+		scheduleContext.SkipMissedFires()
+		scheduleContext.Paused = false
+		markStateChanged()
+	}
+	scheduleContext.AutoPaused = false
+	scheduleContext.ConsecutiveFailures = 0
+	scheduleContext.failingSince = time.Time{}
+
+	LoggingClient.Info("resumed the schedule with id : " + scheduleId + ", next time : " + scheduleContext.NextTime.String())
+
+	return nil
+}
+
 func queryScheduleEvent(scheduleEventId string) (models.ScheduleEvent, error) {
 	mutex.Lock()
 	defer mutex.Unlock()
@@ -258,12 +613,28 @@ func addScheduleEvent(scheduleEvent models.ScheduleEvent) error {
 	mutex.Lock()
 	defer mutex.Unlock()
 
+	return addScheduleEventLocked(scheduleEvent)
+}
+
+// add the schedule event, the caller holds the schedule mutex
+func addScheduleEventLocked(scheduleEvent models.ScheduleEvent) error {
 	scheduleEventId := scheduleEvent.Id.Hex()
 	scheduleName := scheduleEvent.Schedule
 
 	LoggingClient.Debug(fmt.Sprintf("adding the schedule event with id  : %s to schedule : %s ", scheduleEventId, scheduleName))
 
-	scheduleContext := scheduleNameToContextMap[scheduleName]
+	if existingId, exists := scheduleEventNameToScheduleEventIdMap[scheduleEvent.Name]; exists && existingId != scheduleEventId {
+		err := ErrNameConflict{Kind: "schedule event", Name: scheduleEvent.Name}
+		LoggingClient.Error(fmt.Sprintf("the schedule event with id : %s will not be scheduled : %s", scheduleEventId, err.Error()))
+		return err
+	}
+
+	scheduleContext, exists := scheduleNameToContextMap[scheduleName]
+	if !exists {
+		logMsg := fmt.Sprintf("schedule %q not found for event %q", scheduleName, scheduleEvent.Name)
+		LoggingClient.Error(logMsg)
+		return errors.New(logMsg)
+	}
 
 	schedule := scheduleContext.Schedule
 
@@ -271,17 +642,29 @@ func addScheduleEvent(scheduleEvent models.ScheduleEvent) error {
 	LoggingClient.Debug(fmt.Sprintf("check the schedule with id : %s exists.", scheduleId))
 
 	if _, exists := scheduleIdToContextMap[scheduleId]; !exists {
+		//the event queues its schedule again, which needs room in the queue
+		if err := checkQueueCapacityLocked(); err != nil {
+			LoggingClient.Error(fmt.Sprintf("the schedule event with id : %s will not be scheduled : %s", scheduleEventId, err.Error()))
+			return err
+		}
+
 		context := ScheduleContext{
 			ScheduleEventsMap: make(map[string]models.ScheduleEvent),
 			MarkedDeleted:     false,
 		}
 
-		context.Reset(schedule)
+		if err := context.Reset(schedule); err != nil {
+			LoggingClient.Error(fmt.Sprintf("the schedule with id : %s will not be scheduled : %s", scheduleId, err.Error()))
+			return err
+		}
 
 		addScheduleOperation(schedule, &context)
 	}
 
-	addScheduleEventOperation(schedule, scheduleEvent)
+	if err := addScheduleEventOperation(schedule, scheduleEvent); err != nil {
+		LoggingClient.Error(err.Error())
+		return err
+	}
 
 	LoggingClient.Debug(fmt.Sprintf("added the schedule event with id : %s to schedule : %s", scheduleEventId, scheduleName))
 
@@ -309,41 +692,25 @@ func updateScheduleEvent(scheduleEvent models.ScheduleEvent) error {
 		return errors.New(logMsg)
 	}
 
-	//if the schedule event switched schedule
 	schedule := scheduleContext.Schedule
-
 	newScheduleId := schedule.Id.Hex()
 
-	if newScheduleId != oldScheduleId {
-		LoggingClient.Debug("the schedule event switched schedule from " + oldScheduleId + " to " + newScheduleId)
-
-		//remove Schedule Event
-		LoggingClient.Debug("remove the schedule event with id : " + scheduleEventId + " from schedule with id : " + oldScheduleId)
-		delete(scheduleContext.ScheduleEventsMap, scheduleEventId)
-
-		//if there are no more events for the schedule, remove the schedule context
-		// TODO: Not sure we want to just remove the schedule from the schedule context
-		if len(scheduleContext.ScheduleEventsMap) == 0 {
-			LoggingClient.Debug("there are no more events for the schedule : " + oldScheduleId + ", remove it.")
-			deleteScheduleOperation(schedule, scheduleContext)
+	//drop the mappings of the previous version of the event, its name may have changed too
+	if oldContext, exists := scheduleIdToContextMap[oldScheduleId]; exists {
+		if previous, exists := oldContext.ScheduleEventsMap[scheduleEventId]; exists {
+			removeScheduleEventMappingsLocked(scheduleEventId, previous.Name)
 		}
 
-		//add Schedule Event
-		LoggingClient.Debug("add the schedule event with id : " + scheduleEventId + " to schedule with id : " + newScheduleId)
-
-		if _, exists := scheduleIdToContextMap[newScheduleId]; !exists {
-			context := ScheduleContext{
-				ScheduleEventsMap: make(map[string]models.ScheduleEvent),
-				MarkedDeleted:     false,
-			}
-			context.Reset(schedule)
-
-			addScheduleOperation(schedule, &context)
+		//the schedule the event left stays scheduled, like a schedule whose events have been removed
+		if newScheduleId != oldScheduleId {
+			LoggingClient.Debug("the schedule event with id : " + scheduleEventId + " switched schedule from " + oldScheduleId + " to " + newScheduleId)
+			delete(oldContext.ScheduleEventsMap, scheduleEventId)
 		}
+	}
 
-		addScheduleEventOperation(schedule, scheduleEvent)
-	} else { // if not, just update the schedule event in place
-		scheduleContext.ScheduleEventsMap[scheduleEventId] = scheduleEvent
+	if err := addScheduleEventOperation(schedule, scheduleEvent); err != nil {
+		LoggingClient.Error(err.Error())
+		return err
 	}
 
 	LoggingClient.Debug("updated the schedule event with id " + scheduleEvent.Id.Hex() + " to schedule id : " + schedule.Id.Hex())
@@ -369,15 +736,30 @@ func removeScheduleEvent(scheduleEventId string) error {
 		return errors.New(logMsg)
 	}
 
+	scheduleEvent := scheduleContext.ScheduleEventsMap[scheduleEventId]
 	delete(scheduleContext.ScheduleEventsMap, scheduleEventId)
+	removeScheduleEventMappingsLocked(scheduleEventId, scheduleEvent.Name)
+	removeLastRun(scheduleEventId)
+	markStateChanged()
 
 	LoggingClient.Debug("removed the schedule event with id " + scheduleEventId)
 
 	return nil
 }
 
+// Remove the id and name mappings of a schedule event, the caller holds the schedule mutex
+func removeScheduleEventMappingsLocked(scheduleEventId string, scheduleEventName string) {
+	delete(scheduleEventIdToScheduleIdMap, scheduleEventId)
+	//the name may have been taken over by a newer event
+	if scheduleEventNameToScheduleEventIdMap[scheduleEventName] == scheduleEventId {
+		delete(scheduleEventNameToScheduleIdMap, scheduleEventName)
+		delete(scheduleEventNameToScheduleEventIdMap, scheduleEventName)
+	}
+}
+
 func triggerSchedule() {
-	nowEpoch := time.Now().Unix()
+	now := clock.Now()
+	nowEpoch := now.Unix()
 
 	defer func() {
 		if err := recover(); err != nil {
@@ -385,124 +767,594 @@ func triggerSchedule() {
 		}
 	}()
 
-	if scheduleQueue.Length() == 0 {
-		return
-	}
+	//only the leader executes the schedules when the replicas elect one
+	leading := isLeading()
 
-	var wg sync.WaitGroup
+	//pick the due schedules under the lock, the executions requeue their schedules concurrently
+	var dueContexts []*ScheduleContext
 
-	for i := 0; i < scheduleQueue.Length(); i++ {
+	mutex.Lock()
+	lastTick = clock.Now()
+	//a drained scheduler still ticks but starts no new fires until it resumes
+	if draining {
+		mutex.Unlock()
+		return
+	}
+	for i, length := 0, scheduleQueue.Length(); i < length; i++ {
 		if scheduleQueue.Peek().(*ScheduleContext) != nil {
 			scheduleContext := scheduleQueue.Remove().(*ScheduleContext)
 			scheduleId := scheduleContext.Schedule.Id.Hex()
 			if scheduleContext.MarkedDeleted {
 				LoggingClient.Debug("the schedule with id : " + scheduleId + " be marked as deleted, removing it.")
 				continue //really delete from the queue
+			} else if scheduleContext.IsEnded() {
+				LoggingClient.Debug("the schedule with id : " + scheduleId + " has passed its end time " + scheduleContext.EndTime.String() + ", completing it.")
+				continue //completed, do not requeue
+			} else if scheduleContext.Paused {
+				autoResumeLocked(scheduleContext, now)
+				scheduleQueue.Add(scheduleContext)
+			} else if scheduleContext.Executing && scheduleContext.triggered {
+				//a due fire waits for the on demand run to be over
+				scheduleQueue.Add(scheduleContext)
+			} else if scheduleContext.Executing {
+				LoggingClient.Warn("the schedule with id : " + scheduleId + " is still executing, skipping it.")
+				continue //the running execution requeues it
 			} else {
-				if scheduleContext.NextTime.Unix() <= nowEpoch {
-					LoggingClient.Debug("executing schedule, detail : {" + scheduleContext.GetInfo() + "} , at : " + scheduleContext.NextTime.String())
-
-					wg.Add(1)
-
-					//execute it in a individual go routine
-					go execute(scheduleContext, &wg)
+				if scheduleContext.NextTime.Unix() <= nowEpoch && !leading {
+					//a follower moves its due schedules on without executing them so it takes over in step
+					scheduleContext.advanceAfterFire(now)
+					scheduleContext.skipMissedFires(now)
+					scheduleContext.UpdateIterations()
+					if !scheduleContext.IsComplete() {
+						scheduleQueue.Add(scheduleContext)
+					}
+				} else if scheduleContext.NextTime.Unix() <= nowEpoch && !scheduleContext.prepareFire(now) {
+					LoggingClient.Info("skipped the missed fires of the schedule with id : " + scheduleId + ", next time : " + scheduleContext.NextTime.String())
+					markStateChanged()
+					scheduleQueue.Add(scheduleContext)
+				} else if scheduleContext.NextTime.Unix() <= nowEpoch && !scheduleContext.inWindow() {
+					//a fire outside of the window of the schedule is dropped without a request
+					LoggingClient.Info("skipped the fire outside of the window of the schedule with id : " + scheduleId + ", at : " + scheduleContext.NextTime.String())
+					scheduleContext.advanceAfterFire(now)
+					markStateChanged()
+					if !scheduleContext.IsComplete() {
+						scheduleQueue.Add(scheduleContext)
+					}
+				} else if scheduleContext.NextTime.Unix() <= nowEpoch {
+					LoggingClient.Debug(formatLogMsg(logFields{Msg: "executing schedule, detail : {" + scheduleContext.GetInfo() + "} , at : " + scheduleContext.NextTime.String(), ScheduleId: scheduleId}))
+					scheduleContext.Executing = true
+					dueContexts = append(dueContexts, scheduleContext)
 				} else {
 					scheduleQueue.Add(scheduleContext)
 				}
 			}
 		}
 	}
+	dueContexts = capDueContextsLocked(dueContexts)
+	queueLength, schedules := scheduleQueue.Length(), len(scheduleIdToContextMap)
+	//counted under the lock so a drain waits for them
+	executionsInFlight.Add(len(dueContexts))
+	mutex.Unlock()
+	getMetrics().SetQueueState(queueLength, schedules)
+
+	//the executions run in the background so a slow one does not hold up the next ticks
+	if len(dueContexts) > 0 {
+		go dispatchExecutions(dueContexts, getExecutionSlots())
+	}
+}
+
+// Start the executions in their queue order, waiting for a free slot before each one when they are bounded
+func dispatchExecutions(dueContexts []*ScheduleContext, executionSlots chan struct{}) {
+	for _, scheduleContext := range dueContexts {
+		if executionSlots != nil {
+			executionSlots <- struct{}{}
+		}
+
+		//execute it in a individual go routine
+		go func(scheduleContext *ScheduleContext) {
+			defer executionsInFlight.Done()
+			if executionSlots != nil {
+				defer func() { <-executionSlots }()
+			}
+			defer func() {
+				if err := recover(); err != nil {
+					LoggingClient.Error(fmt.Sprintf("the execution of the schedule %s panicked : %v", scheduleContext.Schedule.Name, err))
+					releasePanickedExecution(scheduleContext)
+				}
+			}()
+			if err := execute(scheduleContext); err != nil {
+				LoggingClient.Warn("the execution had failures, " + err.Error())
+			}
+		}(scheduleContext)
+	}
+}
+
+// Let the schedule of an execution which panicked outside of its events fire again, unless the execution got as far
+// as releasing it
+func releasePanickedExecution(context *ScheduleContext) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if !context.Executing {
+		return
+	}
+	context.Executing = false
+	context.advanceAfterFire(clock.Now())
+	markStateChanged()
+	if scheduleIdToContextMap[context.Schedule.Id.Hex()] == context && !context.IsComplete() {
+		scheduleQueue.Add(context)
+	}
+}
 
-	wg.Wait()
+// waitForExecutions blocks until the executions dispatched by the ticks so far are over
+func waitForExecutions() {
+	executionsInFlight.Wait()
 }
 
-func execute(context *ScheduleContext, wg *sync.WaitGroup) error {
-	scheduleEventsMap := context.ScheduleEventsMap
+func execute(context *ScheduleContext) error {
+	//take the events under the lock, they can be added or removed while the execution runs
+	schedule, scheduleEvents, templateData := executionInputs(context)
 
-	defer wg.Done()
+	//every log line and outbound request of this execution carries the same correlation id
+	correlationId := uuid.NewV4().String()
 
+	executionErr := runRecoveredScheduleEvents(schedule, scheduleEvents, templateData, correlationId)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	context.Executing = false
+	if context.rescheduled {
+		//the schedule was updated while executing, its next time already follows the new cadence
+		context.rescheduled = false
+	} else {
+		//a meaningful response brings the interval back to the frequency before the next fire is computed
+		if context.Schedule.ResetInterval && len(executionErr.Responded) > 0 && !executionErr.Failed() {
+			context.resetInterval()
+		}
+		context.advanceAfterFire(clock.Now())
+	}
+	context.UpdateIterations()
+	recordExecutionOutcomeLocked(context, executionErr.Failed(), clock.Now())
+	markStateChanged()
+	if removeFailingScheduleLocked(context, clock.Now()) {
+		return executionErr
+	}
+
+	if reason := context.CompletionReason(); reason != "" {
+		LoggingClient.Info(executionLogMsg(correlationId, "completed schedule by its "+reason+" condition, detail : "+context.GetInfo()), correlationId)
+		if url := completionUrl(); url != "" {
+			go sendCompletionNotification(url, newCompletionNotification(context, templateData.Iteration), correlationId)
+		}
+	} else if scheduleIdToContextMap[context.Schedule.Id.Hex()] != context {
+		LoggingClient.Debug(executionLogMsg(correlationId, "the schedule has been replaced while executing, not requeuing it, detail : "+context.GetInfo()), correlationId)
+	} else {
+		LoggingClient.Debug(executionLogMsg(correlationId, "requeue schedule, detail : "+context.GetInfo()), correlationId)
+		scheduleQueue.Add(context)
+	}
+
+	if executionErr.Failed() {
+		return executionErr
+	}
+	return nil
+}
+
+// the lock is released on panics too so a failed execution never blocks the scheduler
+func executionInputs(context *ScheduleContext) (models.Schedule, []models.ScheduleEvent, TemplateData) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return context.Schedule, orderedScheduleEvents(context.ScheduleEventsMap), newTemplateData(context, clock.Now(), context.NextTime.Add(-context.jitterOffset))
+}
+
+// Run the events of an execution, a panic fails the execution so the schedule is still released, advanced and
+// requeued like after any other failed execution
+func runRecoveredScheduleEvents(schedule models.Schedule, scheduleEvents []models.ScheduleEvent, templateData TemplateData, correlationId string) (executionErr ErrExecution) {
 	defer func() {
 		if err := recover(); err != nil {
-			LoggingClient.Error("schedule execution error : " + err.(string))
+			LoggingClient.Error(executionLogMsg(correlationId, fmt.Sprintf("schedule execution error : %v", err)), correlationId)
+			executionErr = ErrExecution{
+				ScheduleId: schedule.Id.Hex(),
+				Failures:   []EventFailure{{Name: schedule.Name, Err: fmt.Errorf("the execution panicked : %v", err)}},
+			}
 		}
 	}()
 
-	LoggingClient.Debug(fmt.Sprintf("%d schedule event need to be executed.", len(scheduleEventsMap)))
+	return runScheduleEvents(schedule, scheduleEvents, templateData, correlationId, "")
+}
 
-	//execute schedule event one by one
-	for eventId := range scheduleEventsMap {
-		LoggingClient.Debug("the event with id : " + eventId + " belongs to schedule : " + context.Schedule.Id.Hex() + " will be executing!")
-		scheduleEvent, _ := scheduleEventsMap[eventId]
+// Execute the events one by one and record the execution in the history of the schedule, in a span of its own which
+// is a child of the trace parent when one is passed in. The events left once the MaxExecutionMs of the schedule has
+// passed are skipped.
+func runScheduleEvents(schedule models.Schedule, scheduleEvents []models.ScheduleEvent, templateData TemplateData, correlationId string, traceParent string) ErrExecution {
+	LoggingClient.Debug(executionLogMsg(correlationId, fmt.Sprintf("%d schedule event need to be executed.", len(scheduleEvents))), correlationId)
+	span := getTracer().StartSpan("execute schedule "+schedule.Name, traceParent)
+
+	//a failing event does not stop the rest of the events from executing
+	executionErr := ErrExecution{ScheduleId: schedule.Id.Hex()}
+	executionStart := clock.Now()
+	record := ExecutionRecord{
+		CorrelationId: correlationId,
+		Time:          executionStart.UnixNano() / int64(time.Millisecond),
+	}
+	deadline, cancel := executionDeadline(schedule)
+	defer cancel()
+	maxRetryWait := retryAfterBound(schedule)
 
-		executingUrl := getUrlStr(scheduleEvent.Addressable)
-		LoggingClient.Debug("the event with id : " + eventId + " will request url : " + executingUrl)
+	//execute schedule event one by one
+	for _, scheduleEvent := range scheduleEvents {
+		eventId := scheduleEvent.Id.Hex()
+		if deadline.Err() != nil {
+			executionErr.Skipped = append(executionErr.Skipped, eventId)
+			continue
+		}
+		LoggingClient.Debug(executionLogMsg(correlationId, "the event with id : "+eventId+" belongs to schedule : "+schedule.Id.Hex()+" will be executing!"), correlationId)
+
+		startTime := clock.Now()
+		var statusCode, attempts int
+		eventCtx, stats := withRequestStats(deadline)
+		renderedEvent, err := resolveBodySource(deadline, scheduleEvent)
+		if err == nil {
+			renderedEvent, err = renderScheduleEvent(renderedEvent, templateData)
+		}
+		if err == nil {
+			renderedEvent.Headers = withIdempotencyKey(renderedEvent.Headers, idempotencyKey(schedule.Id.Hex(), eventId, templateData.FireTime))
+			if traceParent := span.TraceParent(); traceParent != "" {
+				renderedEvent.Headers = withDefaultHeader(renderedEvent.Headers, TraceParentHeader, traceParent)
+			}
+			statusCode, attempts, err = executeScheduleEventWithRetries(eventCtx, renderedEvent, correlationId, maxRetryWait)
+		}
+		if err != nil {
+			executionErr.Failures = append(executionErr.Failures, EventFailure{ScheduleEventId: eventId, Name: scheduleEvent.Name, Err: err})
+			addDeadLetter(scheduleEvent, correlationId, attempts, err)
+			getMetrics().IncFailure(statusClass(statusCode))
+		} else {
+			executionErr.Succeeded = append(executionErr.Succeeded, eventId)
+			if stats.responseBytes > 0 {
+				executionErr.Responded = append(executionErr.Responded, eventId)
+			}
+		}
+		duration := clock.Now().Sub(startTime)
+		fields := logFields{Msg: "executed the schedule event", ScheduleId: schedule.Id.Hex(), EventId: eventId, StatusCode: statusCode, CorrelationId: correlationId}
+		LoggingClient.Info(formatLogMsg(fields.withDuration(duration)), correlationId)
+		lastRun := newLastRun(startTime, statusCode, err, stats)
+		recordLastRun(eventId, lastRun)
+		notifyExecutionObservers(schedule.Id.Hex(), eventId, statusCode, duration, err)
+		postEventCallback(scheduleEvent, schedule.Id.Hex(), correlationId, statusCode, duration, err)
+		record.Events = append(record.Events, EventExecution{ScheduleEventId: eventId, LastRun: lastRun})
+	}
+	if executionErr.Partial() {
+		LoggingClient.Warn(executionLogMsg(correlationId, fmt.Sprintf("the execution passed its deadline of %d milliseconds, skipped %d schedule events", schedule.MaxExecutionMs, len(executionErr.Skipped))), correlationId)
+		record.Partial = true
+	}
+	recordExecution(schedule.Id.Hex(), record)
+	getMetrics().ObserveExecution(schedule.Id.Hex(), clock.Now().Sub(executionStart))
+	if executionErr.Failed() {
+		span.End(executionErr)
+	} else {
+		span.End(nil)
+	}
+	return executionErr
+}
 
-		//TODO: change the method type based on the event
+// Sort the events by their order, the events without an order run last sorted by name
+func orderedScheduleEvents(scheduleEventsMap map[string]models.ScheduleEvent) []models.ScheduleEvent {
+	scheduleEvents := make([]models.ScheduleEvent, 0, len(scheduleEventsMap))
+	for _, scheduleEvent := range scheduleEventsMap {
+		scheduleEvents = append(scheduleEvents, scheduleEvent)
+	}
 
-		httpMethod := scheduleEvent.Addressable.HTTPMethod
-		if !validMethod(httpMethod) {
-			LoggingClient.Error("net/http: invalid method %q", httpMethod)
-			return nil
+	sort.Slice(scheduleEvents, func(i, j int) bool {
+		a, b := scheduleEvents[i], scheduleEvents[j]
+		if a.Order != b.Order {
+			if a.Order == 0 || b.Order == 0 {
+				return b.Order == 0
+			}
+			return a.Order < b.Order
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
 		}
+		return a.Id < b.Id
+	})
+	return scheduleEvents
+}
 
-		req, err := http.NewRequest(httpMethod, executingUrl, nil)
-		req.Header.Set(ContentTypeKey, ContentTypeJsonValue)
+// the context of an execution, done once the MaxExecutionMs of the schedule has passed
+func executionDeadline(schedule models.Schedule) (context.Context, context.CancelFunc) {
+	if schedule.MaxExecutionMs <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), time.Duration(schedule.MaxExecutionMs)*time.Millisecond)
+}
 
-		params := strings.TrimSpace(scheduleEvent.Parameters)
+// Execute the schedule event, retrying the retryable failures up to the configured MaxRetries with an
+// exponential backoff starting at RetryBackoff milliseconds. Returns the number of attempts made.
+// A Retry-After of the target takes the place of the backoff, up to the max retry wait when it is set.
+// The retries stop once the execution deadline has passed.
+func executeScheduleEventWithRetries(ctx context.Context, scheduleEvent models.ScheduleEvent, correlationId string, maxRetryWait time.Duration) (int, int, error) {
+	maxRetries := 0
+	backoff := time.Duration(0)
+	if Configuration != nil {
+		maxRetries = Configuration.MaxRetries
+		backoff = time.Duration(Configuration.RetryBackoff) * time.Millisecond
+	}
 
-		if len(params) > 0 {
-			req.Header.Set(ContentLengthKey, string(len(params)))
+	attempts := 0
+	for {
+		attempts++
+		statusCode, err := executeScheduleEvent(ctx, scheduleEvent, correlationId)
+		if err == nil || attempts > maxRetries || !isRetryable(err) || ctx.Err() != nil {
+			return statusCode, attempts, err
 		}
 
-		if err != nil {
-			LoggingClient.Error("create new request occurs error : " + err.Error())
+		wait := retryWait(err, backoff, maxRetryWait)
+		LoggingClient.Warn(executionLogMsg(correlationId, fmt.Sprintf("attempt %d of the event with id : %s failed, retrying in %s", attempts, scheduleEvent.Id.Hex(), wait)), correlationId)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return statusCode, attempts, err
 		}
+		backoff *= 2
+	}
+}
+
+// Send the request of a single schedule event, returning the response status code. The request is cancelled
+// when the context is done. The events targeting an MQTT addressable are published to its broker instead.
+func executeScheduleEvent(ctx context.Context, scheduleEvent models.ScheduleEvent, correlationId string) (int, error) {
+	if isMQTTAddressable(scheduleEvent.Addressable) {
+		return publishScheduleEvent(scheduleEvent, correlationId)
+	}
+
+	eventId := scheduleEvent.Id.Hex()
+	httpMethod := strings.ToUpper(strings.TrimSpace(scheduleEvent.Addressable.HTTPMethod))
+	if !validMethod(httpMethod) {
+		logMsg := fmt.Sprintf("net/http: invalid method %q for the event with id : %s", httpMethod, eventId)
+		LoggingClient.Error(executionLogMsg(correlationId, logMsg), correlationId)
+		return 0, errors.New(logMsg)
+	}
 
-		client := &http.Client{
-			Timeout: time.Duration(Configuration.Service.Timeout) * time.Millisecond,
+	//any method but GET and HEAD carries the parameters as its body, unless they are a query string
+	var body io.Reader
+	var bodyStr string
+	var payload []byte
+	params := strings.TrimSpace(scheduleEvent.Parameters)
+	query, paramsInQuery := eventQuery(scheduleEvent.QueryParams, params, httpMethod)
+	if len(params) > 0 && !paramsInQuery && httpMethod != http.MethodGet && httpMethod != http.MethodHead {
+		bodyStr = params
+		payload = []byte(bodyStr)
+		if scheduleEvent.CompressBody {
+			compressed, err := gzipBody(bodyStr)
+			if err != nil {
+				logMsg := fmt.Sprintf("compress the body of the event with id : %s occurs error : %s", eventId, err.Error())
+				LoggingClient.Error(executionLogMsg(correlationId, logMsg), correlationId)
+				return 0, errors.New(logMsg)
+			}
+			payload = compressed
 		}
-		responseBytes, statusCode, err := sendRequestAndGetResponse(client, req)
-		responseStr := string(responseBytes)
+		body = bytes.NewReader(payload)
+	}
+	eventUrl, err := buildEventUrl(scheduleEvent.Addressable)
+	if err != nil {
+		logMsg := fmt.Sprintf("build the url of the event with id : %s occurs error : %s", eventId, err.Error())
+		LoggingClient.Error(executionLogMsg(correlationId, logMsg), correlationId)
+		return 0, errors.New(logMsg)
+	}
+	executingUrl := addQuery(eventUrl, query)
+	LoggingClient.Debug(executionLogMsg(correlationId, "the event with id : "+eventId+" will request url : "+executingUrl), correlationId)
 
-		LoggingClient.Debug(fmt.Sprintf("execution returns status code : %d", statusCode))
-		LoggingClient.Debug("execution returns response content : " + responseStr)
+	req, err := http.NewRequest(httpMethod, executingUrl, body)
+	if err != nil {
+		logMsg := fmt.Sprintf("create new request for the event with id : %s occurs error : %s", eventId, err.Error())
+		LoggingClient.Error(executionLogMsg(correlationId, logMsg), correlationId)
+		return 0, errors.New(logMsg)
 	}
+	req = req.WithContext(withRedirectPolicy(ctx, scheduleEvent))
+	setEventHeaders(req, scheduleEvent, correlationId)
+	if scheduleEvent.CompressBody && body != nil {
+		req.Header.Set(ContentEncodingKey, GzipEncoding)
+	}
+	//the signature covers the body as it is sent
+	signRequest(req, payload)
 
-	context.UpdateNextTime()
-	context.UpdateIterations()
+	if logBodies() {
+		LoggingClient.Debug(executionLogMsg(correlationId, fmt.Sprintf("the event with id : %s sends headers : %v with body : %s", eventId, redactHeaders(req.Header), redactBody(bodyStr))), correlationId)
+	}
 
-	if context.IsComplete() {
-		LoggingClient.Debug("completed schedule, detail : " + context.GetInfo())
-	} else {
-		LoggingClient.Debug("requeue schedule, detail : " + context.GetInfo())
-		scheduleQueue.Add(context)
+	if isDryRun() {
+		LoggingClient.Info(executionLogMsg(correlationId, fmt.Sprintf("dry run, the event with id : %s would send %s %s with body : %s", eventId, httpMethod, executingUrl, redactBody(params))), correlationId)
+		return 0, nil
+	}
+
+	responseBytes, statusCode, err, shared := sendDeduplicated(req.Context(), dedupeKey(req, payload), scheduleEvent.Schedule, func() ([]byte, int, error) {
+		return sendEventRequest(req, scheduleEvent, correlationId)
+	})
+	if shared {
+		LoggingClient.Info(executionLogMsg(correlationId, fmt.Sprintf("the event with id : %s shares an identical request sent within the dedupe window", eventId)), correlationId)
+	}
+
+	LoggingClient.Debug(executionLogMsg(correlationId, fmt.Sprintf("execution returns status code : %d", statusCode)), correlationId)
+	if logBodies() {
+		LoggingClient.Debug(executionLogMsg(correlationId, "execution returns response content : "+redactBody(string(responseBytes))), correlationId)
+	}
+
+	err = checkStatus(scheduleEvent, req.URL.String(), statusCode, err)
+	if err == nil {
+		err = checkResponse(scheduleEvent, req.URL.String(), responseBytes)
+	}
+	if err != nil {
+		LoggingClient.Error(formatLogMsg(logFields{Msg: "the schedule event failed : " + err.Error(), EventId: eventId, StatusCode: statusCode, CorrelationId: correlationId}), correlationId)
+		return statusCode, err
+	}
+	return statusCode, nil
+}
+
+// Send the request of the event once a token of the rate limit and a request slot of its host are free
+func sendEventRequest(req *http.Request, scheduleEvent models.ScheduleEvent, correlationId string) ([]byte, int, error) {
+	eventId := scheduleEvent.Id.Hex()
+	if err := waitForRequestToken(req.Context()); err != nil {
+		LoggingClient.Error(executionLogMsg(correlationId, fmt.Sprintf("the event with id : %s gave up waiting for the rate limit : %s", eventId, err.Error())), correlationId)
+		return []byte{}, 0, err
+	}
+
+	release, err := acquireHostSlot(req.Context(), scheduleEvent.Addressable)
+	if err != nil {
+		LoggingClient.Error(executionLogMsg(correlationId, fmt.Sprintf("the event with id : %s gave up waiting for a request slot of %s : %s", eventId, hostKey(scheduleEvent.Addressable), err.Error())), correlationId)
+		return []byte{}, 0, err
+	}
+	defer release()
+	return sendRequestAndGetResponse(getHTTPClient(), req)
+}
+
+// a successful response still fails the event when its body does not match the expected response
+func checkResponse(scheduleEvent models.ScheduleEvent, url string, body []byte) error {
+	if scheduleEvent.ExpectResponse == "" {
+		return nil
+	}
+	expected, err := regexp.Compile(scheduleEvent.ExpectResponse)
+	if err != nil {
+		return err
+	}
+	if !expected.Match(body) {
+		return ErrUnexpectedResponse{Url: url, Expected: scheduleEvent.ExpectResponse}
 	}
 	return nil
 }
 
-func getUrlStr(addressable models.Addressable) string {
-	return addressable.GetBaseURL() + addressable.Path
+// the configured User-Agent of the requests, identifying the scheduler with its version by default
+func userAgent() string {
+	if Configuration == nil || Configuration.UserAgent == "" {
+		return "edgex-scheduler/" + edgex.Version
+	}
+	return Configuration.UserAgent
+}
+
+// in a dry run the events are logged instead of being sent while the schedules still progress
+func isDryRun() bool {
+	return Configuration != nil && Configuration.DryRun
+}
+
+func executionLogMsg(correlationId string, msg string) string {
+	return formatLogMsg(logFields{Msg: msg, CorrelationId: correlationId})
+}
+
+// Build the url of an HTTP addressable from its protocol, address, port and path. The path is joined to the
+// host with a single slash whether or not it starts with one, and may carry a query string.
+func buildEventUrl(addressable models.Addressable) (string, error) {
+	scheme := strings.ToLower(strings.TrimSpace(addressable.Protocol))
+	if scheme != "http" && scheme != "https" {
+		return "", fmt.Errorf("missing http or https scheme, got the protocol %q", addressable.Protocol)
+	}
+	host := strings.Trim(strings.TrimSpace(addressable.Address), "[]")
+	if host == "" || strings.ContainsAny(host, "/?#@ ") {
+		return "", fmt.Errorf("invalid address %q, expected a host name or an IP address", addressable.Address)
+	}
+	if addressable.Port < 1 || addressable.Port > 65535 {
+		return "", fmt.Errorf("invalid port %d", addressable.Port)
+	}
+
+	path := strings.TrimSpace(addressable.Path)
+	if path != "" {
+		path = "/" + strings.TrimLeft(path, "/")
+	}
+	ref, err := url.Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid path %q : %s", addressable.Path, err.Error())
+	}
+
+	eventUrl := url.URL{
+		Scheme:   scheme,
+		Host:     net.JoinHostPort(host, strconv.Itoa(addressable.Port)),
+		Path:     ref.Path,
+		RawPath:  ref.RawPath,
+		RawQuery: ref.RawQuery,
+	}
+	return eventUrl.String(), nil
+}
+
+// The query of the request is made of the query parameters of the event and, for the methods which usually
+// carry no body, of its parameters when they are a URL-encoded query string. Reports whether the parameters
+// went into the query.
+func eventQuery(queryParams map[string]string, params string, method string) (url.Values, bool) {
+	query := url.Values{}
+	for key, value := range queryParams {
+		query.Set(key, value)
+	}
+
+	paramsInQuery := false
+	if method == http.MethodGet || method == http.MethodHead || method == http.MethodDelete {
+		if values, err := url.ParseQuery(params); err == nil && isQueryString(params) {
+			for key := range values {
+				query[key] = append(query[key], values[key]...)
+			}
+			paramsInQuery = true
+		}
+	}
+	return query, paramsInQuery
+}
+
+// a JSON body or free text is not mistaken for a query string
+func isQueryString(params string) bool {
+	return len(params) > 0 && strings.Contains(params, "=") && !strings.HasPrefix(params, "{") &&
+		!strings.HasPrefix(params, "[") && !strings.ContainsAny(params, " \t\n\"")
 }
 
-func sendRequestAndGetResponse(client *http.Client, req *http.Request) ([]byte, int, error) {
+// append the query to the url, keeping the query already present in its path
+func addQuery(rawUrl string, query url.Values) string {
+	if len(query) == 0 {
+		return rawUrl
+	}
+	separator := "?"
+	if strings.Contains(rawUrl, "?") {
+		separator = "&"
+	}
+	return rawUrl + separator + query.Encode()
+}
+
+func sendRequestAndGetResponse(client HTTPClient, req *http.Request) ([]byte, int, error) {
+	sentAt := time.Now()
 	resp, err := client.Do(req)
 
 	if err != nil {
-		println(err.Error())
-		return []byte{}, 500, err
+		transportErr := ErrTransport{Url: req.URL.String(), Err: err}
+		LoggingClient.Error(transportErr.Error())
+		return []byte{}, 0, transportErr
 	}
 
 	defer resp.Body.Close()
-	resp.Close = true
 
-	bodyBytes, err := ioutil.ReadAll(resp.Body)
+	//read one byte past the limit to tell a body of exactly the limit from a larger one, the limit applies to the decoded body
+	limit := maxResponseBytes()
+	decoded, err := decodedBody(resp)
 	if err != nil {
-		return []byte{}, 500, err
+		return []byte{}, resp.StatusCode, err
+	}
+	bodyBytes, err := ioutil.ReadAll(io.LimitReader(decoded, limit+1))
+	if err != nil {
+		return []byte{}, resp.StatusCode, err
+	}
+	if int64(len(bodyBytes)) > limit {
+		LoggingClient.Warn(fmt.Sprintf("the response of %s %s exceeds %d bytes and has been truncated", req.Method, req.URL.String(), limit))
+		bodyBytes = bodyBytes[:limit]
+	}
+	recordRequestStats(req, len(bodyBytes), time.Since(sentAt))
+
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		retryAfter := parseRetryAfter(resp.Header.Get(RetryAfterHeader), clock.Now())
+		return bodyBytes, resp.StatusCode, ErrServerResponse{Url: req.URL.String(), StatusCode: resp.StatusCode, RetryAfter: retryAfter}
 	}
 
 	return bodyBytes, resp.StatusCode, nil
 }
 
+// get the configured cap on the response body size, falling back to the default when unset
+func maxResponseBytes() int64 {
+	if Configuration == nil || Configuration.MaxResponseBytes <= 0 {
+		return DefaultMaxResponseBytes
+	}
+	return int64(Configuration.MaxResponseBytes)
+}
+
 func validMethod(method string) bool {
 	/*
 	     Method         = "OPTIONS"                ; Section 9.2
@@ -517,7 +1369,7 @@ func validMethod(method string) bool {
 	   extension-method = token
 	     token          = 1*<any CHAR except CTLs or separators>
 	*/
-	a := []string{"GET", "HEAD", "POST", "PUT", "DELETE", "TRACE", "CONNECT"}
+	a := []string{"OPTIONS", "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "TRACE", "CONNECT"}
 	method = strings.ToUpper(method)
 	return contains(a, method)
 }
@@ -537,7 +1389,8 @@ func getMetadataSchedules() ([]models.Schedule, error) {
 	var receivedSchedules []models.Schedule
 	receivedSchedules, errSchedule := msc.Schedules()
 	if errSchedule != nil {
-		return receivedSchedules, LoggingClient.Error(fmt.Sprintf("error connecting to metadata and retrieving schedules %s", errSchedule.Error()))
+		LoggingClient.Error(fmt.Sprintf("error connecting to metadata and retrieving schedules %s", errSchedule.Error()))
+		return receivedSchedules, errSchedule
 	}
 
 	if receivedSchedules != nil {
@@ -555,7 +1408,8 @@ func getMetadataScheduleEvents() ([]models.ScheduleEvent, error) {
 	var receivedScheduleEvents []models.ScheduleEvent
 	receivedScheduleEvents, err := msec.ScheduleEvents()
 	if err != nil {
-		return receivedScheduleEvents, LoggingClient.Error(fmt.Sprintf("error connecting to metadata and retrieving schedule events: %s", err.Error()))
+		LoggingClient.Error(fmt.Sprintf("error connecting to metadata and retrieving schedule events: %s", err.Error()))
+		return receivedScheduleEvents, err
 	}
 
 	// debug information only
@@ -569,9 +1423,12 @@ func getMetadataScheduleEvents() ([]models.ScheduleEvent, error) {
 	return receivedScheduleEvents, nil
 }
 
-// Iterate over the received schedules add them to scheduler
+// Iterate over the received schedules add them to scheduler, the caller holds the schedule mutex.
+// The schedules already past their End or MaxIterations are skipped.
 func addReceivedSchedules(schedules []models.Schedule) error {
 
+	expiredScheduleNames = make(map[string]bool)
+	iterations := stateIterations()
 	for _, schedule := range schedules {
 		// todo: need to remove this naming convention based inference
 		matched, err := regexp.MatchString("device.*", schedule.Name)
@@ -580,11 +1437,12 @@ func addReceivedSchedules(schedules []models.Schedule) error {
 			return err
 		}
 		// we have a service related notification
-		if !matched {
-			err := addSchedule(schedule)
+		if !matched && !skipExpiredScheduleLocked(schedule, iterations) {
+			//an invalid schedule is skipped, the others still load
+			err := addScheduleLocked(schedule)
 			if err != nil {
-				LoggingClient.Info(fmt.Sprintf("error adding core-metadata schedule name: %s - %s", schedule.Name, err.Error()))
-				return err
+				LoggingClient.Error(fmt.Sprintf("error adding core-metadata schedule name: %s - %s, the schedule will not be loaded", schedule.Name, err.Error()))
+				continue
 			}
 			LoggingClient.Info(fmt.Sprintf("added schedule name: %s to the schedule id: %s ", schedule.Name, schedule.Id.Hex()))
 		}
@@ -592,7 +1450,7 @@ func addReceivedSchedules(schedules []models.Schedule) error {
 	return nil
 }
 
-// Iterate over the received schedule event(s)
+// Iterate over the received schedule event(s), the caller holds the schedule mutex
 func addReceivedScheduleEvents(scheduleEvents []models.ScheduleEvent) error {
 
 	for _, scheduleEvent := range scheduleEvents {
@@ -602,12 +1460,17 @@ func addReceivedScheduleEvents(scheduleEvents []models.ScheduleEvent) error {
 			LoggingClient.Info(fmt.Sprintf("error parsing recevied core-metadata schedules %s", err.Error()))
 			return err
 		}
+		if expiredScheduleNames[scheduleEvent.Schedule] {
+			LoggingClient.Debug(fmt.Sprintf("did not add schedule event name: %s as its schedule %s has expired", scheduleEvent.Name, scheduleEvent.Schedule))
+			continue
+		}
 		// schedule event service should not be device.*
 		if !matched {
-			err := addScheduleEvent(scheduleEvent)
+			//the events of a schedule which was not loaded are skipped along with it
+			err := addScheduleEventLocked(scheduleEvent)
 			if err != nil {
-				LoggingClient.Info(fmt.Sprintf("error adding core-metadata schedule event name: %s - %s", scheduleEvent.Name, err.Error()))
-				return err
+				LoggingClient.Error(fmt.Sprintf("error adding core-metadata schedule event name: %s - %s, the event will not be loaded", scheduleEvent.Name, err.Error()))
+				continue
 			}
 			LoggingClient.Info(fmt.Sprintf("added schedule event name: %s to the schedule name: %s  schedule event id: %s", scheduleEvent.Name, scheduleEvent.Schedule, scheduleEvent.Id.Hex()))
 		}
@@ -619,18 +1482,26 @@ func addReceivedScheduleEvents(scheduleEvents []models.ScheduleEvent) error {
 // Utility function for adding configured locally schedulers and scheduled events
 func AddSchedulers() error {
 
-	// ensure maps are clean
-	clearMaps()
-
-	// ensure queue is empty
-	clearQueue()
-
 	LoggingClient.Info(fmt.Sprintf("Loading schedules, schedule events, and addressables ..."))
 
-	// load data from core-metadata
-	err := loadCoreMetadataInformation()
+	// load data from core-metadata, or only the config when it stays unavailable and that is allowed
+	err := loadCoreMetadataInformationWithRetries()
 	if err != nil {
-		return LoggingClient.Error("failed to load information from core-metadata", err.Error())
+		if Configuration == nil || !Configuration.AllowDegradedStart {
+			LoggingClient.Error("failed to load information from core-metadata", err.Error())
+			return err
+		}
+		setDegradedMode(true)
+		LoggingClient.Warn(fmt.Sprintf("core-metadata is unavailable, starting in degraded mode with the config schedules only : %s", err.Error()))
+	} else {
+		setDegradedMode(false)
+		LoggingClient.Info("loaded the schedules from core-metadata")
+	}
+
+	// report every problem of the config before any of it is loaded
+	if err := validateConfig(Configuration.Schedules, Configuration.ScheduleEvents); err != nil {
+		LoggingClient.Error(err.Error())
+		return err
 	}
 
 	// load config schedules
@@ -645,6 +1516,13 @@ func AddSchedulers() error {
 		return LoggingClient.Error("failed to load scheduler events config data", errCSE.Error())
 	}
 
+	// restore the progress and runtime schedules from before the restart
+	if err := restoreState(); err != nil {
+		LoggingClient.Error("failed to restore the scheduler state", err.Error())
+	}
+
+	spreadStartupFires(clock.Now())
+
 	LoggingClient.Info(fmt.Sprintf("completed loading schedules, schedule events, and addressables"))
 
 	return nil
@@ -653,27 +1531,38 @@ func AddSchedulers() error {
 func loadConfigSchedules() error {
 
 	schedules := Configuration.Schedules
+	iterations := stateIterations()
 	for i := range schedules {
-		schedule := models.Schedule{
-			BaseObject: models.BaseObject{},
-			Name:       schedules[i].Name,
-			Start:      schedules[i].Start,
-			End:        schedules[i].End,
-			Frequency:  schedules[i].Frequency,
-			Cron:       schedules[i].Cron,
-			RunOnce:    schedules[i].RunOnce,
+		schedule := scheduleFromConfig(schedules[i])
+		if skipExpiredSchedule(schedule, iterations) {
+			continue
 		}
 		_, errExistingSchedule := queryScheduleByName(schedule.Name)
 
 		if errExistingSchedule != nil {
-			// add the schedule core-metadata
-			newScheduleId, errAddedSchedule := addScheduleToCoreMetaData(schedule)
-			if errAddedSchedule != nil {
-				return LoggingClient.Error("error adding schedule %s to the scheduler", errAddedSchedule.Error())
-			}
+			if isDegradedMode() {
+				// core-metadata is unavailable, the schedule only lives in the scheduler
+				schedule.Id = bson.NewObjectId()
+			} else {
+				if errOneOff := validateOneOffStart(schedule); errOneOff != nil {
+					LoggingClient.Error(errOneOff.Error())
+					return errOneOff
+				}
 
-			// add the core-metadata scheduler.id
-			schedule.Id = bson.ObjectId(newScheduleId)
+				// add the schedule core-metadata
+				newScheduleId, errAddedSchedule := addScheduleToCoreMetaData(&schedule)
+				if errAddedSchedule != nil {
+					return LoggingClient.Error("error adding schedule %s to the scheduler", errAddedSchedule.Error())
+				}
+
+				// add the core-metadata scheduler.id
+				scheduleId, errMalformedId := metadataObjectId("schedule", schedule.Name, newScheduleId)
+				if errMalformedId != nil {
+					LoggingClient.Error(errMalformedId.Error())
+					return errMalformedId
+				}
+				schedule.Id = scheduleId
+			}
 
 			// add the schedule to the scheduler
 			err := addSchedule(schedule)
@@ -696,51 +1585,62 @@ func loadConfigScheduleEvents() error {
 
 	for e := range scheduleEvents {
 
-		addressable := models.Addressable{
-			Name:       fmt.Sprintf("schedule-%s", scheduleEvents[e].Name),
-			Path:       scheduleEvents[e].Path,
-			Port:       scheduleEvents[e].Port,
-			Protocol:   scheduleEvents[e].Protocol,
-			HTTPMethod: scheduleEvents[e].Method,
-			Address:    scheduleEvents[e].Host,
-		}
+		scheduleEvent := scheduleEventFromConfig(scheduleEvents[e])
 
-		scheduleEvent := models.ScheduleEvent{
-			//Id:          bson.NewObjectId(),
-			Name:        scheduleEvents[e].Name,
-			Schedule:    scheduleEvents[e].Schedule,
-			Parameters:  scheduleEvents[e].Parameters,
-			Service:     scheduleEvents[e].Service,
-			Addressable: addressable,
+		// a misconfigured event is reported and skipped so the rest of the events still load
+		err := validateAddressable(scheduleEvent)
+		if err == nil {
+			err = validateExpectResponse(scheduleEvent)
+		}
+		if err == nil {
+			err = validateSuccessStatus(scheduleEvent)
+		}
+		if err == nil {
+			err = validateCallbacks(scheduleEvent)
+		}
+		if err == nil {
+			err = validateBodySource(scheduleEvent)
+		}
+		if err != nil {
+			LoggingClient.Error(fmt.Sprintf("%s, the event will not be loaded", err.Error()))
+			continue
+		}
+		if isExpiredSchedule(scheduleEvent.Schedule) {
+			LoggingClient.Debug(fmt.Sprintf("did not load schedule event name: %s as its schedule %s has expired", scheduleEvent.Name, scheduleEvent.Schedule))
+			continue
+		}
+		if _, err := queryScheduleByName(scheduleEvent.Schedule); err != nil {
+			LoggingClient.Error(fmt.Sprintf("schedule %q not found for event %q, the event will not be loaded", scheduleEvent.Schedule, scheduleEvent.Name))
+			continue
 		}
 
 		// fetch existing queue and determine of scheduleEvent exists
-		_, err := queryScheduleEventByName(scheduleEvent.Name)
+		_, err = queryScheduleEventByName(scheduleEvent.Name)
 
 		if err != nil {
-			// query core-metadata for addressable
-			_, err := mac.AddressableForName(addressable.Name)
-			if err != nil {
-				// we don't have that addressable yet now add it
-				addressableId, err := mac.Add(&addressable)
-				if err != nil {
+			if isDegradedMode() {
+				// core-metadata is unavailable, the schedule event only lives in the scheduler
+				scheduleEvent.Id = bson.NewObjectId()
+			} else {
+				// add the addressable when core-metadata does not have it yet
+				if err := addAddressableToCoreMetadata(&scheduleEvent.Addressable); err != nil {
 					return LoggingClient.Error("error adding new addressable into core-metadata", err.Error())
 				}
-				LoggingClient.Info(fmt.Sprintf("added addressable into core-metadata name: %s id: %s path: %s", addressable.Name, addressableId, addressable.Path))
 
-				// add the core-metadata id value
-				addressable.Id = bson.ObjectId(addressableId)
-			}
+				// add the schedule event with addressable event to core-metadata
+				newScheduleEventId, err := addScheduleEventToCoreMetadata(&scheduleEvent)
+				if err != nil {
+					return LoggingClient.Error("error adding schedule event %s into core-metadata", err.Error())
+				}
 
-			// add the schedule event with addressable event to core-metadata
-			newScheduleEventId, err := addScheduleEventToCoreMetadata(scheduleEvent)
-			if err != nil {
-				return LoggingClient.Error("error adding schedule event %s into core-metadata", err.Error())
+				// add the core-metadata version of the scheduleEvent.Id
+				scheduleEvent.Id, err = metadataObjectId("schedule event", scheduleEvent.Name, newScheduleEventId)
+				if err != nil {
+					LoggingClient.Error(err.Error())
+					return err
+				}
 			}
 
-			// add the core-metadata version of the scheduleEvent.Id
-			scheduleEvent.Id = bson.ObjectId(newScheduleEventId)
-
 			errAddSE := addScheduleEvent(scheduleEvent)
 			if errAddSE != nil {
 				return LoggingClient.Error("error loading schedule event %s into scheduler", errAddSE.Error())
@@ -753,6 +1653,120 @@ func loadConfigScheduleEvents() error {
 	return nil
 }
 
+// the schedule of the configuration, the id is set once it is stored
+func scheduleFromConfig(info config.ScheduleInfo) models.Schedule {
+	return models.Schedule{
+		BaseObject:       models.BaseObject{},
+		Name:             info.Name,
+		Start:            info.Start,
+		End:              info.End,
+		Frequency:        info.Frequency,
+		Cron:             info.Cron,
+		RunOnce:          info.RunOnce,
+		Timezone:         info.Timezone,
+		JitterMs:         info.JitterMs,
+		MissedFirePolicy: info.MissedFirePolicy,
+		AlignToInterval:  info.AlignToInterval,
+		Tags:             info.Tags,
+		MaxExecutionMs:   info.MaxExecutionMs,
+		MaxIterations:    info.MaxIterations,
+		IntervalPolicy:   info.IntervalPolicy,
+		IntervalFactor:   info.IntervalFactor,
+		IntervalLimit:    info.IntervalLimit,
+		ResetInterval:    info.ResetInterval,
+		WindowDays:       info.WindowDays,
+		WindowStart:      info.WindowStart,
+		WindowEnd:        info.WindowEnd,
+	}
+}
+
+// the schedule event of the configuration along with its addressable, the id is set once it is stored
+func scheduleEventFromConfig(info config.ScheduleEventInfo) models.ScheduleEvent {
+	addressable := models.Addressable{
+		Name:       fmt.Sprintf("schedule-%s", info.Name),
+		Path:       info.Path,
+		Port:       info.Port,
+		Protocol:   info.Protocol,
+		HTTPMethod: info.Method,
+		Address:    info.Host,
+	}
+
+	return models.ScheduleEvent{
+		Name:           info.Name,
+		Schedule:       info.Schedule,
+		Parameters:     info.Parameters,
+		Service:        info.Service,
+		Addressable:    addressable,
+		Order:          info.Order,
+		ExpectResponse: info.ExpectResponse,
+		QueryParams:    info.QueryParams,
+		Headers:        info.Headers,
+		CompressBody:   info.CompressBody,
+		BodySource:     info.BodySource,
+		NoRedirects:    info.NoRedirects,
+		MaxRedirects:   info.MaxRedirects,
+		SuccessStatus:  info.SuccessStatus,
+		OnSuccessURL:   info.OnSuccessURL,
+		OnFailureURL:   info.OnFailureURL,
+	}
+}
+
+// Check a schedule event received at runtime is complete and can be dispatched
+func validateScheduleEvent(scheduleEvent models.ScheduleEvent) error {
+	if scheduleEvent.Name == "" {
+		return errors.New("the schedule event has no name")
+	}
+	if scheduleEvent.Schedule == "" {
+		return fmt.Errorf("the schedule event %q has no schedule", scheduleEvent.Name)
+	}
+	if err := validateAddressable(scheduleEvent); err != nil {
+		return err
+	}
+	if !isMQTTAddressable(scheduleEvent.Addressable) && !validMethod(scheduleEvent.Addressable.HTTPMethod) {
+		return fmt.Errorf("the schedule event %q has an invalid http method %q", scheduleEvent.Name, scheduleEvent.Addressable.HTTPMethod)
+	}
+	if err := validateSuccessStatus(scheduleEvent); err != nil {
+		return err
+	}
+	if err := validateCallbacks(scheduleEvent); err != nil {
+		return err
+	}
+	if err := validateBodySource(scheduleEvent); err != nil {
+		return err
+	}
+	return validateExpectResponse(scheduleEvent)
+}
+
+func validateExpectResponse(scheduleEvent models.ScheduleEvent) error {
+	if _, err := regexp.Compile(scheduleEvent.ExpectResponse); err != nil {
+		return fmt.Errorf("the schedule event %q has an invalid expected response %q : %s", scheduleEvent.Name, scheduleEvent.ExpectResponse, err.Error())
+	}
+	return nil
+}
+
+// Check the addressable of a schedule event can be dispatched before it is loaded
+func validateAddressable(scheduleEvent models.ScheduleEvent) error {
+	addressable := scheduleEvent.Addressable
+	switch strings.ToUpper(strings.TrimSpace(addressable.Protocol)) {
+	case HTTPProtocol, HTTPSProtocol, MQTTProtocol:
+	default:
+		return fmt.Errorf("the schedule event %q has an unsupported protocol %q, expected one of %s, %s or %s",
+			scheduleEvent.Name, addressable.Protocol, HTTPProtocol, HTTPSProtocol, MQTTProtocol)
+	}
+
+	if addressable.Port < 1 || addressable.Port > 65535 {
+		return fmt.Errorf("the schedule event %q has an invalid port %d, expected a port between 1 and 65535", scheduleEvent.Name, addressable.Port)
+	}
+	if !isMQTTAddressable(addressable) {
+		if _, err := buildEventUrl(addressable); err != nil {
+			return fmt.Errorf("the schedule event %q has an invalid url : %s", scheduleEvent.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// The schedules and events are fetched from core-metadata before taking the lock and replace the current
+// ones under it, so queries see either the old or the new schedules and never an empty scheduler
 func loadCoreMetadataInformation() error {
 
 	receivedSchedules, err := getMetadataSchedules()
@@ -761,29 +1775,89 @@ func loadCoreMetadataInformation() error {
 		return err
 	}
 
-	err = addReceivedSchedules(receivedSchedules)
+	receivedScheduleEvents, err := getMetadataScheduleEvents()
 	if err != nil {
-		LoggingClient.Error("failed to add received schedules from core-metadata %s", err.Error())
+		LoggingClient.Error("failed to receive schedule events from core-metadata %s", err.Error())
 		return err
 	}
 
-	receivedScheduleEvents, err := getMetadataScheduleEvents()
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	previous := currentScheduleMaps()
+	emptyScheduleMaps().restore()
+
+	err = addReceivedSchedules(receivedSchedules)
 	if err != nil {
-		LoggingClient.Error("failed to receive schedule events from core-metadata %s", err.Error())
+		LoggingClient.Error("failed to add received schedules from core-metadata %s", err.Error())
+		previous.restore()
 		return err
 	}
 
 	err = addReceivedScheduleEvents(receivedScheduleEvents)
 	if err != nil {
 		LoggingClient.Error("failed to add received schedule events from core-metadata %s", err.Error())
+		previous.restore()
 		return err
 	}
 
+	markStateChanged()
+
 	return nil
 }
-func addScheduleToCoreMetaData(schedule models.Schedule) (string, error) {
 
-	addedScheduleId, err := msc.Add(&schedule)
+// scheduleMaps holds the queue and the lookup maps of the scheduler so they can be swapped as a whole
+type scheduleMaps struct {
+	queue                                 *queueV1.Queue
+	scheduleIdToContextMap                map[string]*ScheduleContext
+	scheduleNameToContextMap              map[string]*ScheduleContext
+	scheduleEventIdToScheduleIdMap        map[string]string
+	scheduleEventNameToScheduleIdMap      map[string]string
+	scheduleEventNameToScheduleEventIdMap map[string]string
+	tagToScheduleIdsMap                   map[string]map[string]bool
+}
+
+func emptyScheduleMaps() scheduleMaps {
+	return scheduleMaps{
+		queue:                                 queueV1.New(),
+		scheduleIdToContextMap:                make(map[string]*ScheduleContext),
+		scheduleNameToContextMap:              make(map[string]*ScheduleContext),
+		scheduleEventIdToScheduleIdMap:        make(map[string]string),
+		scheduleEventNameToScheduleIdMap:      make(map[string]string),
+		scheduleEventNameToScheduleEventIdMap: make(map[string]string),
+		tagToScheduleIdsMap:                   make(map[string]map[string]bool),
+	}
+}
+
+// the caller holds the schedule mutex
+func currentScheduleMaps() scheduleMaps {
+	return scheduleMaps{
+		queue:                                 scheduleQueue,
+		scheduleIdToContextMap:                scheduleIdToContextMap,
+		scheduleNameToContextMap:              scheduleNameToContextMap,
+		scheduleEventIdToScheduleIdMap:        scheduleEventIdToScheduleIdMap,
+		scheduleEventNameToScheduleIdMap:      scheduleEventNameToScheduleIdMap,
+		scheduleEventNameToScheduleEventIdMap: scheduleEventNameToScheduleEventIdMap,
+		tagToScheduleIdsMap:                   tagToScheduleIdsMap,
+	}
+}
+
+// make these the queue and maps of the scheduler, the caller holds the schedule mutex
+func (m scheduleMaps) restore() {
+	scheduleQueue = m.queue
+	scheduleIdToContextMap = m.scheduleIdToContextMap
+	scheduleNameToContextMap = m.scheduleNameToContextMap
+	scheduleEventIdToScheduleIdMap = m.scheduleEventIdToScheduleIdMap
+	scheduleEventNameToScheduleIdMap = m.scheduleEventNameToScheduleIdMap
+	scheduleEventNameToScheduleEventIdMap = m.scheduleEventNameToScheduleEventIdMap
+	tagToScheduleIdsMap = m.tagToScheduleIdsMap
+}
+
+// Add the schedule to core-metadata, recording this scheduler instance as its originator
+func addScheduleToCoreMetaData(schedule *models.Schedule) (string, error) {
+	schedule.Originator = originatorId()
+
+	addedScheduleId, err := msc.Add(schedule)
 	if err != nil {
 		return "", LoggingClient.Error(fmt.Sprintf("error trying to add schedule to core-metadata service: %s", err.Error()))
 	}
@@ -791,14 +1865,39 @@ func addScheduleToCoreMetaData(schedule models.Schedule) (string, error) {
 	return addedScheduleId, nil
 }
 
-func addScheduleEventToCoreMetadata(scheduleEvent models.ScheduleEvent) (string, error) {
+// Add the schedule event to core-metadata, recording this scheduler instance as its originator
+func addScheduleEventToCoreMetadata(scheduleEvent *models.ScheduleEvent) (string, error) {
+	scheduleEvent.Originator = originatorId()
 
-	addedScheduleEventId, err := msec.Add(&scheduleEvent)
+	addedScheduleEventId, err := msec.Add(scheduleEvent)
 	if err != nil {
-		return "", LoggingClient.Error(fmt.Sprintf("error trying to add schedule event to core-metadata service: %s", err.Error()))
+		LoggingClient.Error(fmt.Sprintf("error trying to add schedule event to core-metadata service: %s", err.Error()))
+		return "", err
 	}
 	LoggingClient.Info(fmt.Sprintf("added schedule event %s to the core-metadata with id %s", scheduleEvent.Name, addedScheduleEventId))
 	return addedScheduleEventId, nil
 }
 
+// Add the addressable to core-metadata unless it already has one with the same name
+func addAddressableToCoreMetadata(addressable *models.Addressable) error {
+	if _, err := mac.AddressableForName(addressable.Name); err == nil {
+		return nil
+	}
+
+	addressableId, err := mac.Add(addressable)
+	if err != nil {
+		return err
+	}
+	LoggingClient.Info(fmt.Sprintf("added addressable into core-metadata name: %s id: %s path: %s", addressable.Name, addressableId, addressable.Path))
+
+	// add the core-metadata id value
+	id, err := metadataObjectId("addressable", addressable.Name, addressableId)
+	if err != nil {
+		LoggingClient.Error(err.Error())
+		return err
+	}
+	addressable.Id = id
+	return nil
+}
+
 //endregion
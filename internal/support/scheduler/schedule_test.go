@@ -0,0 +1,2102 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestMain(m *testing.M) {
+	LoggingClient = logger.NewMockClient()
+	Configuration = &ConfigurationStruct{}
+	os.Exit(m.Run())
+}
+
+// resetScheduler empties the queue and all the lookup maps between tests
+func resetScheduler() {
+	clearMaps()
+	clearQueue()
+}
+
+func TestTriggerScheduleStopsAfterEnd(t *testing.T) {
+	resetScheduler()
+
+	testSchedule := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      TestScheduleName,
+		Start:     "20180101T000000",
+		End:       "20180102T000000",
+		Frequency: "P1D",
+	}
+	if err := addSchedule(testSchedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+
+	triggerSchedule()
+
+	waitForExecutions()
+
+	if scheduleQueue.Length() != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 0)
+	}
+
+	context := scheduleIdToContextMap[testSchedule.Id.Hex()]
+	if context.CurrentIterations != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.CurrentIterations, 0)
+	}
+}
+
+func TestAddScheduleRejectsEndBeforeStart(t *testing.T) {
+	resetScheduler()
+
+	testSchedule := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      TestScheduleName,
+		Start:     "20180102T000000",
+		End:       "20180101T000000",
+		Frequency: "P1D",
+	}
+	if err := addSchedule(testSchedule); err == nil {
+		t.Error("expected an error adding a schedule which ends before it starts")
+	}
+
+	if scheduleQueue.Length() != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 0)
+	}
+	if _, exists := scheduleIdToContextMap[testSchedule.Id.Hex()]; exists {
+		t.Error("the misconfigured schedule should not have been added")
+	}
+}
+
+func TestTriggerScheduleWaitsForFutureStart(t *testing.T) {
+	resetScheduler()
+
+	testSchedule := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      TestScheduleName,
+		Start:     time.Now().Add(10 * time.Minute).UTC().Format(TIMELAYOUT),
+		Frequency: "PT1M",
+	}
+	if err := addSchedule(testSchedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+
+	triggerSchedule()
+
+	waitForExecutions()
+
+	context := scheduleIdToContextMap[testSchedule.Id.Hex()]
+	if context.CurrentIterations != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.CurrentIterations, 0)
+	}
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+}
+
+func TestTriggerScheduleFiresAfterPastStart(t *testing.T) {
+	resetScheduler()
+
+	testSchedule := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      TestScheduleName,
+		Start:     time.Now().Add(-time.Hour).UTC().Format(TIMELAYOUT),
+		Frequency: "PT1S",
+	}
+	if err := addSchedule(testSchedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+
+	context := scheduleIdToContextMap[testSchedule.Id.Hex()]
+	time.Sleep(context.NextTime.Sub(time.Now()) + 10*time.Millisecond)
+
+	triggerSchedule()
+
+	waitForExecutions()
+
+	if context.CurrentIterations != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.CurrentIterations, 1)
+	}
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+}
+
+func TestScheduleInterval(t *testing.T) {
+	defer func() { Configuration = &ConfigurationStruct{} }()
+
+	Configuration = &ConfigurationStruct{}
+	if scheduleInterval() != DefaultScheduleInterval*time.Millisecond {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleInterval(), DefaultScheduleInterval*time.Millisecond)
+	}
+
+	Configuration.ScheduleInterval = -10
+	if scheduleInterval() != DefaultScheduleInterval*time.Millisecond {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleInterval(), DefaultScheduleInterval*time.Millisecond)
+	}
+
+	Configuration.ScheduleInterval = 20
+	testTicker := newTicker()
+	defer testTicker.Stop()
+
+	begin := time.Now()
+	for i := 0; i < 5; i++ {
+		<-testTicker.C
+	}
+	elapsed := time.Since(begin)
+
+	if elapsed < 90*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf(TestUnexpectedMsgFormatStr, elapsed, 100*time.Millisecond)
+	}
+}
+
+// mockHTTPClient records the requests it receives and replies with a canned response
+type mockHTTPClient struct {
+	mutex      sync.Mutex
+	requests   []*http.Request
+	bodies     []string
+	statusCode int
+	response   string
+}
+
+func (c *mockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		body, _ = ioutil.ReadAll(req.Body)
+	}
+
+	c.mutex.Lock()
+	c.requests = append(c.requests, req)
+	c.bodies = append(c.bodies, string(body))
+	c.mutex.Unlock()
+
+	statusCode := c.statusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	return &http.Response{
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(strings.NewReader(c.response)),
+		Header:     make(http.Header),
+	}, nil
+}
+
+// newTestScheduleEvent builds a schedule event targeting the given path on localhost, the tests set their own
+// fields on it before it is registered
+func newTestScheduleEvent(schedule models.Schedule, name string, method string, path string, parameters string) models.ScheduleEvent {
+	return models.ScheduleEvent{
+		Id:         bson.NewObjectId(),
+		Name:       name,
+		Schedule:   schedule.Name,
+		Parameters: parameters,
+		Addressable: models.Addressable{
+			Name:       name,
+			Protocol:   "http",
+			HTTPMethod: method,
+			Address:    "localhost",
+			Port:       48080,
+			Path:       path,
+		},
+	}
+}
+
+func mustAddScheduleEvent(t *testing.T, scheduleEvent models.ScheduleEvent) models.ScheduleEvent {
+	if err := addScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+	return scheduleEvent
+}
+
+// addTestScheduleEvent registers a schedule event targeting the given path on localhost
+func addTestScheduleEvent(t *testing.T, schedule models.Schedule, name string, method string, path string, parameters string) models.ScheduleEvent {
+	return mustAddScheduleEvent(t, newTestScheduleEvent(schedule, name, method, path, parameters))
+}
+
+// newTestSchedule builds a daily schedule with the given name, the tests set their own fields on it before it is
+// registered
+func newTestSchedule(name string) models.Schedule {
+	return models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      name,
+		Start:     "20180101T000000",
+		Frequency: "P1D",
+	}
+}
+
+func mustAddSchedule(t *testing.T, schedule models.Schedule) models.Schedule {
+	if err := addSchedule(schedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	return schedule
+}
+
+// addTestSchedule registers a daily schedule with the given name
+func addTestSchedule(t *testing.T, name string) models.Schedule {
+	return mustAddSchedule(t, newTestSchedule(name))
+}
+
+// executeSchedule runs a single execution of the schedule with the given id
+func executeSchedule(scheduleId string) error {
+	return execute(scheduleIdToContextMap[scheduleId])
+}
+
+func TestReplyScheduleEvents(t *testing.T) {
+	resetScheduler()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	first := addTestScheduleEvent(t, schedule, "first", http.MethodGet, "/api/v1/ping", "")
+	second := addTestScheduleEvent(t, schedule, "second", http.MethodPost, "/api/v1/event", `{"age":5}`)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schedule/"+TestScheduleName+"/events", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	var scheduleEvents []models.ScheduleEvent
+	if err := json.NewDecoder(rec.Body).Decode(&scheduleEvents); err != nil {
+		t.Fatalf("unexpected error decoding the schedule events : %s", err.Error())
+	}
+	if len(scheduleEvents) != 2 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(scheduleEvents), 2)
+	}
+	if scheduleEvents[0].Id != first.Id || scheduleEvents[1].Id != second.Id {
+		t.Errorf(TestUnexpectedMsgFormatStr, []string{scheduleEvents[0].Name, scheduleEvents[1].Name}, []string{"first", "second"})
+	}
+
+	//an unknown or deleted schedule is not found
+	scheduleIdToContextMap[schedule.Id.Hex()].MarkedDeleted = true
+	for _, name := range []string{"unknown", TestScheduleName} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/schedule/"+name+"/events", nil)
+		rec := httptest.NewRecorder()
+		LoadRestRoutes().ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestReplyScheduleByName(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, "first", http.MethodGet, "/api/v1/ping", "")
+	addTestScheduleEvent(t, schedule, "second", http.MethodGet, "/api/v1/ping", "")
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schedule/name/"+TestScheduleName, nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	var detail ScheduleDetail
+	if err := json.NewDecoder(rec.Body).Decode(&detail); err != nil {
+		t.Fatalf("unexpected error decoding the schedule : %s", err.Error())
+	}
+	if detail.Schedule.Id != schedule.Id || detail.Schedule.Frequency != schedule.Frequency {
+		t.Errorf(TestUnexpectedMsgFormatStr, detail.Schedule.Id.Hex()+" "+detail.Schedule.Frequency, schedule.Id.Hex()+" "+schedule.Frequency)
+	}
+	nextTime := scheduleIdToContextMap[schedule.Id.Hex()].NextTime.UnixNano() / int64(time.Millisecond)
+	if detail.NextTime != nextTime {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, detail.NextTime, nextTime)
+	}
+	if detail.Iterations != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, detail.Iterations, 1)
+	}
+	if detail.EventCount != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, detail.EventCount, 2)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/schedule/name/unknown", nil)
+	rec = httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusNotFound)
+	}
+}
+
+func requestNextFire(t *testing.T) (int, NextFire) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/schedule/next", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	var next NextFire
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&next); err != nil {
+			t.Fatalf("unexpected error decoding the next fire : %s", err.Error())
+		}
+	}
+	return rec.Code, next
+}
+
+func TestReplyNextFire(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+
+	if code, _ := requestNextFire(t); code != http.StatusNoContent {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusNoContent)
+	}
+
+	addClockTestSchedule(t, "third", "20180101T000300", "PT1H")
+	soonest := addClockTestSchedule(t, "first", "20180101T000100", "PT1H")
+	second := addClockTestSchedule(t, "second", "20180101T000200", "PT1H")
+
+	code, next := requestNextFire(t)
+	if code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusOK)
+	}
+	if next.Schedule.Id.Hex() != soonest {
+		t.Errorf(TestUnexpectedMsgFormatStr, next.Schedule.Name, "first")
+	}
+	if next.UntilMs != 60000 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, next.UntilMs, 60000)
+	}
+
+	//a paused schedule does not fire next
+	scheduleIdToContextMap[soonest].Paused = true
+	if _, next := requestNextFire(t); next.Schedule.Id.Hex() != second {
+		t.Errorf(TestUnexpectedMsgFormatStr, next.Schedule.Name, "second")
+	}
+}
+
+func requestAllScheduleEvents(t *testing.T, target string) []ScheduleEventDetail {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+
+	var details []ScheduleEventDetail
+	if err := json.NewDecoder(rec.Body).Decode(&details); err != nil {
+		t.Fatalf("unexpected error decoding the schedule events : %s", err.Error())
+	}
+	return details
+}
+
+func TestReplyAllScheduleEvents(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+
+	morningId := addClockTestSchedule(t, "morning", "20180101T060000", "P1D")
+	eveningId := addClockTestSchedule(t, "evening", "20180101T180000", "P1D")
+	morning := scheduleIdToContextMap[morningId].Schedule
+	evening := scheduleIdToContextMap[eveningId].Schedule
+	for _, event := range []struct {
+		schedule models.Schedule
+		name     string
+		service  string
+	}{
+		{morning, "morning-scrub", "core-data"},
+		{morning, "morning-purge", "support-logging"},
+		{evening, "evening-scrub", "core-data"},
+	} {
+		scheduleEvent := addTestScheduleEvent(t, event.schedule, event.name, http.MethodDelete, "/api/v1/event/scrub", "")
+		scheduleEvent.Service = event.service
+		scheduleIdToContextMap[event.schedule.Id.Hex()].ScheduleEventsMap[scheduleEvent.Id.Hex()] = scheduleEvent
+	}
+
+	details := requestAllScheduleEvents(t, "/api/v1/scheduleevent")
+	expected := []string{"evening-scrub", "morning-purge", "morning-scrub"}
+	if len(details) != len(expected) {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(details), len(expected))
+	}
+	for i, detail := range details {
+		if detail.ScheduleEvent.Name != expected[i] {
+			t.Errorf(TestUnexpectedMsgFormatStr, detail.ScheduleEvent.Name, expected[i])
+		}
+	}
+	if details[0].Schedule != "evening" {
+		t.Errorf(TestUnexpectedMsgFormatStr, details[0].Schedule, "evening")
+	}
+	eveningNext := time.Date(2018, 1, 1, 18, 0, 0, 0, time.UTC).UnixNano() / int64(time.Millisecond)
+	if details[0].NextTime != eveningNext {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, details[0].NextTime, eveningNext)
+	}
+
+	filtered := requestAllScheduleEvents(t, "/api/v1/scheduleevent?service=core-data")
+	expected = []string{"evening-scrub", "morning-scrub"}
+	if len(filtered) != len(expected) {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(filtered), len(expected))
+	}
+	for i, detail := range filtered {
+		if detail.ScheduleEvent.Name != expected[i] || detail.ScheduleEvent.Service != "core-data" {
+			t.Errorf(TestUnexpectedMsgFormatStr, detail.ScheduleEvent.Name, expected[i])
+		}
+	}
+	if filtered[1].Schedule != "morning" {
+		t.Errorf(TestUnexpectedMsgFormatStr, filtered[1].Schedule, "morning")
+	}
+}
+
+func TestExecuteUsesInjectedClient(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodPost, "/api/v1/event/scrub", `{"age":1}`)
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	req := client.requests[0]
+	if req.Method != http.MethodPost {
+		t.Errorf(TestUnexpectedMsgFormatStr, req.Method, http.MethodPost)
+	}
+	if req.URL.String() != "http://localhost:48080/api/v1/event/scrub" {
+		t.Errorf(TestUnexpectedMsgFormatStr, req.URL.String(), "http://localhost:48080/api/v1/event/scrub")
+	}
+	if req.Header.Get(ContentTypeKey) != ContentTypeJsonValue {
+		t.Errorf(TestUnexpectedMsgFormatStr, req.Header.Get(ContentTypeKey), ContentTypeJsonValue)
+	}
+	if client.bodies[0] != `{"age":1}` {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.bodies[0], `{"age":1}`)
+	}
+}
+
+func TestValidMethod(t *testing.T) {
+	for _, method := range []string{"GET", "post", "Put", "PATCH", "options", "DELETE"} {
+		if !validMethod(method) {
+			t.Errorf("the method %s should be valid", method)
+		}
+	}
+	if validMethod("FETCH") {
+		t.Error("the method FETCH should be invalid")
+	}
+}
+
+func TestExecuteNormalizesMethod(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, "post", "/api/v1/event", `{"age":1}`)
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	if client.requests[0].Method != http.MethodPost {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.requests[0].Method, http.MethodPost)
+	}
+	if client.bodies[0] != `{"age":1}` {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.bodies[0], `{"age":1}`)
+	}
+}
+
+func TestExecutePatch(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, "patch", "/api/v1/event", "")
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	if client.requests[0].Method != http.MethodPatch {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.requests[0].Method, http.MethodPatch)
+	}
+}
+
+func TestExecuteDeleteWithBody(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodDelete, "/api/v1/event/removeold/age/1", `{"age":1}`)
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	if client.bodies[0] != `{"age":1}` {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.bodies[0], `{"age":1}`)
+	}
+}
+
+func TestExecuteGetWithoutBody(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", `{"age":1}`)
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	if client.bodies[0] != "" {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.bodies[0], "")
+	}
+}
+
+func TestExecuteGetWithQueryParameters(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/event?limit=10", "device=d1&age=5")
+	scheduleEvent.QueryParams = map[string]string{"tag": "{{.ScheduleName}}"}
+	scheduleIdToContextMap[schedule.Id.Hex()].ScheduleEventsMap[scheduleEvent.Id.Hex()] = scheduleEvent
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	requestUrl := client.requests[0].URL
+	if requestUrl.Path != "/api/v1/event" {
+		t.Errorf(TestUnexpectedMsgFormatStr, requestUrl.Path, "/api/v1/event")
+	}
+	expected := url.Values{"limit": {"10"}, "device": {"d1"}, "age": {"5"}, "tag": {TestScheduleName}}
+	if query := requestUrl.Query(); !reflect.DeepEqual(query, expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, query, expected)
+	}
+	if client.bodies[0] != "" {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.bodies[0], "")
+	}
+}
+
+func TestExecuteDeleteKeepsJsonBody(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodDelete, "/api/v1/event", `{"age":5}`)
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	if client.requests[0].URL.RawQuery != "" {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.requests[0].URL.RawQuery, "")
+	}
+	if client.bodies[0] != `{"age":5}` {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.bodies[0], `{"age":5}`)
+	}
+}
+
+func TestExecuteSkipsInvalidMethod(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	addTestScheduleEvent(t, schedule, TestScheduleEventUpdatingName, "FETCH", "/api/v1/ping", "")
+
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	nextTime := context.NextTime
+
+	if err := executeSchedule(schedule.Id.Hex()); err == nil {
+		t.Error("expected an error for the event with an invalid method")
+	}
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	if client.requests[0].Method != http.MethodGet {
+		t.Errorf(TestUnexpectedMsgFormatStr, client.requests[0].Method, http.MethodGet)
+	}
+	if !context.NextTime.After(nextTime) {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, "a later next time")
+	}
+}
+
+func TestExecuteSkipsMalformedUrl(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	scheduleEvent.Addressable.Address = "local host"
+	scheduleIdToContextMap[schedule.Id.Hex()].ScheduleEventsMap[scheduleEvent.Id.Hex()] = scheduleEvent
+
+	if err := executeSchedule(schedule.Id.Hex()); err == nil {
+		t.Error("expected an error for the event with a malformed url")
+	}
+
+	if len(client.requests) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 0)
+	}
+}
+
+func TestExecuteReturnsEachOutcome(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	first := addTestScheduleEvent(t, schedule, "first", http.MethodGet, "/api/v1/ping", "")
+	invalid := addTestScheduleEvent(t, schedule, "invalid", "FETCH", "/api/v1/ping", "")
+	last := addTestScheduleEvent(t, schedule, "last", http.MethodGet, "/api/v1/ping", "")
+
+	err := executeSchedule(schedule.Id.Hex())
+	executionErr, ok := err.(ErrExecution)
+	if !ok {
+		t.Fatalf(TestUnexpectedMsgFormatStr, err, "an ErrExecution")
+	}
+
+	if executionErr.ScheduleId != schedule.Id.Hex() {
+		t.Errorf(TestUnexpectedMsgFormatStr, executionErr.ScheduleId, schedule.Id.Hex())
+	}
+	if len(executionErr.Succeeded) != 2 || executionErr.Succeeded[0] != first.Id.Hex() || executionErr.Succeeded[1] != last.Id.Hex() {
+		t.Errorf(TestUnexpectedMsgFormatStr, executionErr.Succeeded, []string{first.Id.Hex(), last.Id.Hex()})
+	}
+	if len(executionErr.Failures) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(executionErr.Failures), 1)
+	}
+	if executionErr.Failures[0].ScheduleEventId != invalid.Id.Hex() || executionErr.Failures[0].Err == nil {
+		t.Errorf(TestUnexpectedMsgFormatStr, executionErr.Failures[0], "the failure of the invalid event")
+	}
+	if !strings.HasPrefix(err.Error(), "1 of 3 events") {
+		t.Errorf(TestUnexpectedMsgFormatStr, err.Error(), "a summary of the failures")
+	}
+}
+
+func TestExecuteFailsOnUnexpectedResponse(t *testing.T) {
+	resetScheduler()
+	clearLastRuns()
+	client := &mockHTTPClient{response: `{"status":"error"}`}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	scheduleEvent.ExpectResponse = `"status":\s*"ok"`
+	scheduleIdToContextMap[schedule.Id.Hex()].ScheduleEventsMap[scheduleEvent.Id.Hex()] = scheduleEvent
+
+	err := executeSchedule(schedule.Id.Hex())
+	executionErr, ok := err.(ErrExecution)
+	if !ok || len(executionErr.Failures) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStr, err, "a failed execution")
+	}
+	if _, ok := executionErr.Failures[0].Err.(ErrUnexpectedResponse); !ok {
+		t.Errorf(TestUnexpectedMsgFormatStr, executionErr.Failures[0].Err, "an ErrUnexpectedResponse")
+	}
+	lastRun, _ := queryLastRun(scheduleEvent.Id.Hex())
+	if lastRun.StatusCode != http.StatusOK || lastRun.Error == "" {
+		t.Errorf("expected a failed run with status code 200, got %v", lastRun)
+	}
+
+	//a matching body succeeds
+	client.response = `{"status": "ok"}`
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Errorf("unexpected error : %s", err.Error())
+	}
+}
+
+func TestValidateScheduleEventRejectsInvalidExpectResponse(t *testing.T) {
+	scheduleEvent := models.ScheduleEvent{
+		Name:           TestScheduleEventName,
+		Schedule:       TestScheduleName,
+		ExpectResponse: "([a-z",
+		Addressable: models.Addressable{
+			Protocol:   "http",
+			HTTPMethod: http.MethodGet,
+			Address:    "localhost",
+			Port:       48080,
+		},
+	}
+	if err := validateScheduleEvent(scheduleEvent); err == nil {
+		t.Error("expected an error for an invalid expected response")
+	}
+}
+
+func TestTriggerScheduleLogsFailures(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, "FETCH", "/api/v1/ping", "")
+	scheduleIdToContextMap[schedule.Id.Hex()].NextTime = time.Now().Add(-time.Second)
+
+	logs := &captureLogger{}
+	LoggingClient = logs
+	defer func() { LoggingClient = logger.NewMockClient() }()
+
+	triggerSchedule()
+
+	waitForExecutions()
+
+	found := false
+	for _, msg := range logs.messages {
+		if strings.Contains(msg, "had failures, 1 of 1 events") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a summary of the failures, got %v", logs.messages)
+	}
+}
+
+func TestPauseAndResumeSchedule(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	context.NextTime = time.Now().Add(-time.Minute)
+	nextTime := context.NextTime
+
+	if err := pauseSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error pausing the schedule : %s", err.Error())
+	}
+
+	triggerSchedule()
+
+	waitForExecutions()
+
+	if len(client.requests) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 0)
+	}
+	if context.NextTime != nextTime {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, nextTime)
+	}
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+
+	if err := resumeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error resuming the schedule : %s", err.Error())
+	}
+	if context.Paused {
+		t.Errorf(TestUnexpectedMsgFormatStrForBoolVal, context.Paused, false)
+	}
+	if !context.NextTime.After(time.Now()) {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, "a next time after now")
+	}
+
+	context.NextTime = time.Now().Add(-time.Second)
+	triggerSchedule()
+	waitForExecutions()
+
+	if len(client.requests) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+}
+
+func TestPauseUnknownSchedule(t *testing.T) {
+	resetScheduler()
+
+	if err := pauseSchedule(bson.NewObjectId().Hex()); err == nil {
+		t.Error("expected an error pausing an unknown schedule")
+	}
+	if err := resumeSchedule(bson.NewObjectId().Hex()); err == nil {
+		t.Error("expected an error resuming an unknown schedule")
+	}
+}
+
+// captureLogger keeps the messages it receives so tests can inspect them
+type captureLogger struct {
+	mutex    sync.Mutex
+	messages []string
+}
+
+func (l *captureLogger) capture(msg string) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.messages = append(l.messages, msg)
+	return nil
+}
+
+func (l *captureLogger) SetLogLevel(logLevel string) error        { return nil }
+func (l *captureLogger) Debug(msg string, labels ...string) error { return l.capture(msg) }
+func (l *captureLogger) Error(msg string, labels ...string) error { return l.capture(msg) }
+func (l *captureLogger) Info(msg string, labels ...string) error  { return l.capture(msg) }
+func (l *captureLogger) Trace(msg string, labels ...string) error { return l.capture(msg) }
+func (l *captureLogger) Warn(msg string, labels ...string) error  { return l.capture(msg) }
+
+func TestExecuteCorrelationId(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	logs := &captureLogger{}
+	LoggingClient = logs
+	defer func() { LoggingClient = logger.NewMockClient() }()
+
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	correlationId := client.requests[0].Header.Get(CorrelationHeader)
+	if correlationId == "" {
+		t.Fatal("expected a correlation id on the outbound request")
+	}
+
+	if len(logs.messages) == 0 {
+		t.Fatal("expected log output from the execution")
+	}
+	for _, msg := range logs.messages {
+		if !strings.Contains(msg, correlationId) {
+			t.Errorf("log line %q does not contain the correlation id %s", msg, correlationId)
+		}
+	}
+
+	//every execution gets its own correlation id
+	executeSchedule(schedule.Id.Hex())
+	if client.requests[1].Header.Get(CorrelationHeader) == correlationId {
+		t.Error("expected a new correlation id for the next execution")
+	}
+}
+
+// countingHTTPClient tracks how many requests are in flight at the same time
+type countingHTTPClient struct {
+	mutex       sync.Mutex
+	inFlight    int
+	maxInFlight int
+	calls       int
+	delay       time.Duration
+}
+
+func (c *countingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.mutex.Lock()
+	c.calls++
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mutex.Unlock()
+
+	time.Sleep(c.delay)
+
+	c.mutex.Lock()
+	c.inFlight--
+	c.mutex.Unlock()
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestTriggerScheduleLimitsConcurrentExecutions(t *testing.T) {
+	resetScheduler()
+	client := &countingHTTPClient{delay: 20 * time.Millisecond}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.MaxConcurrentExecutions = 3
+	defer func() { Configuration.MaxConcurrentExecutions = 0 }()
+
+	const schedules = 12
+	for i := 0; i < schedules; i++ {
+		name := fmt.Sprintf("%s-%d", TestScheduleName, i)
+		schedule := addTestSchedule(t, name)
+		addTestScheduleEvent(t, schedule, name, http.MethodGet, "/api/v1/ping", "")
+		scheduleIdToContextMap[schedule.Id.Hex()].NextTime = time.Now().Add(-time.Second)
+	}
+
+	triggerSchedule()
+
+	waitForExecutions()
+
+	if client.calls != schedules {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, client.calls, schedules)
+	}
+	if client.maxInFlight > 3 {
+		t.Errorf("%d executions were in flight at the same time, the limit is %d", client.maxInFlight, 3)
+	}
+	if scheduleQueue.Length() != schedules {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), schedules)
+	}
+}
+
+// hangingHTTPClient blocks the requests to the hanging path until it is released
+type hangingHTTPClient struct {
+	mutex   sync.Mutex
+	calls   map[string]int
+	path    string
+	release chan struct{}
+}
+
+func (c *hangingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.mutex.Lock()
+	c.calls[req.URL.Path]++
+	c.mutex.Unlock()
+
+	if req.URL.Path == c.path {
+		<-c.release
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Header:     make(http.Header),
+	}, nil
+}
+
+// wait for the schedule to have executed the given number of times
+func waitForIterations(t *testing.T, scheduleId string, iterations int64) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mutex.Lock()
+		current := scheduleIdToContextMap[scheduleId].CurrentIterations
+		mutex.Unlock()
+		if current >= iterations {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("the schedule %s did not reach %d iterations in time", scheduleId, iterations)
+}
+
+func TestHangingExecutionDoesNotHoldUpTicks(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	client := &hangingHTTPClient{calls: make(map[string]int), path: "/api/v1/hang", release: make(chan struct{})}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	hanging := addTestSchedule(t, "hanging")
+	addTestScheduleEvent(t, hanging, "hanging", http.MethodGet, "/api/v1/hang", "")
+	fast := addTestSchedule(t, "fast")
+	addTestScheduleEvent(t, fast, "fast", http.MethodGet, "/api/v1/ping", "")
+
+	for day := int64(1); day <= 3; day++ {
+		fake.Advance(24 * time.Hour)
+
+		start := time.Now()
+		triggerSchedule()
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("the tick took %s while an execution was hanging", elapsed)
+		}
+		waitForIterations(t, fast.Id.Hex(), day)
+	}
+
+	client.mutex.Lock()
+	if client.calls["/api/v1/ping"] != 3 || client.calls["/api/v1/hang"] != 1 {
+		t.Errorf("unexpected requests %v", client.calls)
+	}
+	client.mutex.Unlock()
+
+	close(client.release)
+	waitForExecutions()
+	if iterations := scheduleIdToContextMap[hanging.Id.Hex()].CurrentIterations; iterations != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, iterations, 1)
+	}
+}
+
+func TestAddScheduleEventUnknownSchedule(t *testing.T) {
+	resetScheduler()
+
+	scheduleEvent := models.ScheduleEvent{
+		Id:       bson.NewObjectId(),
+		Name:     TestScheduleEventName,
+		Schedule: "missing",
+	}
+	err := addScheduleEvent(scheduleEvent)
+	if err == nil {
+		t.Fatal("expected an error adding an event for an unknown schedule")
+	}
+	expected := `schedule "missing" not found for event "pushed events"`
+	if err.Error() != expected {
+		t.Errorf(TestUnexpectedMsgFormatStr, err.Error(), expected)
+	}
+	if _, exists := scheduleEventIdToScheduleIdMap[scheduleEvent.Id.Hex()]; exists {
+		t.Error("the event for the unknown schedule should not be registered")
+	}
+}
+
+func TestAddScheduleRejectsDuplicateName(t *testing.T) {
+	resetScheduler()
+
+	first := addTestSchedule(t, TestScheduleName)
+	second := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      TestScheduleName,
+		Start:     "20180101T000000",
+		Frequency: "PT1H",
+	}
+	err := addSchedule(second)
+	if _, ok := err.(ErrNameConflict); !ok {
+		t.Fatalf("expected a name conflict adding a second schedule with the same name, got %v", err)
+	}
+	if _, exists := scheduleIdToContextMap[second.Id.Hex()]; exists {
+		t.Error("the second schedule should not be registered")
+	}
+	context, exists := scheduleNameToContextMap[TestScheduleName]
+	if !exists || context.Schedule.Id != first.Id {
+		t.Fatal("the name should still map to the first schedule")
+	}
+	if context.Schedule.Frequency != first.Frequency {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.Schedule.Frequency, first.Frequency)
+	}
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+}
+
+func TestAddScheduleEventRejectsDuplicateName(t *testing.T) {
+	resetScheduler()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	other := addTestSchedule(t, "other")
+	first := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	second := first
+	second.Id = bson.NewObjectId()
+	second.Schedule = other.Name
+	err := addScheduleEvent(second)
+	if _, ok := err.(ErrNameConflict); !ok {
+		t.Fatalf("expected a name conflict adding a second schedule event with the same name, got %v", err)
+	}
+	if _, exists := scheduleEventIdToScheduleIdMap[second.Id.Hex()]; exists {
+		t.Error("the second schedule event should not be registered")
+	}
+	if len(scheduleIdToContextMap[other.Id.Hex()].ScheduleEventsMap) != 0 {
+		t.Error("the second schedule event should not be added to its schedule")
+	}
+	if id := scheduleEventNameToScheduleEventIdMap[TestScheduleEventName]; id != first.Id.Hex() {
+		t.Errorf(TestUnexpectedMsgFormatStr, id, first.Id.Hex())
+	}
+	if id := scheduleEventNameToScheduleIdMap[TestScheduleEventName]; id != schedule.Id.Hex() {
+		t.Errorf(TestUnexpectedMsgFormatStr, id, schedule.Id.Hex())
+	}
+
+	//adding the same event again is not a conflict
+	if err := addScheduleEvent(first); err != nil {
+		t.Errorf("unexpected error adding the schedule event again : %s", err.Error())
+	}
+}
+
+func TestLoadConfigScheduleEventsSkipsUnknownSchedule(t *testing.T) {
+	resetScheduler()
+	Configuration.ScheduleEvents = map[string]config.ScheduleEventInfo{
+		"Orphan": {Name: "orphan-event", Schedule: "missing", Host: "localhost", Port: 48080, Protocol: "http", Method: http.MethodGet, Path: "/api/v1/ping"},
+	}
+	defer func() { Configuration.ScheduleEvents = nil }()
+
+	if err := loadConfigScheduleEvents(); err != nil {
+		t.Fatalf("unexpected error loading the schedule events : %s", err.Error())
+	}
+	if _, exists := scheduleEventNameToScheduleIdMap["orphan-event"]; exists {
+		t.Error("the event for the unknown schedule should not be loaded")
+	}
+}
+
+func TestAddScheduleEventOperationMissingContext(t *testing.T) {
+	resetScheduler()
+
+	schedule := models.Schedule{Id: bson.NewObjectId(), Name: TestScheduleName}
+	scheduleEvent := models.ScheduleEvent{Id: bson.NewObjectId(), Name: TestScheduleEventName, Schedule: schedule.Name}
+	if err := addScheduleEventOperation(schedule, scheduleEvent); err == nil {
+		t.Error("expected an error adding an event to a missing schedule context")
+	}
+}
+
+func TestRemoveScheduleWhileAddingEvents(t *testing.T) {
+	resetScheduler()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+
+	var wg sync.WaitGroup
+	panics := make(chan interface{}, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					panics <- r
+				}
+			}()
+
+			if i%2 == 0 {
+				removeSchedule(schedule.Id.Hex())
+				return
+			}
+			addScheduleEvent(models.ScheduleEvent{
+				Id:       bson.NewObjectId(),
+				Name:     fmt.Sprintf("%s-%d", TestScheduleEventName, i),
+				Schedule: schedule.Name,
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(panics)
+
+	for r := range panics {
+		t.Errorf("unexpected panic : %v", r)
+	}
+}
+
+func TestSendRequestTruncatesLargeResponse(t *testing.T) {
+	Configuration.MaxResponseBytes = 1024
+	defer func() { Configuration.MaxResponseBytes = 0 }()
+
+	chunk := []byte(strings.Repeat("x", 64*1024))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		for i := 0; i < 128; i++ {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+		}
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	body, statusCode, err := sendRequestAndGetResponse(&http.Client{}, req)
+	if err != nil {
+		t.Fatalf("unexpected error sending the request : %s", err.Error())
+	}
+	if statusCode != http.StatusAccepted {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusAccepted)
+	}
+	if len(body) != 1024 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(body), 1024)
+	}
+}
+
+func TestSendRequestKeepsResponseWithinLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("pong"))
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	body, _, err := sendRequestAndGetResponse(&http.Client{}, req)
+	if err != nil {
+		t.Fatalf("unexpected error sending the request : %s", err.Error())
+	}
+	if string(body) != "pong" {
+		t.Errorf(TestUnexpectedMsgFormatStr, string(body), "pong")
+	}
+}
+
+func TestSendRequestTransportError(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:48080/api/v1/ping", nil)
+	_, statusCode, err := sendRequestAndGetResponse(&failingHTTPClient{}, req)
+
+	if _, ok := err.(ErrTransport); !ok {
+		t.Fatalf("expected a transport error, got %v", err)
+	}
+	if statusCode != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, 0)
+	}
+	if !isRetryable(err) {
+		t.Error("a transport error should be retryable")
+	}
+}
+
+func TestSendRequestServerError(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "http://localhost:48080/api/v1/ping", nil)
+	_, statusCode, err := sendRequestAndGetResponse(&mockHTTPClient{statusCode: http.StatusInternalServerError}, req)
+
+	serverErr, ok := err.(ErrServerResponse)
+	if !ok {
+		t.Fatalf("expected a server response error, got %v", err)
+	}
+	if statusCode != http.StatusInternalServerError || serverErr.StatusCode != http.StatusInternalServerError {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusInternalServerError)
+	}
+	if isRetryable(err) {
+		t.Error("a server error should not be retried unless RetryServerErrors is set")
+	}
+
+	Configuration.RetryServerErrors = true
+	defer func() { Configuration.RetryServerErrors = false }()
+	if !isRetryable(err) {
+		t.Error("a server error should be retried when RetryServerErrors is set")
+	}
+}
+
+func TestExecuteDoesNotRetryServerError(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{statusCode: http.StatusServiceUnavailable}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	Configuration.MaxRetries = 2
+	Configuration.RetryBackoff = 1
+	defer func() {
+		Configuration.MaxRetries = 0
+		Configuration.RetryBackoff = 0
+	}()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	if err := executeSchedule(schedule.Id.Hex()); err == nil {
+		t.Error("expected the server error to fail the execution")
+	}
+	if len(client.requests) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+}
+
+func TestExecuteDryRun(t *testing.T) {
+	resetScheduler()
+	Configuration.DryRun = true
+	defer func() { Configuration.DryRun = false }()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer server.Close()
+	serverUrl, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(serverUrl.Port())
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := models.ScheduleEvent{
+		Id:         bson.NewObjectId(),
+		Name:       TestScheduleEventName,
+		Schedule:   schedule.Name,
+		Parameters: `{"age":1}`,
+		Addressable: models.Addressable{
+			Protocol:   "http",
+			HTTPMethod: http.MethodPost,
+			Address:    serverUrl.Hostname(),
+			Port:       port,
+			Path:       "/api/v1/event/scrub",
+		},
+	}
+	if err := addScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+	}
+
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	nextTime := context.NextTime
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	if atomic.LoadInt32(&requests) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, requests, 0)
+	}
+	if context.CurrentIterations != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.CurrentIterations, 1)
+	}
+	if !context.NextTime.After(nextTime) {
+		t.Errorf("the next time %s should move past %s", context.NextTime, nextTime)
+	}
+}
+
+func TestTriggerScheduleSkipsExecutingSchedule(t *testing.T) {
+	resetScheduler()
+	client := &countingHTTPClient{delay: 100 * time.Millisecond}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	context.NextTime = time.Now().Add(-time.Second)
+
+	//the schedule is queued again, e.g. by an update, while its first execution is still running
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		triggerSchedule()
+		waitForExecutions()
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	mutex.Lock()
+	executing := context.Executing
+	context.NextTime = time.Now().Add(-time.Second)
+	scheduleQueue.Add(context)
+	mutex.Unlock()
+	triggerSchedule()
+	waitForExecutions()
+	wg.Wait()
+
+	if !executing {
+		t.Error("the schedule should be flagged as executing while its events run")
+	}
+	if client.maxInFlight != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, client.maxInFlight, 1)
+	}
+	if client.calls != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, client.calls, 1)
+	}
+	if context.Executing {
+		t.Error("the schedule should no longer be flagged as executing")
+	}
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+}
+
+// panickingHTTPClient panics on every request
+type panickingHTTPClient struct{}
+
+func (c panickingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	panic("client failure")
+}
+
+func TestPanickingExecutionRequeuesTheSchedule(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	SetHTTPClient(panickingHTTPClient{})
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	nextTime := context.NextTime
+
+	fake.Advance(24 * time.Hour)
+	triggerSchedule()
+	waitForExecutions()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if context.Executing {
+		t.Error("the schedule should no longer be flagged as executing after the panic")
+	}
+	if !context.NextTime.After(nextTime) {
+		t.Errorf("expected the schedule to move past %s, got %s", nextTime, context.NextTime)
+	}
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+}
+
+func TestReleasePanickedExecution(t *testing.T) {
+	resetScheduler()
+	_, restore := useFakeClock(time.Date(2018, 1, 2, 0, 0, 30, 0, time.UTC))
+	defer restore()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	mutex.Lock()
+	scheduleQueue.Remove()
+	context.Executing = true
+	mutex.Unlock()
+
+	releasePanickedExecution(context)
+	if context.Executing || scheduleQueue.Length() != 1 {
+		t.Errorf("expected the schedule to be released and requeued, executing : %t, queue length : %d", context.Executing, scheduleQueue.Length())
+	}
+
+	//an execution which released its schedule before panicking has requeued it already
+	releasePanickedExecution(context)
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+}
+
+func TestExecuteRunsEventsInOrder(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	events := []struct {
+		name  string
+		order int
+	}{
+		{"unordered-b", 0},
+		{"third", 3},
+		{"unordered-a", 0},
+		{"first", 1},
+		{"second", 2},
+	}
+	for _, e := range events {
+		scheduleEvent := models.ScheduleEvent{
+			Id:       bson.NewObjectId(),
+			Name:     e.name,
+			Schedule: schedule.Name,
+			Order:    e.order,
+			Addressable: models.Addressable{
+				Name:       e.name,
+				Protocol:   "http",
+				HTTPMethod: http.MethodGet,
+				Address:    "localhost",
+				Port:       48080,
+				Path:       "/" + e.name,
+			},
+		}
+		if err := addScheduleEvent(scheduleEvent); err != nil {
+			t.Fatalf("unexpected error adding the schedule event : %s", err.Error())
+		}
+	}
+
+	executeSchedule(schedule.Id.Hex())
+
+	expected := []string{"/first", "/second", "/third", "/unordered-a", "/unordered-b"}
+	if len(client.requests) != len(expected) {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), len(expected))
+	}
+	for i, path := range expected {
+		if client.requests[i].URL.Path != path {
+			t.Errorf(TestUnexpectedMsgFormatStr, client.requests[i].URL.Path, path)
+		}
+	}
+}
+
+func TestAddScheduleRejectsZeroFrequency(t *testing.T) {
+	resetScheduler()
+	logs := &captureLogger{}
+	LoggingClient = logs
+	defer func() { LoggingClient = logger.NewMockClient() }()
+
+	schedule := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      TestScheduleName,
+		Start:     "20180101T000000",
+		Frequency: "PT0S",
+	}
+	if err := addSchedule(schedule); err == nil {
+		t.Fatal("expected an error for a zero frequency")
+	}
+
+	if scheduleQueue.Length() != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 0)
+	}
+	if _, exists := scheduleIdToContextMap[schedule.Id.Hex()]; exists {
+		t.Error("the schedule should not have been added")
+	}
+	logged := false
+	for _, msg := range logs.messages {
+		if strings.Contains(msg, "invalid frequency") {
+			logged = true
+		}
+	}
+	if !logged {
+		t.Error("expected the invalid frequency to be logged")
+	}
+}
+
+func TestAddScheduleRejectedWhenQueueIsFull(t *testing.T) {
+	resetScheduler()
+	Configuration.MaxQueueDepth = 3
+	defer func() { Configuration.MaxQueueDepth = 0 }()
+
+	for i := 0; i < 3; i++ {
+		addTestSchedule(t, fmt.Sprintf("%s-%d", TestScheduleName, i))
+	}
+
+	schedule := models.Schedule{
+		Id:        bson.NewObjectId(),
+		Name:      TestScheduleName,
+		Start:     "20180101T000000",
+		Frequency: "P1D",
+	}
+	err := addSchedule(schedule)
+	if _, ok := err.(ErrQueueFull); !ok {
+		t.Fatalf(TestUnexpectedMsgFormatStr, err, ErrQueueFull{})
+	}
+	if scheduleQueue.Length() != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 3)
+	}
+	if _, exists := scheduleIdToContextMap[schedule.Id.Hex()]; exists {
+		t.Error("the rejected schedule should not have been added")
+	}
+
+	status := checkHealth()
+	if status.QueueLength != 3 || status.MaxQueueDepth != 3 {
+		t.Errorf("unexpected queue depth in the health status : %+v", status)
+	}
+
+	//events of the queued schedules are still accepted
+	addTestScheduleEvent(t, scheduleNameToContextMap[TestScheduleName+"-0"].Schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+}
+
+func TestRemoveScheduleAndEventsEndpoints(t *testing.T) {
+	resetScheduler()
+	schedule := addTestSchedule(t, TestScheduleName)
+	first := addTestScheduleEvent(t, schedule, "first", http.MethodGet, "/first", "")
+	addTestScheduleEvent(t, schedule, "second", http.MethodGet, "/second", "")
+	addTestScheduleEvent(t, schedule, "third", http.MethodGet, "/third", "")
+
+	remove := func(path string) int {
+		req := httptest.NewRequest(http.MethodDelete, path, nil)
+		rec := httptest.NewRecorder()
+		LoadRestRoutes().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := remove("/api/v1/scheduleevent/" + first.Id.Hex()); code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusOK)
+	}
+	if _, err := queryScheduleEventByName("first"); err == nil {
+		t.Error("the removed schedule event should not be found by name")
+	}
+
+	if code := remove("/api/v1/schedule/" + schedule.Id.Hex()); code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusOK)
+	}
+	if len(scheduleIdToContextMap) != 0 || len(scheduleNameToContextMap) != 0 {
+		t.Errorf("dangling schedule contexts : %v %v", scheduleIdToContextMap, scheduleNameToContextMap)
+	}
+	if len(scheduleEventIdToScheduleIdMap) != 0 {
+		t.Errorf("dangling schedule event ids : %v", scheduleEventIdToScheduleIdMap)
+	}
+	if len(scheduleEventNameToScheduleIdMap) != 0 || len(scheduleEventNameToScheduleEventIdMap) != 0 {
+		t.Errorf("dangling schedule event names : %v %v", scheduleEventNameToScheduleIdMap, scheduleEventNameToScheduleEventIdMap)
+	}
+
+	if code := remove("/api/v1/schedule/" + schedule.Id.Hex()); code != http.StatusNotFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusNotFound)
+	}
+	if code := remove("/api/v1/scheduleevent/" + first.Id.Hex()); code != http.StatusNotFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, code, http.StatusNotFound)
+	}
+}
+
+func TestRemoveScheduleCleansEventMappings(t *testing.T) {
+	resetScheduler()
+	SetHTTPClient(&mockHTTPClient{})
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, "first", http.MethodGet, "/first", "")
+	addTestScheduleEvent(t, schedule, "second", http.MethodGet, "/second", "")
+	executeSchedule(schedule.Id.Hex())
+
+	if err := removeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	if len(scheduleEventNameToScheduleIdMap) != 0 {
+		t.Errorf("stale schedule ids by event name : %v", scheduleEventNameToScheduleIdMap)
+	}
+	if len(scheduleEventNameToScheduleEventIdMap) != 0 {
+		t.Errorf("stale schedule event ids by event name : %v", scheduleEventNameToScheduleEventIdMap)
+	}
+	if len(scheduleNameToContextMap) != 0 {
+		t.Errorf("stale schedule contexts by name : %v", scheduleNameToContextMap)
+	}
+	if len(scheduleEventIdToLastRunMap) != 0 {
+		t.Errorf("stale last runs : %v", scheduleEventIdToLastRunMap)
+	}
+	for _, name := range []string{"first", "second"} {
+		if _, err := queryScheduleEventByName(name); err == nil {
+			t.Errorf("the schedule event %s of the removed schedule should not be found", name)
+		}
+	}
+
+	//the names can be used again by a new schedule
+	schedule = addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, "first", http.MethodGet, "/first", "")
+	found, err := queryScheduleEventByName("first")
+	if err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if found.Id != scheduleEvent.Id {
+		t.Errorf(TestUnexpectedMsgFormatStr, found.Id.Hex(), scheduleEvent.Id.Hex())
+	}
+}
+
+func TestLoadConfigScheduleEventsRejectsInvalidAddressable(t *testing.T) {
+	tests := []struct {
+		name     string
+		port     int
+		protocol string
+		expected string
+	}{
+		{"zero-port", 0, "http", "invalid port 0"},
+		{"large-port", 70000, "http", "invalid port 70000"},
+		{"unknown-protocol", 48080, "gopher", `unsupported protocol "gopher"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetScheduler()
+			addTestSchedule(t, TestScheduleName)
+			logs := &captureLogger{}
+			LoggingClient = logs
+			defer func() { LoggingClient = logger.NewMockClient() }()
+
+			Configuration.ScheduleEvents = map[string]config.ScheduleEventInfo{
+				"Invalid": {Name: tt.name, Schedule: TestScheduleName, Host: "localhost", Port: tt.port, Protocol: tt.protocol, Method: http.MethodGet, Path: "/api/v1/ping"},
+			}
+			defer func() { Configuration.ScheduleEvents = nil }()
+
+			if err := loadConfigScheduleEvents(); err != nil {
+				t.Fatalf("unexpected error loading the schedule events : %s", err.Error())
+			}
+			if _, exists := scheduleEventNameToScheduleIdMap[tt.name]; exists {
+				t.Error("the invalid event should not be loaded")
+			}
+			logged := false
+			for _, msg := range logs.messages {
+				if strings.Contains(msg, strconv.Quote(tt.name)) && strings.Contains(msg, tt.expected) {
+					logged = true
+				}
+			}
+			if !logged {
+				t.Errorf("expected an error naming the event %s and containing %q, got %v", tt.name, tt.expected, logs.messages)
+			}
+		})
+	}
+}
+
+func TestValidateAddressable(t *testing.T) {
+	for _, protocol := range []string{"http", "HTTPS", " mqtt "} {
+		scheduleEvent := models.ScheduleEvent{Name: TestScheduleEventName, Addressable: models.Addressable{Protocol: protocol, Address: "localhost", Port: 65535}}
+		if err := validateAddressable(scheduleEvent); err != nil {
+			t.Errorf("unexpected error for the protocol %q : %s", protocol, err.Error())
+		}
+	}
+}
+
+func TestBuildEventUrl(t *testing.T) {
+	tests := []struct {
+		name        string
+		addressable models.Addressable
+		expected    string
+	}{
+		{"leading slash", models.Addressable{Protocol: "HTTP", Address: "core-data", Port: 48080, Path: "/api/v1/ping"}, "http://core-data:48080/api/v1/ping"},
+		{"no leading slash", models.Addressable{Protocol: "http", Address: "core-data", Port: 48080, Path: "api/v1/ping"}, "http://core-data:48080/api/v1/ping"},
+		{"double slash", models.Addressable{Protocol: "http", Address: "core-data", Port: 48080, Path: "//api/v1/ping"}, "http://core-data:48080/api/v1/ping"},
+		{"no path", models.Addressable{Protocol: "https", Address: "core-data", Port: 48443}, "https://core-data:48443"},
+		{"query", models.Addressable{Protocol: "http", Address: "core-data", Port: 48080, Path: "/api/v1/event?limit=10"}, "http://core-data:48080/api/v1/event?limit=10"},
+		{"ipv6", models.Addressable{Protocol: "http", Address: "::1", Port: 48080, Path: "/api/v1/ping"}, "http://[::1]:48080/api/v1/ping"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventUrl, err := buildEventUrl(tt.addressable)
+			if err != nil {
+				t.Fatalf("unexpected error building the url : %s", err.Error())
+			}
+			if eventUrl != tt.expected {
+				t.Errorf(TestUnexpectedMsgFormatStr, eventUrl, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildEventUrlRejectsInvalidAddressables(t *testing.T) {
+	invalid := map[string]models.Addressable{
+		"no scheme":           {Address: "core-data", Port: 48080, Path: "/api/v1/ping"},
+		"scheme in address":   {Protocol: "http", Address: "http://core-data", Port: 48080, Path: "/api/v1/ping"},
+		"no address":          {Protocol: "http", Port: 48080, Path: "/api/v1/ping"},
+		"invalid path escape": {Protocol: "http", Address: "core-data", Port: 48080, Path: "/api/%zz"},
+	}
+	for name, addressable := range invalid {
+		if eventUrl, err := buildEventUrl(addressable); err == nil {
+			t.Errorf("expected an error building the url of the addressable with %s, got %s", name, eventUrl)
+		}
+	}
+
+	//the invalid url is reported when the event is loaded and when it executes
+	scheduleEvent := models.ScheduleEvent{Id: bson.NewObjectId(), Name: TestScheduleEventName,
+		Addressable: models.Addressable{Protocol: "http", Address: "http://core-data", Port: 48080, HTTPMethod: http.MethodGet}}
+	if err := validateAddressable(scheduleEvent); err == nil || !strings.Contains(err.Error(), "invalid url") {
+		t.Errorf("expected the invalid url to fail the validation, got %v", err)
+	}
+	if _, err := executeScheduleEvent(context.Background(), scheduleEvent, ""); err == nil {
+		t.Error("expected the invalid url to fail the execution")
+	}
+}
+
+func TestExecuteSetsUserAgent(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	executeSchedule(schedule.Id.Hex())
+
+	Configuration.UserAgent = "custom-agent/2.0"
+	defer func() { Configuration.UserAgent = "" }()
+	executeSchedule(schedule.Id.Hex())
+
+	if len(client.requests) != 2 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 2)
+	}
+	if agent := client.requests[0].Header.Get(UserAgentKey); agent != "edgex-scheduler/"+edgex.Version {
+		t.Errorf(TestUnexpectedMsgFormatStr, agent, "edgex-scheduler/"+edgex.Version)
+	}
+	if agent := client.requests[1].Header.Get(UserAgentKey); agent != "custom-agent/2.0" {
+		t.Errorf(TestUnexpectedMsgFormatStr, agent, "custom-agent/2.0")
+	}
+}
+
+func TestAddScheduleEventEndpoint(t *testing.T) {
+	resetScheduler()
+	scheduleEventClient := &fakeScheduleEventClient{}
+	addressableClient := &fakeAddressableClient{}
+	msec = scheduleEventClient
+	mac = addressableClient
+	defer func() {
+		msec = nil
+		mac = nil
+	}()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/scheduleevent", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		LoadRestRoutes().ServeHTTP(rec, req)
+		return rec
+	}
+	body := func(name string, scheduleName string) string {
+		return fmt.Sprintf(`{"name":%q,"schedule":%q,"addressable":{"name":"schedule-%s","protocol":"http","method":"GET","address":"localhost","port":48080,"path":"/api/v1/ping"}}`, name, scheduleName, name)
+	}
+
+	rec := post(body(TestScheduleEventName, schedule.Name))
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	id := rec.Body.String()
+
+	if len(scheduleEventClient.scheduleEvents) != 1 || scheduleEventClient.scheduleEvents[0].Id.Hex() != id {
+		t.Errorf("the schedule event was not stored in core-metadata : %v", scheduleEventClient.scheduleEvents)
+	}
+	if len(addressableClient.addressables) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(addressableClient.addressables), 1)
+	}
+	scheduleEvent, err := queryScheduleEvent(id)
+	if err != nil {
+		t.Fatalf("the schedule event was not scheduled : %s", err.Error())
+	}
+	if scheduleEvent.Name != TestScheduleEventName {
+		t.Errorf(TestUnexpectedMsgFormatStr, scheduleEvent.Name, TestScheduleEventName)
+	}
+	if scheduleQueue.Length() != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 1)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		expected int
+	}{
+		{"duplicate", body(TestScheduleEventName, schedule.Name), http.StatusConflict},
+		{"unknown schedule", body("other", "missing"), http.StatusNotFound},
+		{"invalid", `{"name":"other","schedule":"midnight-1","addressable":{"protocol":"http","method":"GET","port":0}}`, http.StatusBadRequest},
+		{"malformed", `{"name":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if rec := post(tt.body); rec.Code != tt.expected {
+			t.Errorf("%s : "+TestUnexpectedMsgFormatStrForIntVal, tt.name, rec.Code, tt.expected)
+		}
+	}
+	if len(scheduleEventClient.scheduleEvents) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(scheduleEventClient.scheduleEvents), 1)
+	}
+}
+
+func TestOneOffSchedule(t *testing.T) {
+	resetScheduler()
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake, restore := useFakeClock(now)
+	defer restore()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	fireTime := now.Add(5 * time.Minute)
+	schedule := models.Schedule{
+		Id:    bson.NewObjectId(),
+		Name:  TestScheduleName,
+		Start: fireTime.Format(TIMELAYOUT),
+	}
+	if err := addSchedule(schedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	if !context.Schedule.RunOnce || !context.NextTime.Equal(fireTime) {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, fireTime)
+	}
+
+	var fires []time.Time
+	for i := 0; i < 10; i++ {
+		fake.Advance(time.Minute)
+		before := len(client.requests)
+		triggerSchedule()
+		waitForExecutions()
+		if len(client.requests) > before {
+			fires = append(fires, fake.Now())
+		}
+	}
+
+	if len(fires) != 1 || !fires[0].Equal(fireTime) {
+		t.Errorf(TestUnexpectedMsgFormatStr, fires, []time.Time{fireTime})
+	}
+	if !context.IsComplete() {
+		t.Error("expected the one-off schedule to be complete")
+	}
+	if scheduleQueue.Length() != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 0)
+	}
+}
+
+// slowHTTPClient answers every request after the delay, unless the request is cancelled first
+type slowHTTPClient struct {
+	mutex sync.Mutex
+	paths []string
+	delay time.Duration
+}
+
+func (c *slowHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	c.mutex.Lock()
+	c.paths = append(c.paths, req.URL.Path)
+	c.mutex.Unlock()
+
+	select {
+	case <-time.After(c.delay):
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Header:     make(http.Header),
+	}, nil
+}
+
+func TestExecutionDeadlineSkipsRemainingEvents(t *testing.T) {
+	resetScheduler()
+	client := &slowHTTPClient{delay: 100 * time.Millisecond}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := models.Schedule{
+		Id:             bson.NewObjectId(),
+		Name:           TestScheduleName,
+		Start:          "20180101T000000",
+		Frequency:      "P1D",
+		MaxExecutionMs: 250,
+	}
+	if err := addSchedule(schedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	var last models.ScheduleEvent
+	for i := 1; i <= 5; i++ {
+		last = addTestScheduleEvent(t, schedule, "event-"+strconv.Itoa(i), http.MethodGet, "/api/v1/event/"+strconv.Itoa(i), "")
+	}
+
+	err := executeSchedule(schedule.Id.Hex())
+
+	executionErr, ok := err.(ErrExecution)
+	if !ok {
+		t.Fatalf("expected an execution error, got %v", err)
+	}
+	if !executionErr.Partial() {
+		t.Errorf("expected the execution to be partial, got %s", executionErr.Error())
+	}
+	if len(executionErr.Succeeded) < 1 || len(executionErr.Skipped) < 1 {
+		t.Errorf("expected the first events to succeed and the last ones to be skipped, got %s", executionErr.Error())
+	}
+	if len(executionErr.Succeeded)+len(executionErr.Failures)+len(executionErr.Skipped) != 5 {
+		t.Errorf(TestUnexpectedMsgFormatStr, executionErr.Error(), "5 events accounted for")
+	}
+	for _, path := range client.paths {
+		if path == last.Addressable.Path {
+			t.Errorf("expected the last event not to be requested")
+		}
+	}
+
+	history, _ := queryHistory(schedule.Id.Hex())
+	if len(history) != 1 || !history[0].Partial {
+		t.Errorf("expected a partial execution in the history, got %v", history)
+	}
+}
+
+func TestExecutionWithoutDeadlineRunsAllEvents(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	for i := 1; i <= 3; i++ {
+		addTestScheduleEvent(t, schedule, "event-"+strconv.Itoa(i), http.MethodGet, "/api/v1/event/"+strconv.Itoa(i), "")
+	}
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Errorf("unexpected error executing the schedule : %s", err.Error())
+	}
+	if len(client.requests) != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 3)
+	}
+}
+
+func TestUpdateScheduleEventSwitchesSchedule(t *testing.T) {
+	resetScheduler()
+
+	first := addTestSchedule(t, "first")
+	second := addTestSchedule(t, "second")
+	moving := addTestScheduleEvent(t, first, "moving", http.MethodGet, "/api/v1/moving", "")
+	staying := addTestScheduleEvent(t, first, "staying", http.MethodGet, "/api/v1/staying", "")
+	other := addTestScheduleEvent(t, second, "other", http.MethodGet, "/api/v1/other", "")
+
+	moving.Schedule = second.Name
+	if err := updateScheduleEvent(moving); err != nil {
+		t.Fatalf("unexpected error updating the schedule event : %s", err.Error())
+	}
+
+	assertScheduleEvents := func(schedule models.Schedule, expected ...models.ScheduleEvent) {
+		context, exists := scheduleIdToContextMap[schedule.Id.Hex()]
+		if !exists {
+			t.Fatalf("expected the schedule %s to still be scheduled", schedule.Name)
+		}
+		if len(context.ScheduleEventsMap) != len(expected) {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(context.ScheduleEventsMap), len(expected))
+		}
+		for _, scheduleEvent := range expected {
+			eventId := scheduleEvent.Id.Hex()
+			if _, exists := context.ScheduleEventsMap[eventId]; !exists {
+				t.Errorf("expected the event %s in the schedule %s", scheduleEvent.Name, schedule.Name)
+			}
+			if scheduleEventIdToScheduleIdMap[eventId] != schedule.Id.Hex() ||
+				scheduleEventNameToScheduleIdMap[scheduleEvent.Name] != schedule.Id.Hex() ||
+				scheduleEventNameToScheduleEventIdMap[scheduleEvent.Name] != eventId {
+				t.Errorf("expected the mappings of the event %s to point to the schedule %s", scheduleEvent.Name, schedule.Name)
+			}
+		}
+	}
+	assertScheduleEvents(first, staying)
+	assertScheduleEvents(second, moving, other)
+
+	//the schedule left without events is kept
+	staying.Schedule = second.Name
+	if err := updateScheduleEvent(staying); err != nil {
+		t.Fatalf("unexpected error updating the schedule event : %s", err.Error())
+	}
+	assertScheduleEvents(first)
+	assertScheduleEvents(second, moving, staying, other)
+	if _, err := queryScheduleByName(first.Name); err != nil {
+		t.Errorf("expected the schedule without events to be kept : %s", err.Error())
+	}
+	if scheduleQueue.Length() != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 2)
+	}
+}
+
+func TestUpdateScheduleEventRename(t *testing.T) {
+	resetScheduler()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, "before", http.MethodGet, "/api/v1/ping", "")
+
+	scheduleEvent.Name = "after"
+	if err := updateScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error updating the schedule event : %s", err.Error())
+	}
+
+	if _, err := queryScheduleEventByName("before"); err == nil {
+		t.Error("expected the previous name to be dropped")
+	}
+	if found, err := queryScheduleEventByName("after"); err != nil || found.Id != scheduleEvent.Id {
+		t.Errorf("expected the event to be found by its new name")
+	}
+}
+
+func TestMaxIterationsSchedule(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := models.Schedule{
+		Id:            bson.NewObjectId(),
+		Name:          TestScheduleName,
+		Start:         "20180101T000000",
+		Frequency:     "PT60S",
+		MaxIterations: 3,
+	}
+	if err := addSchedule(schedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	for i := 0; i < 10; i++ {
+		fake.Advance(time.Minute)
+		triggerSchedule()
+		waitForExecutions()
+	}
+
+	if len(client.requests) != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 3)
+	}
+	context := scheduleIdToContextMap[schedule.Id.Hex()]
+	if !context.IsComplete() || context.CurrentIterations != 3 {
+		t.Errorf("expected the schedule to be complete after 3 iterations, got %d", context.CurrentIterations)
+	}
+	if scheduleQueue.Length() != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 0)
+	}
+}
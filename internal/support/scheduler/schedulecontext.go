@@ -8,46 +8,76 @@
 package scheduler
 
 import (
+	"fmt"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/robfig/cron"
 
+	"math/rand"
+	"reflect"
 	"regexp"
 	"strconv"
 	"time"
 )
 
 type ScheduleContext struct {
-	Schedule          models.Schedule
-	ScheduleEventsMap map[string]models.ScheduleEvent
-	StartTime         time.Time
-	EndTime           time.Time
-	NextTime          time.Time
-	Frequency         time.Duration
-	CurrentIterations int64
-	MaxIterations     int64
-	MarkedDeleted     bool
-}
-
-func (sc *ScheduleContext) Reset(schedule models.Schedule) {
-	if sc.Schedule != (models.Schedule{}) && sc.Schedule.Name != schedule.Name {
+	Schedule            models.Schedule
+	ScheduleEventsMap   map[string]models.ScheduleEvent
+	StartTime           time.Time
+	EndTime             time.Time
+	NextTime            time.Time
+	Frequency           time.Duration
+	CurrentIterations   int64
+	MaxIterations       int64
+	MarkedDeleted       bool
+	Paused              bool
+	Executing           bool // set while an execution is running, guarded by the schedule mutex
+	ConsecutiveFailures int  // executions in a row with a failed event
+	AutoPaused          bool // paused after reaching the FailureThreshold
+	Location            *time.Location
+	Jitter              time.Duration
+	cronSchedule        cron.Schedule
+	jitterOffset        time.Duration
+	autoPausedAt        time.Time
+	failingSince        time.Time     // end of the first execution of the current run of failures
+	rescheduled         bool          // reset while executing, the running execution keeps the new next time
+	triggered           bool          // executing on demand, the schedule stays queued for its next fire
+	interval            time.Duration // gap to the fire after the next one under the multiplicative interval policy
+	intervalLimit       time.Duration // the interval stops at it under the multiplicative interval policy
+	window              *fireWindow   // the days and time of day the schedule fires in, nil fires at any time
+}
+
+func (sc *ScheduleContext) Reset(schedule models.Schedule) error {
+	if !reflect.DeepEqual(sc.Schedule, models.Schedule{}) && sc.Schedule.Name != schedule.Name {
 		//if schedule name has changed, we should clear the old events map(here just renew one)
 		sc.ScheduleEventsMap = make(map[string]models.ScheduleEvent)
 	}
 
 	sc.Schedule = schedule
 
+	if isOneOff(sc.Schedule) {
+		sc.Schedule.RunOnce = true
+	}
+
 	//run times, current and max iteration
 	if sc.Schedule.RunOnce {
 		sc.MaxIterations = 1
 	} else {
-		sc.MaxIterations = 0
+		sc.MaxIterations = int64(sc.Schedule.MaxIterations)
 	}
 	sc.CurrentIterations = 0
 
+	//time zone, an empty name loads UTC
+	location, err := time.LoadLocation(sc.Schedule.Timezone)
+	if err != nil {
+		return fmt.Errorf("the schedule %s has an invalid time zone %s : %s", sc.Schedule.Name, sc.Schedule.Timezone, err.Error())
+	}
+	sc.Location = location
+
 	//start and end time
 	if sc.Schedule.Start == "" {
-		sc.StartTime = time.Now()
+		sc.StartTime = clock.Now()
 	} else {
-		t, err := time.Parse(TIMELAYOUT, sc.Schedule.Start)
+		t, err := time.ParseInLocation(TIMELAYOUT, sc.Schedule.Start, sc.Location)
 		if err != nil {
 			LoggingClient.Error("parse time error, the original time string is : " + sc.Schedule.Start)
 		}
@@ -59,7 +89,7 @@ func (sc *ScheduleContext) Reset(schedule models.Schedule) {
 		//use max time
 		sc.EndTime = time.Unix(1<<63-62135596801, 999999999)
 	} else {
-		t, err := time.Parse(TIMELAYOUT, sc.Schedule.End)
+		t, err := time.ParseInLocation(TIMELAYOUT, sc.Schedule.End, sc.Location)
 		if err != nil {
 			LoggingClient.Error("parse time error, the original time string is : " + sc.Schedule.End)
 		}
@@ -68,19 +98,108 @@ func (sc *ScheduleContext) Reset(schedule models.Schedule) {
 	}
 
 	//frequency and next time
-	nowBenchmark := time.Now().Unix()
+	nowBenchmark := clock.Now().Unix()
 	sc.Frequency = parseFrequency(sc.Schedule.Frequency)
+	sc.Jitter = time.Duration(sc.Schedule.JitterMs) * time.Millisecond
+	sc.jitterOffset = 0
 
+	//a cron expression is only used when no frequency is given
+	sc.cronSchedule = nil
+	if sc.Schedule.Frequency == "" && sc.Schedule.Cron != "" {
+		sc.cronSchedule, err = parseCron(sc.Schedule.Cron)
+		if err != nil {
+			return fmt.Errorf("the schedule %s has an invalid cron expression %s : %s", sc.Schedule.Name, sc.Schedule.Cron, err.Error())
+		}
+	}
+
+	switch sc.Schedule.MissedFirePolicy {
+	case "", MissedFireSkip, MissedFireOnce, MissedFireCatchUp:
+	default:
+		return fmt.Errorf("the schedule %s has an unknown missed fire policy %s", sc.Schedule.Name, sc.Schedule.MissedFirePolicy)
+	}
+	if sc.Schedule.MaxExecutionMs < 0 {
+		return fmt.Errorf("the schedule %s has a negative max execution time %d", sc.Schedule.Name, sc.Schedule.MaxExecutionMs)
+	}
+	if sc.Schedule.MaxIterations < 0 {
+		return fmt.Errorf("the schedule %s has a negative max iterations %d", sc.Schedule.Name, sc.Schedule.MaxIterations)
+	}
+
+	//a repeating schedule without an interval would fire on every tick
+	if sc.cronSchedule == nil && !sc.Schedule.RunOnce && sc.Frequency <= 0 {
+		return fmt.Errorf("the schedule %s has an invalid frequency %s, the interval must be greater than zero", sc.Schedule.Name, sc.Schedule.Frequency)
+	}
+
+	//intervals under the configured floor are rejected or raised to the floor
+	if floor := minInterval(); sc.cronSchedule == nil && !sc.Schedule.RunOnce && sc.Frequency < floor {
+		if Configuration.RejectBelowMinInterval {
+			return fmt.Errorf("the schedule %s has a frequency %s below the minimum interval of %s", sc.Schedule.Name, sc.Schedule.Frequency, floor)
+		}
+		LoggingClient.Warn(fmt.Sprintf("the schedule %s has a frequency %s below the minimum interval, using %s instead", sc.Schedule.Name, sc.Schedule.Frequency, floor))
+		sc.Frequency = floor
+	}
+
+	if err := sc.resetIntervalPolicy(); err != nil {
+		return err
+	}
+	if err := sc.resetWindow(); err != nil {
+		return err
+	}
+
+	// a future start is the first fire time, otherwise fire on the first interval boundary after now
 	sc.NextTime = sc.StartTime
-	if sc.StartTime.Unix() <= nowBenchmark && !sc.Schedule.RunOnce {
-		for sc.NextTime.Unix() <= nowBenchmark {
-			sc.NextTime = sc.NextTime.Add(sc.Frequency)
+	if sc.cronSchedule != nil {
+		after := time.Unix(nowBenchmark, 0)
+		if sc.StartTime.Unix() > nowBenchmark {
+			after = sc.StartTime.Add(-time.Second)
+		}
+		sc.NextTime = sc.nextCronTime(after)
+	} else if sc.isAligned() {
+		after := time.Unix(nowBenchmark, 0)
+		if sc.StartTime.Unix() > nowBenchmark {
+			after = sc.StartTime.Add(-time.Nanosecond)
 		}
+		sc.NextTime = sc.nextAlignedTime(after)
+	} else if sc.StartTime.Unix() <= nowBenchmark && !sc.Schedule.RunOnce && sc.Frequency > 0 {
+		elapsed := time.Unix(nowBenchmark, 0).Sub(sc.StartTime)
+		sc.NextTime = sc.StartTime.Add((elapsed/sc.Frequency + 1) * sc.Frequency)
 	}
+
+	if sc.Schedule.Start != "" && sc.Schedule.End != "" && sc.EndTime.Before(sc.StartTime) {
+		return fmt.Errorf("the schedule %s ends at %s before it starts at %s", sc.Schedule.Name, sc.Schedule.End, sc.Schedule.Start)
+	}
+
+	return nil
+}
+
+// a start without a frequency or a cron expression is the single fire time of a one-off schedule, whether it is
+// flagged run once or not
+func isOneOff(schedule models.Schedule) bool {
+	return schedule.Start != "" && schedule.Frequency == "" && schedule.Cron == ""
+}
+
+// Check a schedule being created fires at least once. Only the creations check it, a one-off loaded once its start
+// has passed fires then unless the saved state tells it has fired already.
+func validateOneOffStart(schedule models.Schedule) error {
+	if !isOneOff(schedule) {
+		return nil
+	}
+	candidate := ScheduleContext{}
+	if err := candidate.Reset(schedule); err != nil {
+		return err
+	}
+	if candidate.StartTime.Unix() <= clock.Now().Unix() {
+		return fmt.Errorf("the one-off schedule %s fires at %s which is not in the future", schedule.Name, schedule.Start)
+	}
+	return nil
 }
 
 func (sc *ScheduleContext) IsComplete() bool {
-	return sc.isComplete(time.Now())
+	return sc.isComplete(clock.Now())
+}
+
+// IsEnded reports whether the end time of the schedule has passed.
+func (sc *ScheduleContext) IsEnded() bool {
+	return sc.isEnded(clock.Now())
 }
 
 func (sc *ScheduleContext) UpdateIterations() {
@@ -91,25 +210,201 @@ func (sc *ScheduleContext) UpdateIterations() {
 
 func (sc *ScheduleContext) UpdateNextTime() {
 	if !sc.IsComplete() {
-		sc.NextTime = sc.NextTime.Add(sc.Frequency)
+		//advance from the fire time without jitter so the offsets do not accumulate
+		next := sc.NextTime.Add(-sc.jitterOffset)
+		if sc.cronSchedule != nil {
+			next = sc.nextCronTime(next)
+		} else if sc.isAligned() {
+			next = sc.nextAlignedTime(next)
+		} else {
+			next = next.Add(sc.currentInterval())
+			sc.advanceInterval()
+		}
+		sc.jitterOffset = sc.nextJitter(next)
+		sc.NextTime = next.Add(sc.jitterOffset)
 	}
 }
 
+// SkipMissedFires moves the next fire time to the first one after now, dropping the fires which were missed.
+func (sc *ScheduleContext) SkipMissedFires() {
+	sc.skipMissedFires(clock.Now())
+}
+
 func (sc *ScheduleContext) GetInfo() string {
 	return sc.Schedule.String()
 }
 
 func (sc *ScheduleContext) isComplete(time time.Time) bool {
-	complete := (sc.StartTime.Unix() < time.Unix() && sc.Schedule.RunOnce) ||
-		(sc.NextTime.Unix() > sc.EndTime.Unix()) ||
-		((sc.MaxIterations != 0) && (sc.CurrentIterations >= sc.MaxIterations))
-	return complete
+	return sc.completionReason(time) != ""
+}
+
+// CompletionReason reports the stop condition which completed the schedule, empty while it is not complete.
+func (sc *ScheduleContext) CompletionReason() string {
+	return sc.completionReason(clock.Now())
+}
+
+// The schedule completes on the first of its stop conditions to be met. They are checked in the order RunOnce,
+// MaxIterations then End, so a schedule whose last iteration coincides with its end completes by MaxIterations.
+func (sc *ScheduleContext) completionReason(time time.Time) string {
+	switch {
+	case sc.StartTime.Unix() < time.Unix() && sc.Schedule.RunOnce:
+		return CompletedRunOnce
+	case sc.MaxIterations != 0 && sc.CurrentIterations >= sc.MaxIterations:
+		return CompletedMaxIterations
+	case sc.NextTime.Unix() > sc.EndTime.Unix() || sc.isEnded(time):
+		return CompletedEnd
+	}
+	return ""
+}
+
+func (sc *ScheduleContext) skipMissedFires(now time.Time) {
+	next := sc.NextTime.Add(-sc.jitterOffset)
+	if next.Unix() > now.Unix() {
+		return
+	}
+
+	if sc.cronSchedule != nil {
+		next = sc.nextCronTime(now)
+	} else if sc.isAligned() {
+		next = sc.nextAlignedTime(now)
+	} else if interval := sc.currentInterval(); interval > 0 {
+		elapsed := now.Sub(next)
+		next = next.Add((elapsed/interval + 1) * interval)
+	} else {
+		return
+	}
+	sc.jitterOffset = sc.nextJitter(next)
+	sc.NextTime = next.Add(sc.jitterOffset)
+}
+
+// the fires due after the next one up to now, they were missed while the scheduler was down
+func (sc *ScheduleContext) missedFires(now time.Time) int64 {
+	next := sc.NextTime.Add(-sc.jitterOffset)
+	if next.Unix() > now.Unix() {
+		return 0
+	}
+
+	if sc.cronSchedule != nil {
+		var missed int64
+		for next = sc.nextCronTime(next); next.Unix() <= now.Unix() && missed < MaxMissedCronFires; next = sc.nextCronTime(next) {
+			missed++
+		}
+		return missed
+	} else if sc.Frequency > 0 && !sc.Schedule.RunOnce {
+		return int64(now.Sub(next) / sc.Frequency)
+	}
+	return 0
+}
+
+// applies the missed fire policy to a due schedule, false when the due fire is dropped
+func (sc *ScheduleContext) prepareFire(now time.Time) bool {
+	missed := sc.missedFires(now)
+	if missed == 0 {
+		return true
+	}
+
+	switch sc.Schedule.MissedFirePolicy {
+	case MissedFireSkip:
+		sc.skipMissedFires(now)
+		return false
+	case MissedFireOnce:
+		return true
+	default:
+		//keep the latest fires only, the due one included
+		if limit := maxCatchUpFires(); limit > 0 && missed >= limit {
+			sc.advanceFires(missed + 1 - limit)
+		}
+		return true
+	}
+}
+
+// moves the schedule to its next fire time once it has fired
+func (sc *ScheduleContext) advanceAfterFire(now time.Time) {
+	sc.UpdateNextTime()
+	if sc.Schedule.MissedFirePolicy == MissedFireOnce {
+		sc.skipMissedFires(now)
+	}
+}
+
+func (sc *ScheduleContext) advanceFires(count int64) {
+	next := sc.NextTime.Add(-sc.jitterOffset)
+	if sc.cronSchedule != nil {
+		for i := int64(0); i < count; i++ {
+			next = sc.nextCronTime(next)
+		}
+	} else if sc.isMultiplicative() {
+		for i := int64(0); i < count; i++ {
+			next = next.Add(sc.interval)
+			sc.advanceInterval()
+		}
+	} else {
+		next = next.Add(time.Duration(count) * sc.Frequency)
+	}
+	sc.jitterOffset = sc.nextJitter(next)
+	sc.NextTime = next.Add(sc.jitterOffset)
+}
+
+// random offset within the jitter window which never moves the fire time past the end time
+func (sc *ScheduleContext) nextJitter(next time.Time) time.Duration {
+	if sc.Jitter <= 0 {
+		return 0
+	}
+
+	offset := time.Duration(rand.Int63n(int64(sc.Jitter)))
+	if next.Add(offset).After(sc.EndTime) {
+		offset = sc.EndTime.Sub(next)
+		if offset < 0 {
+			offset = 0
+		}
+	}
+	return offset
+}
+
+// cron fields are evaluated against the wall clock of the schedule time zone
+func (sc *ScheduleContext) nextCronTime(after time.Time) time.Time {
+	return sc.cronSchedule.Next(after.In(sc.Location))
+}
+
+// aligned schedules fire on the multiples of their frequency since the epoch in their time zone
+func (sc *ScheduleContext) isAligned() bool {
+	return sc.Schedule.AlignToInterval && sc.cronSchedule == nil && !sc.Schedule.RunOnce && sc.Frequency > 0
+}
+
+// the first aligned boundary strictly after the given time
+func (sc *ScheduleContext) nextAlignedTime(after time.Time) time.Time {
+	_, offset := after.In(sc.Location).Zone()
+	anchor := time.Unix(-int64(offset), 0).In(sc.Location)
+	elapsed := after.Sub(anchor)
+	return anchor.Add((elapsed/sc.Frequency + 1) * sc.Frequency)
+}
+
+func (sc *ScheduleContext) isEnded(time time.Time) bool {
+	return time.Unix() > sc.EndTime.Unix()
 }
 
 //region util methods
+// the configured floor for the schedule intervals, 0 when there is none
+func minInterval() time.Duration {
+	if Configuration == nil || Configuration.MinIntervalMs <= 0 {
+		return 0
+	}
+	return time.Duration(Configuration.MinIntervalMs) * time.Millisecond
+}
+
+// the configured bound on the missed fires replayed by the catch-up policy, 0 when there is none
+func maxCatchUpFires() int64 {
+	if Configuration == nil || Configuration.MaxCatchUpFires <= 0 {
+		return 0
+	}
+	return int64(Configuration.MaxCatchUpFires)
+}
+
 func parseFrequency(durationStr string) time.Duration {
 	durationRegex := regexp.MustCompile(`P(?P<years>\d+Y)?(?P<months>\d+M)?(?P<days>\d+D)?T?(?P<hours>\d+H)?(?P<minutes>\d+M)?(?P<seconds>\d+S)?`)
 	matches := durationRegex.FindStringSubmatch(durationStr)
+	if matches == nil {
+		return 0
+	}
 
 	years := parseInt64(matches[1])
 	months := parseInt64(matches[2])
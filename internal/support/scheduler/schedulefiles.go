@@ -0,0 +1,403 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/edgexfoundry/edgex-go/internal/pkg/config"
+	"github.com/fsnotify/fsnotify"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultScheduleDirPollMs is how often ScheduleDir is scanned when it can not be watched and ScheduleDirPollMs
+// is not configured
+const DefaultScheduleDirPollMs = 5000
+
+// how long the watcher waits for the events of a file to settle before scanning, editors write a file in steps
+const scheduleDirSettleTime = 100 * time.Millisecond
+
+// The definitions of a schedule file, laid out like the Schedules and ScheduleEvents of the configuration. The
+// YAML files use the lower case field names, e.g. maxexecutionms.
+type scheduleFile struct {
+	Schedules      map[string]config.ScheduleInfo
+	ScheduleEvents map[string]config.ScheduleEventInfo
+}
+
+type fileSchedule struct {
+	id   string
+	info config.ScheduleInfo
+}
+
+type fileScheduleEvent struct {
+	id   string
+	info config.ScheduleEventInfo
+}
+
+// a schedule file as it was last read, with the schedules and events applied from it by name
+type loadedScheduleFile struct {
+	modTime        time.Time
+	size           int64
+	schedules      map[string]fileSchedule
+	scheduleEvents map[string]fileScheduleEvent
+}
+
+var (
+	fileMutex      sync.Mutex
+	loadedFiles    = make(map[string]*loadedScheduleFile)
+	dirWatcherStop chan struct{}
+	dirWatcherDone chan struct{}
+)
+
+func clearScheduleFiles() {
+	fileMutex.Lock()
+	defer fileMutex.Unlock()
+
+	loadedFiles = make(map[string]*loadedScheduleFile)
+}
+
+func scheduleDir() string {
+	if Configuration == nil {
+		return ""
+	}
+	return Configuration.ScheduleDir
+}
+
+func scheduleDirPollInterval() time.Duration {
+	if Configuration == nil || Configuration.ScheduleDirPollMs <= 0 {
+		return DefaultScheduleDirPollMs * time.Millisecond
+	}
+	return time.Duration(Configuration.ScheduleDirPollMs) * time.Millisecond
+}
+
+// StartScheduleDirWatcher loads the schedule files of ScheduleDir and keeps the scheduler in line with them while
+// they are added, changed and removed. The directory is scanned on its file system events, or every
+// ScheduleDirPollMs when it can not be watched. Nothing is watched when ScheduleDir is not configured.
+func StartScheduleDirWatcher() {
+	dir := scheduleDir()
+	if dir == "" || dirWatcherStop != nil {
+		return
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = watcher.Add(dir); err != nil {
+			watcher.Close()
+		}
+	}
+	if err != nil {
+		watcher = nil
+		LoggingClient.Warn(fmt.Sprintf("could not watch the schedule directory %s, polling it every %s : %s",
+			dir, scheduleDirPollInterval(), err.Error()))
+	} else {
+		LoggingClient.Info("watching the schedule files of " + dir)
+	}
+	scanScheduleDir(dir)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	dirWatcherStop, dirWatcherDone = stop, done
+	go func() {
+		defer close(done)
+		if watcher != nil {
+			watchScheduleDir(dir, watcher, stop)
+		} else {
+			pollScheduleDir(dir, stop)
+		}
+	}()
+}
+
+// Scan the directory once the events of its schedule files have settled. An error of the watcher, e.g. an
+// overflow of its events, rescans the directory in case an event was lost.
+func watchScheduleDir(dir string, watcher *fsnotify.Watcher, stop chan struct{}) {
+	defer watcher.Close()
+
+	settle := time.NewTimer(scheduleDirSettleTime)
+	settle.Stop()
+	defer settle.Stop()
+	for {
+		select {
+		case event := <-watcher.Events:
+			if isScheduleFile(event.Name) {
+				settle.Reset(scheduleDirSettleTime)
+			}
+		case err := <-watcher.Errors:
+			LoggingClient.Warn(fmt.Sprintf("error watching the schedule directory %s : %s", dir, err.Error()))
+			settle.Reset(scheduleDirSettleTime)
+		case <-settle.C:
+			scanScheduleDir(dir)
+		case <-stop:
+			return
+		}
+	}
+}
+
+func pollScheduleDir(dir string, stop chan struct{}) {
+	ticker := time.NewTicker(scheduleDirPollInterval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			scanScheduleDir(dir)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// StopScheduleDirWatcher returns once a scan in progress is over
+func StopScheduleDirWatcher() {
+	if dirWatcherStop != nil {
+		close(dirWatcherStop)
+		<-dirWatcherDone
+		dirWatcherStop, dirWatcherDone = nil, nil
+	}
+}
+
+func isScheduleFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".toml", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// Apply the schedule files which changed since the last scan. A file which can not be read or is invalid is
+// skipped until it changes again, what was applied from it before stays scheduled.
+func scanScheduleDir(dir string) {
+	fileMutex.Lock()
+	defer fileMutex.Unlock()
+
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		LoggingClient.Error(fmt.Sprintf("error reading the schedule directory %s : %s", dir, err.Error()))
+		return
+	}
+
+	present := make(map[string]bool)
+	for _, info := range infos {
+		if info.IsDir() || !isScheduleFile(info.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, info.Name())
+		present[path] = true
+
+		loaded, exists := loadedFiles[path]
+		if exists && loaded.modTime.Equal(info.ModTime()) && loaded.size == info.Size() {
+			continue
+		}
+		if !exists {
+			loaded = &loadedScheduleFile{
+				schedules:      make(map[string]fileSchedule),
+				scheduleEvents: make(map[string]fileScheduleEvent),
+			}
+			loadedFiles[path] = loaded
+		}
+		loaded.modTime = info.ModTime()
+		loaded.size = info.Size()
+
+		file, err := readScheduleFile(path)
+		if err == nil {
+			err = validateScheduleFile(file, loaded)
+		}
+		if err != nil {
+			LoggingClient.Error(fmt.Sprintf("the schedule file %s is skipped : %s", path, err.Error()))
+			continue
+		}
+		LoggingClient.Info("applying the schedule file " + path)
+		applyScheduleFile(file, loaded)
+	}
+
+	for path, loaded := range loadedFiles {
+		if !present[path] {
+			LoggingClient.Info("removing the schedules of the schedule file " + path)
+			applyScheduleFile(scheduleFile{}, loaded)
+			delete(loadedFiles, path)
+		}
+	}
+}
+
+func readScheduleFile(path string) (scheduleFile, error) {
+	var file scheduleFile
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return file, err
+	}
+
+	if strings.ToLower(filepath.Ext(path)) == ".toml" {
+		_, err = toml.Decode(string(contents), &file)
+	} else {
+		err = yaml.Unmarshal(contents, &file)
+	}
+	return file, err
+}
+
+// Check the definitions of a file like the configuration, the schedules and events it defines may not already
+// be defined by core-metadata, the configuration or another file
+func validateScheduleFile(file scheduleFile, loaded *loadedScheduleFile) error {
+	var problems []string
+	if err := validateConfig(file.Schedules, file.ScheduleEvents); err != nil {
+		problems = append(problems, err.(ErrInvalidConfig).Problems...)
+	}
+
+	for _, key := range sortedScheduleKeys(file.Schedules) {
+		info := file.Schedules[key]
+		if info.Name == "" {
+			continue
+		}
+		candidate := ScheduleContext{}
+		if err := candidate.Reset(scheduleFromConfig(info)); err != nil {
+			problems = append(problems, fmt.Sprintf("the schedule %q can not be scheduled : %s", info.Name, err.Error()))
+		} else if _, applied := loaded.schedules[info.Name]; !applied {
+			if err := validateOneOffStart(scheduleFromConfig(info)); err != nil {
+				problems = append(problems, fmt.Sprintf("the schedule %q can not be scheduled : %s", info.Name, err.Error()))
+			}
+		}
+		if existing, err := queryScheduleByName(info.Name); err == nil && existing.Id.Hex() != loaded.schedules[info.Name].id {
+			problems = append(problems, fmt.Sprintf("the schedule %q is already defined outside of the file", info.Name))
+		}
+	}
+
+	for _, key := range sortedScheduleEventKeys(file.ScheduleEvents) {
+		info := file.ScheduleEvents[key]
+		if info.Name == "" {
+			continue
+		}
+		if err := validateScheduleEvent(scheduleEventFromConfig(info)); err != nil {
+			problems = append(problems, err.Error())
+		}
+		if existing, err := queryScheduleEventByName(info.Name); err == nil && existing.Id.Hex() != loaded.scheduleEvents[info.Name].id {
+			problems = append(problems, fmt.Sprintf("the schedule event %q is already defined outside of the file", info.Name))
+		}
+	}
+
+	if len(problems) > 0 {
+		return ErrInvalidConfig{Problems: problems}
+	}
+	return nil
+}
+
+// Bring the scheduler in line with a file, only the schedules and events which changed are touched. The file
+// definitions only live in the scheduler, core-metadata does not know about them.
+func applyScheduleFile(file scheduleFile, loaded *loadedScheduleFile) {
+	//the schedules go first for the events to find them
+	scheduleNames := make(map[string]bool)
+	for _, key := range sortedScheduleKeys(file.Schedules) {
+		info := file.Schedules[key]
+		scheduleNames[info.Name] = true
+		schedule := scheduleFromConfig(info)
+
+		previous, exists := loaded.schedules[info.Name]
+		if exists && reflect.DeepEqual(previous.info, info) {
+			continue
+		}
+		if exists {
+			schedule.Id = bson.ObjectIdHex(previous.id)
+			if err := updateSchedule(schedule); err != nil {
+				LoggingClient.Error(fmt.Sprintf("error updating the schedule %s of a schedule file : %s", info.Name, err.Error()))
+				delete(loaded.schedules, info.Name)
+				continue
+			}
+		} else {
+			schedule.Id = bson.NewObjectId()
+			if err := addSchedule(schedule); err != nil {
+				LoggingClient.Error(fmt.Sprintf("error adding the schedule %s of a schedule file : %s", info.Name, err.Error()))
+				continue
+			}
+		}
+		loaded.schedules[info.Name] = fileSchedule{id: schedule.Id.Hex(), info: info}
+	}
+
+	scheduleEventNames := make(map[string]bool)
+	for _, key := range sortedScheduleEventKeys(file.ScheduleEvents) {
+		info := file.ScheduleEvents[key]
+		scheduleEventNames[info.Name] = true
+		scheduleEvent := scheduleEventFromConfig(info)
+
+		previous, exists := loaded.scheduleEvents[info.Name]
+		if exists && reflect.DeepEqual(previous.info, info) {
+			continue
+		}
+		if exists {
+			scheduleEvent.Id = bson.ObjectIdHex(previous.id)
+			if err := updateScheduleEvent(scheduleEvent); err != nil {
+				LoggingClient.Error(fmt.Sprintf("error updating the schedule event %s of a schedule file : %s", info.Name, err.Error()))
+				continue
+			}
+		} else {
+			scheduleEvent.Id = bson.NewObjectId()
+			if err := addScheduleEvent(scheduleEvent); err != nil {
+				LoggingClient.Error(fmt.Sprintf("error adding the schedule event %s of a schedule file : %s", info.Name, err.Error()))
+				continue
+			}
+		}
+		loaded.scheduleEvents[info.Name] = fileScheduleEvent{id: scheduleEvent.Id.Hex(), info: info}
+	}
+
+	//the events left out of the file go before their schedules
+	for name, scheduleEvent := range loaded.scheduleEvents {
+		if !scheduleEventNames[name] {
+			if err := removeScheduleEvent(scheduleEvent.id); err != nil {
+				LoggingClient.Error(fmt.Sprintf("error removing the schedule event %s of a schedule file : %s", name, err.Error()))
+			}
+			delete(loaded.scheduleEvents, name)
+		}
+	}
+	for name, schedule := range loaded.schedules {
+		if !scheduleNames[name] {
+			if err := removeSchedule(schedule.id); err != nil {
+				LoggingClient.Error(fmt.Sprintf("error removing the schedule %s of a schedule file : %s", name, err.Error()))
+			}
+			delete(loaded.schedules, name)
+		}
+	}
+}
+
+// The ids of the schedules and schedule events defined by the schedule files, a reload from core-metadata leaves
+// them alone
+func scheduleFileIds() (map[string]bool, map[string]bool) {
+	fileMutex.Lock()
+	defer fileMutex.Unlock()
+
+	scheduleIds := make(map[string]bool)
+	scheduleEventIds := make(map[string]bool)
+	for _, loaded := range loadedFiles {
+		for _, schedule := range loaded.schedules {
+			scheduleIds[schedule.id] = true
+		}
+		for _, scheduleEvent := range loaded.scheduleEvents {
+			scheduleEventIds[scheduleEvent.id] = true
+		}
+	}
+	return scheduleIds, scheduleEventIds
+}
+
+func sortedScheduleKeys(schedules map[string]config.ScheduleInfo) []string {
+	keys := make([]string, 0, len(schedules))
+	for key := range schedules {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedScheduleEventKeys(scheduleEvents map[string]config.ScheduleEventInfo) []string {
+	keys := make([]string, 0, len(scheduleEvents))
+	for key := range scheduleEvents {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,265 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testScheduleFile = `
+[Schedules.file-schedule]
+Name = 'file-schedule'
+Start = '20180101T000000'
+Frequency = 'PT1H'
+
+[ScheduleEvents.file-event]
+Name = 'file-event'
+Schedule = 'file-schedule'
+Host = 'localhost'
+Port = 48080
+Protocol = 'http'
+Method = 'DELETE'
+Path = '/api/v1/event/scrub'
+`
+
+const testScheduleYAMLFile = `
+schedules:
+  file-schedule:
+    name: file-schedule
+    start: '20180101T000000'
+    frequency: %s
+scheduleevents:
+  file-event:
+    name: file-event
+    schedule: file-schedule
+    host: localhost
+    port: 48080
+    protocol: http
+    method: DELETE
+    path: /api/v1/event/scrub
+`
+
+func useScheduleDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "schedules")
+	if err != nil {
+		t.Fatalf("unexpected error creating the schedule directory : %s", err.Error())
+	}
+	Configuration = &ConfigurationStruct{ScheduleDir: dir}
+	return dir, func() {
+		Configuration = &ConfigurationStruct{}
+		os.RemoveAll(dir)
+	}
+}
+
+// write the file with a modification time of its own so every write is seen as a change
+func writeScheduleFile(t *testing.T, path string, contents string, modTime time.Time) {
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("unexpected error writing the schedule file : %s", err.Error())
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("unexpected error setting the time of the schedule file : %s", err.Error())
+	}
+}
+
+// wait for the schedule event of the test files to be defined or not
+func waitForFileEvent(defined bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := queryScheduleEventByName("file-event"); (err == nil) == defined {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestScheduleDirWatcherPicksUpNewFile(t *testing.T) {
+	resetScheduler()
+	dir, cleanup := useScheduleDir(t)
+	defer cleanup()
+
+	//the default poll is well past the wait, the file is picked up by its events
+	StartScheduleDirWatcher()
+	defer StopScheduleDirWatcher()
+
+	path := filepath.Join(dir, "scrub.toml")
+	writeScheduleFile(t, path, testScheduleFile, time.Now())
+	waitForFileEvent(true)
+
+	schedule, err := queryScheduleByName("file-schedule")
+	if err != nil {
+		t.Fatalf("expected the schedule of the file to be picked up : %s", err.Error())
+	}
+	scheduleEvents, err := queryScheduleEventsOfSchedule("file-schedule")
+	if err != nil || len(scheduleEvents) != 1 || scheduleEvents[0].Addressable.Path != "/api/v1/event/scrub" {
+		t.Fatalf("expected the schedule event of the file to be picked up, got %v", scheduleEvents)
+	}
+
+	mutex.Lock()
+	queued := scheduleQueue.Length() == 1 && scheduleQueue.Peek().(*ScheduleContext).Schedule.Id == schedule.Id
+	mutex.Unlock()
+	if !queued {
+		t.Errorf("expected the schedule of the file to be queued")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("unexpected error removing the schedule file : %s", err.Error())
+	}
+	waitForFileEvent(false)
+	if _, err := queryScheduleByName("file-schedule"); err == nil {
+		t.Error("expected the schedule of the removed file to be removed")
+	}
+}
+
+func TestScheduleDirIsPolledWhenItCanNotBeWatched(t *testing.T) {
+	resetScheduler()
+	dir, cleanup := useScheduleDir(t)
+	defer cleanup()
+	Configuration.ScheduleDirPollMs = 10
+
+	//the directory does not exist yet when the watcher starts
+	os.RemoveAll(dir)
+	StartScheduleDirWatcher()
+	defer StopScheduleDirWatcher()
+
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("unexpected error creating the schedule directory : %s", err.Error())
+	}
+	writeScheduleFile(t, filepath.Join(dir, "scrub.toml"), testScheduleFile, time.Now())
+	waitForFileEvent(true)
+
+	if _, err := queryScheduleByName("file-schedule"); err != nil {
+		t.Fatalf("expected the schedule of the file to be picked up by the poll : %s", err.Error())
+	}
+}
+
+func TestScheduleFileChangesAreApplied(t *testing.T) {
+	resetScheduler()
+	dir, cleanup := useScheduleDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "scrub.yaml")
+	modTime := time.Now().Add(-time.Hour)
+
+	writeScheduleFile(t, path, fmt.Sprintf(testScheduleYAMLFile, "PT1H"), modTime)
+	scanScheduleDir(dir)
+	original, err := queryScheduleByName("file-schedule")
+	if err != nil {
+		t.Fatalf("expected the schedule of the file to be loaded : %s", err.Error())
+	}
+
+	writeScheduleFile(t, path, fmt.Sprintf(testScheduleYAMLFile, "PT2H"), modTime.Add(time.Second))
+	scanScheduleDir(dir)
+	updated, err := queryScheduleByName("file-schedule")
+	if err != nil || updated.Id != original.Id || updated.Frequency != "PT2H" {
+		t.Errorf("expected the schedule to be updated in place, got %s", updated.String())
+	}
+
+	//the event is left alone as it did not change
+	scheduleEvents, _ := queryScheduleEventsOfSchedule("file-schedule")
+	if len(scheduleEvents) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(scheduleEvents), 1)
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatalf("unexpected error removing the schedule file : %s", err.Error())
+	}
+	scanScheduleDir(dir)
+	if _, err := queryScheduleByName("file-schedule"); err == nil {
+		t.Error("expected the schedule of the removed file to be removed")
+	}
+	if _, err := queryScheduleEventByName("file-event"); err == nil {
+		t.Error("expected the schedule event of the removed file to be removed")
+	}
+}
+
+func TestInvalidScheduleFileKeepsState(t *testing.T) {
+	resetScheduler()
+	dir, cleanup := useScheduleDir(t)
+	defer cleanup()
+	path := filepath.Join(dir, "scrub.toml")
+	modTime := time.Now().Add(-time.Hour)
+
+	writeScheduleFile(t, path, testScheduleFile, modTime)
+	scanScheduleDir(dir)
+
+	tests := []struct {
+		name     string
+		contents string
+	}{
+		{"malformed", "[Schedules.file-schedule\nName = "},
+		{"frequency and cron", `
+[Schedules.file-schedule]
+Name = 'file-schedule'
+Frequency = 'PT1H'
+Cron = '0 0 * * * *'
+`},
+		{"unknown schedule", `
+[ScheduleEvents.file-event]
+Name = 'file-event'
+Schedule = 'missing'
+Host = 'localhost'
+Port = 48080
+Protocol = 'http'
+Method = 'GET'
+`},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeScheduleFile(t, path, tt.contents, modTime.Add(time.Duration(i+1)*time.Second))
+			scanScheduleDir(dir)
+
+			schedule, err := queryScheduleByName("file-schedule")
+			if err != nil || schedule.Frequency != "PT1H" || schedule.Cron != "" {
+				t.Errorf("expected the schedule of the file to be unchanged, got %s", schedule.String())
+			}
+			if _, err := queryScheduleEventByName("file-event"); err != nil {
+				t.Errorf("expected the schedule event of the file to be kept : %s", err.Error())
+			}
+		})
+	}
+}
+
+func TestScheduleFileCanNotRedefineSchedule(t *testing.T) {
+	resetScheduler()
+	dir, cleanup := useScheduleDir(t)
+	defer cleanup()
+	existing := addTestSchedule(t, "file-schedule")
+
+	writeScheduleFile(t, filepath.Join(dir, "scrub.toml"), testScheduleFile, time.Now())
+	scanScheduleDir(dir)
+
+	schedule, err := queryScheduleByName("file-schedule")
+	if err != nil || schedule.Id != existing.Id || schedule.Frequency != existing.Frequency {
+		t.Errorf("expected the schedule defined outside of the file to be kept, got %s", schedule.String())
+	}
+	if _, err := queryScheduleEventByName("file-event"); err == nil {
+		t.Error("expected the file to be skipped")
+	}
+}
+
+func TestReloadKeepsScheduleFileDefinitions(t *testing.T) {
+	resetScheduler()
+	dir, cleanup := useScheduleDir(t)
+	defer cleanup()
+
+	writeScheduleFile(t, filepath.Join(dir, "scrub.toml"), testScheduleFile, time.Now())
+	scanScheduleDir(dir)
+
+	//core-metadata knows nothing about the schedule files
+	if err := reloadSchedules(nil, nil); err != nil {
+		t.Fatalf("unexpected error reloading : %s", err.Error())
+	}
+	if _, err := queryScheduleByName("file-schedule"); err != nil {
+		t.Errorf("expected the schedule of the file to be kept : %s", err.Error())
+	}
+	if _, err := queryScheduleEventByName("file-event"); err != nil {
+		t.Errorf("expected the schedule event of the file to be kept : %s", err.Error())
+	}
+}
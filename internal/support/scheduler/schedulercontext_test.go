@@ -8,9 +8,11 @@
 package scheduler
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/edgexfoundry/edgex-go/pkg/clients/logging"
 	"github.com/edgexfoundry/edgex-go/pkg/models"
 )
 
@@ -154,6 +156,147 @@ func TestRet(t *testing.T) {
 
 }
 
+func TestResetFutureStart(t *testing.T) {
+	start := time.Now().Add(10 * time.Minute)
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Start:     start.UTC().Format(TIMELAYOUT),
+		Frequency: "PT1M",
+	}
+
+	testScheduleContext := ScheduleContext{}
+	testScheduleContext.Reset(testSchedule)
+
+	if testScheduleContext.NextTime.Unix() != start.Unix() {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, start)
+	}
+}
+
+func TestResetPastStart(t *testing.T) {
+	now := time.Now()
+	start := now.Add(-90 * time.Minute)
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Start:     start.UTC().Format(TIMELAYOUT),
+		Frequency: "PT1H",
+	}
+
+	testScheduleContext := ScheduleContext{}
+	testScheduleContext.Reset(testSchedule)
+
+	expected := start.Add(2 * time.Hour)
+	if testScheduleContext.NextTime.Unix() != expected.Unix() {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, expected)
+	}
+}
+
+func TestResetInvalidTimezone(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Frequency: TestScheduleFrequency,
+		Timezone:  "Mars/Olympus_Mons",
+	}
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(testSchedule); err == nil {
+		t.Error("expected an error for an unknown time zone")
+	}
+}
+
+func TestResetTimezoneStart(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Start:     "20180101T090000",
+		Frequency: TestScheduleFrequency,
+	}
+
+	testScheduleContext := ScheduleContext{}
+	testScheduleContext.Reset(testSchedule)
+	if testScheduleContext.Location != time.UTC {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.Location, time.UTC)
+	}
+	utcStart := testScheduleContext.StartTime
+
+	testSchedule.Timezone = "Asia/Shanghai"
+	testScheduleContext.Reset(testSchedule)
+	if utcStart.Sub(testScheduleContext.StartTime) != 8*time.Hour {
+		t.Errorf(TestUnexpectedMsgFormatStr, utcStart.Sub(testScheduleContext.StartTime), 8*time.Hour)
+	}
+}
+
+func TestCronAcrossDaylightSaving(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name:     TestScheduleName,
+		Cron:     "0 0 9 * * *",
+		Timezone: "America/New_York",
+	}
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(testSchedule); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	// daylight saving time starts in New York on 2019-03-10
+	testScheduleContext.NextTime = time.Date(2019, 3, 9, 9, 0, 0, 0, testScheduleContext.Location)
+	before := testScheduleContext.NextTime
+	testScheduleContext.UpdateNextTime()
+
+	next := testScheduleContext.NextTime.In(testScheduleContext.Location)
+	if next.Day() != 10 || next.Hour() != 9 || next.Minute() != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStr, next, "2019-03-10 09:00 EDT")
+	}
+	if next.Sub(before) != 23*time.Hour {
+		t.Errorf(TestUnexpectedMsgFormatStr, next.Sub(before), 23*time.Hour)
+	}
+}
+
+func TestJitterSpreadsFireTimes(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Start:     "20180101T000000",
+		Frequency: "PT60S",
+		JitterMs:  10000,
+	}
+
+	offsets := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		testScheduleContext := ScheduleContext{}
+		testScheduleContext.Reset(testSchedule)
+		boundary := testScheduleContext.NextTime.Add(testScheduleContext.Frequency)
+
+		testScheduleContext.UpdateNextTime()
+
+		offset := testScheduleContext.NextTime.Sub(boundary)
+		if offset < 0 || offset >= testScheduleContext.Jitter {
+			t.Fatalf(TestUnexpectedMsgFormatStr, offset, "an offset within the jitter window")
+		}
+		offsets[offset] = true
+	}
+
+	if len(offsets) < 50 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(offsets), 50)
+	}
+}
+
+func TestJitterRespectsEnd(t *testing.T) {
+	end := time.Now().Add(150 * time.Second).UTC()
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Start:     time.Now().UTC().Format(TIMELAYOUT),
+		End:       end.Format(TIMELAYOUT),
+		Frequency: "PT60S",
+		JitterMs:  3600000,
+	}
+
+	testScheduleContext := ScheduleContext{}
+	testScheduleContext.Reset(testSchedule)
+	testScheduleContext.UpdateNextTime()
+
+	if testScheduleContext.NextTime.After(testScheduleContext.EndTime) {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, testScheduleContext.EndTime)
+	}
+}
+
 func TestIsComplete(t *testing.T) {
 	testSchedule := models.Schedule{
 		Name:      TestScheduleName,
@@ -182,6 +325,88 @@ func TestIsComplete(t *testing.T) {
 	}
 }
 
+func TestCompletionReason(t *testing.T) {
+	_, restore := useFakeClock(time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC))
+	defer restore()
+
+	tests := []struct {
+		name       string
+		schedule   models.Schedule
+		iterations int64
+		nextTime   string
+		expected   string
+	}{
+		{"not complete", models.Schedule{Start: "20180101T000000", End: "20180110T000000", Frequency: "P1D", MaxIterations: 5}, 2, "20180102T000000", ""},
+		{"run once", models.Schedule{Start: "20180101T000000", RunOnce: true}, 0, "20180101T000000", CompletedRunOnce},
+		{"max iterations first", models.Schedule{Start: "20180101T000000", End: "20180110T000000", Frequency: "P1D", MaxIterations: 2}, 2, "20180103T000000", CompletedMaxIterations},
+		{"end first", models.Schedule{Start: "20180101T000000", End: "20180103T000000", Frequency: "P1D", MaxIterations: 5}, 3, "20180104T000000", CompletedEnd},
+		{"end and max iterations together", models.Schedule{Start: "20180101T000000", End: "20180103T000000", Frequency: "P1D", MaxIterations: 3}, 3, "20180104T000000", CompletedMaxIterations},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.schedule.Name = TestScheduleName
+			testScheduleContext := ScheduleContext{}
+			if err := testScheduleContext.Reset(tt.schedule); err != nil {
+				t.Fatalf("unexpected error resetting the schedule : %s", err.Error())
+			}
+			testScheduleContext.CurrentIterations = tt.iterations
+			testScheduleContext.NextTime, _ = time.ParseInLocation(TIMELAYOUT, tt.nextTime, testScheduleContext.Location)
+
+			if reason := testScheduleContext.CompletionReason(); reason != tt.expected {
+				t.Errorf(TestUnexpectedMsgFormatStr, reason, tt.expected)
+			}
+			if complete := testScheduleContext.IsComplete(); complete != (tt.expected != "") {
+				t.Errorf(TestUnexpectedMsgFormatStrForBoolVal, complete, tt.expected != "")
+			}
+		})
+	}
+}
+
+func TestResetEndBeforeStart(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Start:     "20180101T010101",
+		End:       "20170101T010101",
+		Frequency: TestScheduleFrequency,
+	}
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(testSchedule); err == nil {
+		t.Error("expected an error when the end time is before the start time")
+	}
+
+	testSchedule.End = "20190101T010101"
+	if err := testScheduleContext.Reset(testSchedule); err != nil {
+		t.Errorf("unexpected error : %s", err.Error())
+	}
+}
+
+func TestIsEnded(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Start:     "20180101T010101",
+		End:       "20180102T010101",
+		Frequency: TestScheduleFrequency,
+	}
+
+	testScheduleContext := ScheduleContext{}
+	testScheduleContext.Reset(testSchedule)
+
+	if !testScheduleContext.IsEnded() {
+		t.Errorf(TestUnexpectedMsgFormatStrForBoolVal, testScheduleContext.IsEnded(), true)
+	}
+	if !testScheduleContext.IsComplete() {
+		t.Errorf(TestUnexpectedMsgFormatStrForBoolVal, testScheduleContext.IsComplete(), true)
+	}
+
+	testSchedule.End = ""
+	testScheduleContext.Reset(testSchedule)
+
+	if testScheduleContext.IsEnded() {
+		t.Errorf(TestUnexpectedMsgFormatStrForBoolVal, testScheduleContext.IsEnded(), false)
+	}
+}
+
 func TestParseFrequency(t *testing.T) {
 	durationStr := "P1D"
 	duration := parseFrequency(durationStr)
@@ -205,3 +430,256 @@ func TestParseFrequency(t *testing.T) {
 		t.Errorf(TestUnexpectedMsgFormatStrForFloatVal, duration.Seconds(), 0.0)
 	}
 }
+
+func TestResetRejectsZeroFrequency(t *testing.T) {
+	for _, frequency := range []string{"", "PT0S", "bogus"} {
+		testSchedule := models.Schedule{
+			Name:      TestScheduleName,
+			Frequency: frequency,
+		}
+
+		testScheduleContext := ScheduleContext{}
+		if err := testScheduleContext.Reset(testSchedule); err == nil {
+			t.Errorf("expected an error for the frequency %q", frequency)
+		}
+	}
+
+	//run once and cron schedules do not need a frequency
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(models.Schedule{Name: TestScheduleName, RunOnce: true}); err != nil {
+		t.Errorf("unexpected error : %s", err.Error())
+	}
+	if err := testScheduleContext.Reset(models.Schedule{Name: TestScheduleName, Cron: "0 0 9 * * *"}); err != nil {
+		t.Errorf("unexpected error : %s", err.Error())
+	}
+}
+
+func TestResetClampsToMinInterval(t *testing.T) {
+	Configuration.MinIntervalMs = 5000
+	defer func() { Configuration.MinIntervalMs = 0 }()
+	logs := &captureLogger{}
+	LoggingClient = logs
+	defer func() { LoggingClient = logger.NewMockClient() }()
+
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Frequency: "PT1S",
+	}
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(testSchedule); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if testScheduleContext.Frequency != 5*time.Second {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.Frequency, 5*time.Second)
+	}
+	if len(logs.messages) != 1 || !strings.Contains(logs.messages[0], "below the minimum interval") {
+		t.Errorf("expected a warning for the clamped interval, got %v", logs.messages)
+	}
+
+	//intervals at or above the floor are left alone
+	testSchedule.Frequency = "PT10S"
+	testScheduleContext.Reset(testSchedule)
+	if testScheduleContext.Frequency != 10*time.Second {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.Frequency, 10*time.Second)
+	}
+}
+
+func TestResetRejectsBelowMinInterval(t *testing.T) {
+	Configuration.MinIntervalMs = 5000
+	Configuration.RejectBelowMinInterval = true
+	defer func() {
+		Configuration.MinIntervalMs = 0
+		Configuration.RejectBelowMinInterval = false
+	}()
+
+	testSchedule := models.Schedule{
+		Name:      TestScheduleName,
+		Frequency: "PT1S",
+	}
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(testSchedule); err == nil {
+		t.Error("expected an error for an interval below the minimum")
+	}
+}
+
+// counts the fires of a schedule which was down from its next fire time for five and a half intervals
+func countMissedFires(t *testing.T, policy string) int {
+	testSchedule := models.Schedule{
+		Name:             TestScheduleName,
+		Start:            "20180101T000000",
+		Frequency:        "PT1M",
+		MissedFirePolicy: policy,
+	}
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(testSchedule); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	base := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	testScheduleContext.NextTime = base
+	now := base.Add(5*time.Minute + 30*time.Second)
+
+	fires := 0
+	for i := 0; i < 100 && !testScheduleContext.NextTime.After(now); i++ {
+		if testScheduleContext.prepareFire(now) {
+			fires++
+			testScheduleContext.advanceAfterFire(now)
+		}
+		now = now.Add(time.Second)
+	}
+
+	if !testScheduleContext.NextTime.After(now) {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, "a next time after now")
+	}
+	if testScheduleContext.NextTime.Sub(base)%time.Minute != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, "a next time on an interval boundary")
+	}
+	return fires
+}
+
+func TestMissedFirePolicies(t *testing.T) {
+	policies := []struct {
+		policy string
+		fires  int
+	}{
+		{MissedFireSkip, 0},
+		{MissedFireOnce, 1},
+		{MissedFireCatchUp, 6},
+		{"", 6},
+	}
+
+	for _, p := range policies {
+		if fires := countMissedFires(t, p.policy); fires != p.fires {
+			t.Errorf("policy %s : "+TestUnexpectedMsgFormatStrForIntVal, p.policy, fires, p.fires)
+		}
+	}
+}
+
+func TestCatchUpIsBounded(t *testing.T) {
+	Configuration.MaxCatchUpFires = 2
+	defer func() { Configuration.MaxCatchUpFires = 0 }()
+
+	if fires := countMissedFires(t, MissedFireCatchUp); fires != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, fires, 2)
+	}
+}
+
+func TestMissedCronFires(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name: TestScheduleName,
+		Cron: "0 0 * * * *",
+	}
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(testSchedule); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	testScheduleContext.NextTime = time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	now := time.Date(2018, 1, 1, 3, 30, 0, 0, time.UTC)
+	if missed := testScheduleContext.missedFires(now); missed != 3 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, missed, 3)
+	}
+}
+
+func TestResetRejectsUnknownMissedFirePolicy(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name:             TestScheduleName,
+		Frequency:        "PT1M",
+		MissedFirePolicy: "replay",
+	}
+
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(testSchedule); err == nil {
+		t.Error("expected an error for an unknown missed fire policy")
+	}
+}
+
+func TestAlignToInterval(t *testing.T) {
+	testSchedule := models.Schedule{
+		Name:            TestScheduleName,
+		Frequency:       "PT15M",
+		AlignToInterval: true,
+	}
+
+	base := time.Date(2018, 1, 1, 10, 0, 0, 0, time.UTC)
+	for _, added := range []time.Duration{0, 7*time.Minute + 23*time.Second, 14 * time.Minute, 44*time.Minute + 59*time.Second} {
+		_, restore := useFakeClock(base.Add(added))
+
+		testScheduleContext := ScheduleContext{}
+		if err := testScheduleContext.Reset(testSchedule); err != nil {
+			t.Fatalf("unexpected error : %s", err.Error())
+		}
+		for i := 0; i < 3; i++ {
+			if testScheduleContext.NextTime.Sub(base)%(15*time.Minute) != 0 || !testScheduleContext.NextTime.After(base.Add(added)) {
+				t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, "a quarter hour after "+base.Add(added).String())
+			}
+			testScheduleContext.UpdateNextTime()
+		}
+		restore()
+	}
+
+	// the boundaries are taken in the time zone of the schedule
+	_, restore := useFakeClock(time.Date(2018, 1, 1, 5, 0, 0, 0, time.UTC))
+	defer restore()
+	testSchedule.Frequency = "P1D"
+	testSchedule.Timezone = "Asia/Shanghai"
+	testScheduleContext := ScheduleContext{}
+	testScheduleContext.Reset(testSchedule)
+
+	expected := time.Date(2018, 1, 2, 0, 0, 0, 0, testScheduleContext.Location)
+	if !testScheduleContext.NextTime.Equal(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, expected)
+	}
+}
+
+func TestUnalignedIntervalFollowsStart(t *testing.T) {
+	now := time.Date(2018, 1, 1, 10, 7, 0, 0, time.UTC)
+	_, restore := useFakeClock(now)
+	defer restore()
+
+	testScheduleContext := ScheduleContext{}
+	testScheduleContext.Reset(models.Schedule{Name: TestScheduleName, Frequency: "PT15M"})
+
+	if expected := now.Add(15 * time.Minute); !testScheduleContext.NextTime.Equal(expected) {
+		t.Errorf(TestUnexpectedMsgFormatStr, testScheduleContext.NextTime, expected)
+	}
+}
+
+func TestOneOffScheduleInThePast(t *testing.T) {
+	now := time.Date(2018, 1, 1, 12, 0, 0, 0, time.UTC)
+	_, restore := useFakeClock(now)
+	defer restore()
+
+	schedule := models.Schedule{Name: TestScheduleName, Start: now.Add(-time.Minute).Format(TIMELAYOUT)}
+	if err := validateOneOffStart(schedule); err == nil {
+		t.Error("expected an error for a one-off schedule in the past")
+	}
+
+	//a one-off which has fired still loads, it is completed
+	testScheduleContext := ScheduleContext{}
+	if err := testScheduleContext.Reset(schedule); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	if reason := testScheduleContext.CompletionReason(); reason != CompletedRunOnce {
+		t.Errorf(TestUnexpectedMsgFormatStr, reason, CompletedRunOnce)
+	}
+}
+
+func TestResetRejectsNegativeMaxExecution(t *testing.T) {
+	testScheduleContext := ScheduleContext{}
+	err := testScheduleContext.Reset(models.Schedule{Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D", MaxExecutionMs: -1})
+	if err == nil {
+		t.Error("expected an error for a negative max execution time")
+	}
+}
+
+func TestResetRejectsNegativeMaxIterations(t *testing.T) {
+	testScheduleContext := ScheduleContext{}
+	err := testScheduleContext.Reset(models.Schedule{Name: TestScheduleName, Start: "20180101T000000", Frequency: "P1D", MaxIterations: -1})
+	if err == nil {
+		t.Error("expected an error for negative max iterations")
+	}
+}
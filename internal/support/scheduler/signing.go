@@ -0,0 +1,39 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+)
+
+// the header carrying the signature of the event requests when SignatureHeader is not configured
+const DefaultSignatureHeader = "X-Signature"
+
+func signatureHeader() string {
+	if Configuration == nil || Configuration.SignatureHeader == "" {
+		return DefaultSignatureHeader
+	}
+	return Configuration.SignatureHeader
+}
+
+// Sign the body of an event request with the SigningSecret, a request without a body is signed over the empty
+// string. The receivers compute the HMAC-SHA256 of the body they got and compare it with the hex encoded
+// signature. Nothing is signed without a secret.
+func signRequest(req *http.Request, body []byte) {
+	if Configuration == nil || Configuration.SigningSecret == "" {
+		return
+	}
+	req.Header.Set(signatureHeader(), bodySignature(Configuration.SigningSecret, body))
+}
+
+func bodySignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,88 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+)
+
+const testSigningSecret = "s3cr3t"
+
+func expectedSignature(body string) string {
+	mac := hmac.New(sha256.New, []byte(testSigningSecret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestEventRequestIsSigned(t *testing.T) {
+	resetScheduler()
+	Configuration = &ConfigurationStruct{SigningSecret: testSigningSecret, SignatureHeader: "X-Scheduler-Signature"}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	const body = `{"age":86400}`
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodPost, "/api/v1/event/scrub", body)
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	req := client.requests[0]
+	if client.bodies[0] != body {
+		t.Fatalf(TestUnexpectedMsgFormatStr, client.bodies[0], body)
+	}
+	if signature := req.Header.Get("X-Scheduler-Signature"); signature != expectedSignature(body) {
+		t.Errorf(TestUnexpectedMsgFormatStr, signature, expectedSignature(body))
+	}
+	if signature := req.Header.Get(DefaultSignatureHeader); signature != "" {
+		t.Errorf("expected the signature in the configured header only, got %q", signature)
+	}
+}
+
+func TestEmptyBodyIsSigned(t *testing.T) {
+	resetScheduler()
+	Configuration = &ConfigurationStruct{SigningSecret: testSigningSecret}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	signature := client.requests[0].Header.Get(DefaultSignatureHeader)
+	if signature == "" || signature != expectedSignature("") {
+		t.Errorf(TestUnexpectedMsgFormatStr, signature, expectedSignature(""))
+	}
+}
+
+func TestEventRequestIsNotSignedWithoutSecret(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodPost, "/api/v1/event/scrub", `{"age":86400}`)
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+	if signature := client.requests[0].Header.Get(DefaultSignatureHeader); signature != "" {
+		t.Errorf("expected no signature, got %q", signature)
+	}
+}
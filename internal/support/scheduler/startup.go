@@ -0,0 +1,76 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Stagger the first fire of the schedules which are due within the startup window, so they do not all
+// fire on the first tick. Only the first fire moves, the following ones keep their usual times.
+func spreadStartupFires(now time.Time) {
+	window := startupSpread()
+	if window <= 0 {
+		return
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	var due []*ScheduleContext
+	for _, context := range scheduleIdToContextMap {
+		if !context.NextTime.After(now.Add(window)) {
+			due = append(due, context)
+		}
+	}
+	if len(due) < 2 {
+		return
+	}
+
+	//sorted so the spread does not depend on the map order
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Schedule.Id < due[j].Schedule.Id
+	})
+
+	step := window / time.Duration(len(due))
+	for i, context := range due {
+		fire := now.Add(time.Duration(i) * step)
+		if fire.Before(context.NextTime) {
+			continue
+		}
+		//a short interval caps the delay so no fire of the schedule is skipped
+		offset := fire.Sub(context.NextTime)
+		if context.cronSchedule == nil && context.Frequency > 0 && offset >= context.Frequency {
+			continue
+		}
+		if fire.After(context.EndTime) {
+			continue
+		}
+		context.jitterOffset += offset
+		context.NextTime = fire
+	}
+	markStateChanged()
+
+	LoggingClient.Info(fmt.Sprintf("spread the first fire of %d schedules across %s", len(due), window))
+}
+
+// the configured startup window, 0 when the first fires are not spread
+func startupSpread() time.Duration {
+	if Configuration == nil || Configuration.StartupSpreadMs <= 0 {
+		return 0
+	}
+	return time.Duration(Configuration.StartupSpreadMs) * time.Millisecond
+}
+
+// the configured delay before the ticker processes the schedules, 0 when it starts right away
+func startupDelay() time.Duration {
+	if Configuration == nil || Configuration.StartupDelayMs <= 0 {
+		return 0
+	}
+	return time.Duration(Configuration.StartupDelayMs) * time.Millisecond
+}
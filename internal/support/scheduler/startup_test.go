@@ -0,0 +1,149 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestSpreadStartupFires(t *testing.T) {
+	resetScheduler()
+	Configuration.StartupSpreadMs = 1000
+	defer func() { Configuration.StartupSpreadMs = 0 }()
+
+	for i := 0; i < 10; i++ {
+		schedule := models.Schedule{
+			Id:      bson.NewObjectId(),
+			Name:    fmt.Sprintf("%s-%d", TestScheduleName, i),
+			RunOnce: true,
+		}
+		if err := addSchedule(schedule); err != nil {
+			t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+		}
+	}
+
+	now := time.Now()
+	spreadStartupFires(now)
+
+	var fires []time.Time
+	for _, context := range scheduleIdToContextMap {
+		fires = append(fires, context.NextTime)
+	}
+	sort.Slice(fires, func(i, j int) bool { return fires[i].Before(fires[j]) })
+
+	for i := 1; i < len(fires); i++ {
+		if gap := fires[i].Sub(fires[i-1]); gap < 90*time.Millisecond {
+			t.Errorf("the fires %d and %d are only %s apart", i-1, i, gap)
+		}
+	}
+	if last := fires[len(fires)-1].Sub(now); last >= time.Second {
+		t.Errorf("the last fire is %s after the start, beyond the window", last)
+	}
+}
+
+func TestSpreadStartupFiresOnlyMovesTheFirstFire(t *testing.T) {
+	resetScheduler()
+	Configuration.StartupSpreadMs = 1000
+	defer func() { Configuration.StartupSpreadMs = 0 }()
+
+	var contexts []*ScheduleContext
+	for i := 0; i < 2; i++ {
+		schedule := addTestSchedule(t, fmt.Sprintf("%s-%d", TestScheduleName, i))
+		contexts = append(contexts, scheduleIdToContextMap[schedule.Id.Hex()])
+	}
+	//both are due now, on the same boundary
+	boundary := time.Now().Truncate(time.Second)
+	for _, context := range contexts {
+		context.NextTime = boundary
+	}
+
+	spreadStartupFires(boundary)
+
+	moved := 0
+	for _, context := range contexts {
+		if !context.NextTime.Equal(boundary) {
+			moved++
+		}
+		context.UpdateNextTime()
+		if expected := boundary.Add(24 * time.Hour); !context.NextTime.Equal(expected) {
+			t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, expected)
+		}
+	}
+	if moved != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, moved, 1)
+	}
+}
+
+func TestSpreadStartupFiresDisabled(t *testing.T) {
+	resetScheduler()
+	var contexts []*ScheduleContext
+	for i := 0; i < 3; i++ {
+		schedule := addTestSchedule(t, fmt.Sprintf("%s-%d", TestScheduleName, i))
+		contexts = append(contexts, scheduleIdToContextMap[schedule.Id.Hex()])
+	}
+	now := time.Now()
+	for _, context := range contexts {
+		context.NextTime = now
+	}
+
+	spreadStartupFires(now)
+
+	for _, context := range contexts {
+		if !context.NextTime.Equal(now) {
+			t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, now)
+		}
+	}
+}
+
+func TestStartupDelay(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2017, 12, 31, 23, 59, 59, 0, time.UTC))
+	defer restore()
+	Configuration.ScheduleInterval = 10
+	Configuration.StartupDelayMs = 300
+	defer func() {
+		Configuration.ScheduleInterval = 0
+		Configuration.StartupDelayMs = 0
+	}()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	fake.Advance(2 * time.Second)
+
+	requests := func() int {
+		client.mutex.Lock()
+		defer client.mutex.Unlock()
+		return len(client.requests)
+	}
+
+	StartTicker()
+	defer StopTicker()
+
+	//the schedule is due but the ticker holds off within the delay
+	time.Sleep(150 * time.Millisecond)
+	if count := requests(); count != 0 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, count, 0)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for requests() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if count := requests(); count != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, count, 1)
+	}
+	waitForExecutions()
+}
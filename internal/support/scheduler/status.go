@@ -0,0 +1,123 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// DefaultSuccessStatus is the status codes counted as a success when neither the event nor SuccessStatusCodes lists any
+const DefaultSuccessStatus = "200-299"
+
+// an inclusive range of status codes, a single code has the same first and last
+type statusRange struct {
+	first int
+	last  int
+}
+
+// Parse a comma separated list of status codes and ranges of status codes, e.g. 200-299,304
+func parseStatusCodes(spec string) ([]statusRange, error) {
+	var ranges []statusRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := parseStatusCode(bounds[0])
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = parseStatusCode(bounds[1]); err != nil {
+				return nil, err
+			}
+			if last < first {
+				return nil, fmt.Errorf("the status range %q ends before it starts", part)
+			}
+		}
+		ranges = append(ranges, statusRange{first: first, last: last})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("the status codes %q list no status code", spec)
+	}
+	return ranges, nil
+}
+
+func parseStatusCode(value string) (int, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || code < 100 || code > 599 {
+		return 0, fmt.Errorf("invalid status code %q, expected a number from 100 to 599", value)
+	}
+	return code, nil
+}
+
+// the success statuses of the event, falling back to the configured ones and then to 2xx.
+// Without statuses of its own, an event returning its redirects also succeeds with a 3xx.
+func successStatus(scheduleEvent models.ScheduleEvent) []statusRange {
+	if scheduleEvent.SuccessStatus != "" {
+		if ranges, err := parseStatusCodes(scheduleEvent.SuccessStatus); err == nil {
+			return ranges
+		}
+	}
+	ranges, _ := parseStatusCodes(DefaultSuccessStatus)
+	if Configuration != nil && Configuration.SuccessStatusCodes != "" {
+		configured, err := parseStatusCodes(Configuration.SuccessStatusCodes)
+		if err == nil {
+			ranges = configured
+		} else {
+			LoggingClient.Warn(fmt.Sprintf("invalid SuccessStatusCodes : %s, the default %s applies", err.Error(), DefaultSuccessStatus))
+		}
+	}
+	if scheduleEvent.NoRedirects {
+		ranges = append(ranges, statusRange{first: http.StatusMultipleChoices, last: http.StatusPermanentRedirect})
+	}
+	return ranges
+}
+
+func isSuccessStatus(scheduleEvent models.ScheduleEvent, statusCode int) bool {
+	for _, r := range successStatus(scheduleEvent) {
+		if statusCode >= r.first && statusCode <= r.last {
+			return true
+		}
+	}
+	return false
+}
+
+// a response with a status outside of the success statuses of the event fails it, while a server error listed
+// among them is a success
+func checkStatus(scheduleEvent models.ScheduleEvent, url string, statusCode int, err error) error {
+	switch err.(type) {
+	case nil:
+	case ErrServerResponse:
+		if isSuccessStatus(scheduleEvent, statusCode) {
+			return nil
+		}
+		return err
+	default:
+		return err
+	}
+	if !isSuccessStatus(scheduleEvent, statusCode) {
+		return ErrUnexpectedStatus{Url: url, StatusCode: statusCode}
+	}
+	return nil
+}
+
+func validateSuccessStatus(scheduleEvent models.ScheduleEvent) error {
+	if scheduleEvent.SuccessStatus == "" {
+		return nil
+	}
+	if _, err := parseStatusCodes(scheduleEvent.SuccessStatus); err != nil {
+		return fmt.Errorf("the schedule event %q has invalid success statuses %q : %s", scheduleEvent.Name, scheduleEvent.SuccessStatus, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+func TestNotFoundFailsUnderTheDefaultStatuses(t *testing.T) {
+	resetScheduler()
+	clearDeadLetters()
+	SetHTTPClient(&mockHTTPClient{statusCode: http.StatusNotFound})
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/missing", "")
+
+	err := executeSchedule(schedule.Id.Hex())
+	executionErr, ok := err.(ErrExecution)
+	if !ok || len(executionErr.Failures) != 1 {
+		t.Fatalf("expected the execution to fail its event, got %v", err)
+	}
+	if _, ok := executionErr.Failures[0].Err.(ErrUnexpectedStatus); !ok {
+		t.Errorf("expected an unexpected status, got %v", executionErr.Failures[0].Err)
+	}
+
+	lastRun, err := queryLastRun(scheduleEvent.Id.Hex())
+	if err != nil {
+		t.Fatalf("unexpected error querying the last run : %s", err.Error())
+	}
+	if lastRun.StatusCode != http.StatusNotFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, lastRun.StatusCode, http.StatusNotFound)
+	}
+	if lastRun.Error == "" {
+		t.Error("expected the last run to record the failure")
+	}
+	if deadLetters := queryDeadLetters(); len(deadLetters) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(deadLetters), 1)
+	}
+}
+
+func TestSuccessStatusOfTheEvent(t *testing.T) {
+	resetScheduler()
+	SetHTTPClient(&mockHTTPClient{statusCode: http.StatusNotFound})
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/missing", "")
+	scheduleEvent.SuccessStatus = "200-299, 404"
+
+	statusCode, err := executeScheduleEvent(context.Background(), scheduleEvent, "")
+	if err != nil {
+		t.Errorf("unexpected error for a listed status : %s", err.Error())
+	}
+	if statusCode != http.StatusNotFound {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, statusCode, http.StatusNotFound)
+	}
+
+	//the statuses of the event replace the default ones
+	SetHTTPClient(&mockHTTPClient{statusCode: http.StatusNoContent})
+	if _, err := executeScheduleEvent(context.Background(), scheduleEvent, ""); err != nil {
+		t.Errorf("unexpected error for a listed status : %s", err.Error())
+	}
+	scheduleEvent.SuccessStatus = "200"
+	if _, err := executeScheduleEvent(context.Background(), scheduleEvent, ""); err == nil {
+		t.Error("expected an error for a status the event does not list")
+	}
+}
+
+func TestConfiguredSuccessStatusCodes(t *testing.T) {
+	resetScheduler()
+	SetHTTPClient(&mockHTTPClient{statusCode: http.StatusServiceUnavailable})
+	defer SetHTTPClient(nil)
+	Configuration.SuccessStatusCodes = "200-299,503"
+	defer func() { Configuration.SuccessStatusCodes = "" }()
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	if _, err := executeScheduleEvent(context.Background(), scheduleEvent, ""); err != nil {
+		t.Errorf("unexpected error for a configured status : %s", err.Error())
+	}
+
+	SetHTTPClient(&mockHTTPClient{statusCode: http.StatusInternalServerError})
+	_, err := executeScheduleEvent(context.Background(), scheduleEvent, "")
+	if _, ok := err.(ErrServerResponse); !ok {
+		t.Errorf("expected a server error for a status outside of the configured ones, got %v", err)
+	}
+}
+
+func TestParseStatusCodes(t *testing.T) {
+	ranges, err := parseStatusCodes(" 200-299 ,304")
+	if err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	expected := []statusRange{{first: 200, last: 299}, {first: 304, last: 304}}
+	if len(ranges) != len(expected) || ranges[0] != expected[0] || ranges[1] != expected[1] {
+		t.Errorf("unexpected status ranges %v, expected %v", ranges, expected)
+	}
+
+	for _, invalid := range []string{"", ",", "ok", "99", "600", "299-200", "200-"} {
+		if _, err := parseStatusCodes(invalid); err == nil {
+			t.Errorf("expected an error parsing %q", invalid)
+		}
+	}
+}
+
+func TestValidateScheduleEventRejectsInvalidSuccessStatus(t *testing.T) {
+	scheduleEvent := models.ScheduleEvent{
+		Name:          TestScheduleEventName,
+		Schedule:      TestScheduleName,
+		SuccessStatus: "2xx",
+		Addressable: models.Addressable{
+			Protocol:   "http",
+			HTTPMethod: http.MethodGet,
+			Address:    "localhost",
+			Port:       48080,
+		},
+	}
+	if err := validateScheduleEvent(scheduleEvent); err == nil {
+		t.Error("expected an error for invalid success statuses")
+	}
+}
@@ -0,0 +1,148 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"github.com/satori/go.uuid"
+)
+
+// TriggerResult is the outcome of a schedule triggered on demand
+type TriggerResult struct {
+	ScheduleId string `json:"scheduleId"`
+	Name       string `json:"name"`
+	Error      string `json:"error,omitempty"`
+}
+
+// a schedule triggered on demand with the events and template data taken under the schedule mutex
+type triggerRun struct {
+	context        *ScheduleContext
+	schedule       models.Schedule
+	scheduleEvents []models.ScheduleEvent
+	templateData   TemplateData
+	traceParent    string
+	skipped        string // why the schedule is not run, empty when it is
+}
+
+// must be called with the schedule mutex held
+func indexScheduleTagsLocked(schedule models.Schedule) {
+	for _, tag := range schedule.Tags {
+		if tagToScheduleIdsMap[tag] == nil {
+			tagToScheduleIdsMap[tag] = make(map[string]bool)
+		}
+		tagToScheduleIdsMap[tag][schedule.Id.Hex()] = true
+	}
+}
+
+// must be called with the schedule mutex held
+func unindexScheduleTagsLocked(schedule models.Schedule) {
+	for _, tag := range schedule.Tags {
+		delete(tagToScheduleIdsMap[tag], schedule.Id.Hex())
+		if len(tagToScheduleIdsMap[tag]) == 0 {
+			delete(tagToScheduleIdsMap, tag)
+		}
+	}
+}
+
+// Execute the events of every schedule carrying the tag once, leaving their next fire times and iterations as they
+// are. The schedules which are already executing or are paused are skipped and reported as such, a run holds off
+// the fires of the ticker until it is over like any execution. The trigger is explicit so it is not bound by the
+// days and time of day a schedule fires in. The runs join the trace of the trigger when a traceparent is passed in.
+func triggerSchedulesByTag(tag string, traceParent string) ([]TriggerResult, error) {
+	//an on demand run is a fire of its own, at the time of the trigger
+	now := clock.Now()
+
+	mutex.Lock()
+	if draining {
+		mutex.Unlock()
+		return nil, ErrDraining{}
+	}
+	var runs []triggerRun
+	for scheduleId := range tagToScheduleIdsMap[tag] {
+		context, exists := scheduleIdToContextMap[scheduleId]
+		if !exists || context.MarkedDeleted {
+			continue
+		}
+		run := triggerRun{
+			context:        context,
+			schedule:       context.Schedule,
+			scheduleEvents: orderedScheduleEvents(context.ScheduleEventsMap),
+			templateData:   newTemplateData(context, now, now),
+			traceParent:    traceParent,
+		}
+		if context.Executing {
+			run.skipped = "the schedule is already executing"
+		} else if context.Paused {
+			run.skipped = "the schedule is paused"
+		} else {
+			context.Executing = true
+			context.triggered = true
+		}
+		runs = append(runs, run)
+	}
+	//the on demand runs are waited for by a drain like the fires of the ticker
+	executionsInFlight.Add(1)
+	defer executionsInFlight.Done()
+	mutex.Unlock()
+
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("scheduler could not find any schedule with tag : %s", tag)
+	}
+
+	results := make([]TriggerResult, len(runs))
+	var wg sync.WaitGroup
+	for i, run := range runs {
+		results[i] = TriggerResult{ScheduleId: run.schedule.Id.Hex(), Name: run.schedule.Name}
+		if run.skipped != "" {
+			results[i].Error = run.skipped
+			continue
+		}
+
+		wg.Add(1)
+		go func(run triggerRun, result *TriggerResult) {
+			defer wg.Done()
+			defer releaseTriggeredSchedule(run.context)
+			if err := triggerRunEvents(run); err != nil {
+				result.Error = err.Error()
+			}
+		}(run, &results[i])
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Name < results[j].Name })
+	return results, nil
+}
+
+// let the ticker fire the schedule again once its on demand run is over
+func releaseTriggeredSchedule(context *ScheduleContext) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	context.Executing = false
+	context.triggered = false
+	context.rescheduled = false
+}
+
+func triggerRunEvents(run triggerRun) (err error) {
+	correlationId := uuid.NewV4().String()
+
+	defer func() {
+		if r := recover(); r != nil {
+			LoggingClient.Error(executionLogMsg(correlationId, fmt.Sprintf("schedule execution error : %v", r)), correlationId)
+			err = fmt.Errorf("schedule execution error : %v", r)
+		}
+	}()
+
+	LoggingClient.Info(formatLogMsg(logFields{Msg: "triggering the schedule " + run.schedule.Name + " on demand", ScheduleId: run.schedule.Id.Hex(), CorrelationId: correlationId}), correlationId)
+	if executionErr := runScheduleEvents(run.schedule, run.scheduleEvents, run.templateData, correlationId, run.traceParent); executionErr.Failed() {
+		return executionErr
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func addTaggedTestSchedule(t *testing.T, name string, tags ...string) models.Schedule {
+	schedule := newTestSchedule(name)
+	schedule.Tags = tags
+	mustAddSchedule(t, schedule)
+	addTestScheduleEvent(t, schedule, name+"-event", http.MethodGet, "/api/v1/"+name, "")
+	return schedule
+}
+
+func TestTriggerSchedulesByTag(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	addTaggedTestSchedule(t, "cleanup", "nightly")
+	addTaggedTestSchedule(t, "export", "nightly", "reports")
+	addTaggedTestSchedule(t, "backup", "weekly", "nightly")
+	hourly := addTaggedTestSchedule(t, "poll", "hourly")
+	addTaggedTestSchedule(t, "untagged")
+
+	context := scheduleIdToContextMap[hourly.Id.Hex()]
+	nextTime, iterations := context.NextTime, context.CurrentIterations
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/schedule/trigger?tag=nightly", nil)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+	var results []TriggerResult
+	if err := json.NewDecoder(rec.Body).Decode(&results); err != nil {
+		t.Fatalf("unexpected error decoding the results : %s", err.Error())
+	}
+	if len(results) != 3 || results[0].Name != "backup" || results[1].Name != "cleanup" || results[2].Name != "export" {
+		t.Errorf(TestUnexpectedMsgFormatStr, results, "the backup, cleanup and export schedules")
+	}
+
+	var paths []string
+	for _, request := range client.requests {
+		paths = append(paths, request.URL.Path)
+	}
+	sort.Strings(paths)
+	expected := []string{"/api/v1/backup", "/api/v1/cleanup", "/api/v1/export"}
+	if len(paths) != len(expected) || paths[0] != expected[0] || paths[1] != expected[1] || paths[2] != expected[2] {
+		t.Errorf(TestUnexpectedMsgFormatStr, paths, expected)
+	}
+
+	//the cadence of the schedules is left alone
+	for _, context := range scheduleIdToContextMap {
+		if context.CurrentIterations != 0 {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, context.CurrentIterations, 0)
+		}
+	}
+	if !context.NextTime.Equal(nextTime) || context.CurrentIterations != iterations {
+		t.Errorf(TestUnexpectedMsgFormatStr, context.NextTime, nextTime)
+	}
+	if scheduleQueue.Length() != 5 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, scheduleQueue.Length(), 5)
+	}
+}
+
+func TestTriggeredScheduleDoesNotOverlapTheTicker(t *testing.T) {
+	resetScheduler()
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 30, 0, time.UTC))
+	defer restore()
+	client := &hangingHTTPClient{calls: make(map[string]int), path: "/api/v1/cleanup", release: make(chan struct{})}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTaggedTestSchedule(t, "cleanup", "nightly")
+	paused := addTaggedTestSchedule(t, "export", "nightly")
+	if err := pauseSchedule(paused.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error pausing the schedule : %s", err.Error())
+	}
+
+	triggered := make(chan []TriggerResult)
+	go func() {
+		results, _ := triggerSchedulesByTag("nightly", "")
+		triggered <- results
+	}()
+	deadline := time.Now().Add(2 * time.Second)
+	for !isExecuting(schedule.Id.Hex()) && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	//the fire due while the on demand run is in flight waits for it
+	fake.Advance(24 * time.Hour)
+	triggerSchedule()
+
+	close(client.release)
+	results := <-triggered
+	waitForExecutions()
+	if calls := client.calls["/api/v1/cleanup"]; calls != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, calls, 1)
+	}
+	if len(results) != 2 || results[0].Name != "cleanup" || results[0].Error != "" || results[1].Error != "the schedule is paused" {
+		t.Errorf("unexpected results %+v", results)
+	}
+	if client.calls["/api/v1/export"] != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, client.calls["/api/v1/export"], 0)
+	}
+
+	triggerSchedule()
+	waitForIterations(t, schedule.Id.Hex(), 1)
+	waitForExecutions()
+	if calls := client.calls["/api/v1/cleanup"]; calls != 2 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, calls, 2)
+	}
+}
+
+func isExecuting(scheduleId string) bool {
+	mutex.Lock()
+	defer mutex.Unlock()
+	return scheduleIdToContextMap[scheduleId].Executing
+}
+
+func TestTriggerSchedulesByUnknownTag(t *testing.T) {
+	resetScheduler()
+	addTaggedTestSchedule(t, "cleanup", "nightly")
+
+	for tag, code := range map[string]int{"": http.StatusBadRequest, "weekly": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/schedule/trigger?tag="+tag, nil)
+		rec := httptest.NewRecorder()
+		LoadRestRoutes().ServeHTTP(rec, req)
+
+		if rec.Code != code {
+			t.Errorf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, code)
+		}
+	}
+}
+
+func TestTagIndexFollowsTheSchedules(t *testing.T) {
+	resetScheduler()
+	schedule := addTaggedTestSchedule(t, "cleanup", "nightly")
+
+	schedule.Tags = []string{"weekly"}
+	if err := updateSchedule(schedule); err != nil {
+		t.Fatalf("unexpected error updating the schedule : %s", err.Error())
+	}
+	if _, exists := tagToScheduleIdsMap["nightly"]; exists {
+		t.Error("expected the former tag to be removed from the index")
+	}
+	if !tagToScheduleIdsMap["weekly"][schedule.Id.Hex()] {
+		t.Error("expected the new tag to be indexed")
+	}
+
+	removeSchedule(schedule.Id.Hex())
+	if len(tagToScheduleIdsMap) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(tagToScheduleIdsMap), 0)
+	}
+}
+
+func TestLoadCoreMetadataReplacesTheTags(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	scheduleClient, scheduleEventClient, _, restore := useFakeMetadataClients()
+	defer restore()
+	schedule := models.Schedule{Id: bson.NewObjectId(), Name: "cleanup", Start: "20180101T000000", Frequency: "P1D", Tags: []string{"old"}}
+	scheduleClient.schedules = []models.Schedule{schedule}
+	scheduleEventClient.scheduleEvents = []models.ScheduleEvent{{
+		Id:          bson.NewObjectId(),
+		Name:        "cleanup-event",
+		Schedule:    schedule.Name,
+		Service:     "core-data",
+		Addressable: models.Addressable{Protocol: "http", HTTPMethod: http.MethodGet, Address: "localhost", Port: 48080, Path: "/api/v1/cleanup"},
+	}}
+	if err := loadCoreMetadataInformation(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	//retagged in core-metadata, the next load drops the old tag
+	scheduleClient.schedules[0].Tags = []string{"new"}
+	if err := loadCoreMetadataInformation(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	if _, err := triggerSchedulesByTag("old", ""); err == nil {
+		t.Error("expected no schedule to carry the old tag")
+	}
+	results, err := triggerSchedulesByTag("new", "")
+	if err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	waitForExecutions()
+	if len(results) != 1 || results[0].Name != schedule.Name {
+		t.Errorf(TestUnexpectedMsgFormatStr, results, schedule.Name)
+	}
+	if len(client.requests) != 1 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+}
@@ -0,0 +1,96 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+)
+
+// TemplateData holds the only values which the path, the parameters, the query parameters and the headers of a schedule event can refer to
+type TemplateData struct {
+	Now          time.Time // start of the execution
+	FireTime     time.Time // time the schedule was due, without its jitter
+	Iteration    int64     // number of the fire, starting at 1
+	ScheduleName string
+}
+
+func newTemplateData(context *ScheduleContext, now time.Time, fireTime time.Time) TemplateData {
+	return TemplateData{
+		Now:          now,
+		FireTime:     fireTime,
+		Iteration:    context.CurrentIterations + 1,
+		ScheduleName: context.Schedule.Name,
+	}
+}
+
+// Resolve the templates in the path, the parameters, the query parameters and the headers of a copy of the schedule event
+func renderScheduleEvent(scheduleEvent models.ScheduleEvent, data TemplateData) (models.ScheduleEvent, error) {
+	path, err := renderTemplate(scheduleEvent.Name+" path", scheduleEvent.Addressable.Path, data)
+	if err != nil {
+		return scheduleEvent, err
+	}
+	parameters, err := renderTemplate(scheduleEvent.Name+" parameters", scheduleEvent.Parameters, data)
+	if err != nil {
+		return scheduleEvent, err
+	}
+
+	queryParams, err := renderTemplateMap(scheduleEvent.Name+" query parameter ", scheduleEvent.QueryParams, data)
+	if err != nil {
+		return scheduleEvent, err
+	}
+	headers, err := renderTemplateMap(scheduleEvent.Name+" header ", scheduleEvent.Headers, data)
+	if err != nil {
+		return scheduleEvent, err
+	}
+
+	scheduleEvent.Addressable.Path = path
+	scheduleEvent.Parameters = parameters
+	scheduleEvent.QueryParams = queryParams
+	scheduleEvent.Headers = headers
+	return scheduleEvent, nil
+}
+
+// The values are rendered into a new map, the event in the schedule context shares the original
+func renderTemplateMap(namePrefix string, values map[string]string, data TemplateData) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	rendered := make(map[string]string, len(values))
+	for key, value := range values {
+		result, err := renderTemplate(namePrefix+key, value, data)
+		if err != nil {
+			return nil, err
+		}
+		rendered[key] = result
+	}
+	return rendered, nil
+}
+
+// strings without an action are returned as they are
+func renderTemplate(name string, text string, data TemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+
+	//missing keys are errors rather than an empty value in the request
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template in the schedule event %s : %s", name, err.Error())
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil {
+		return "", fmt.Errorf("unable to resolve the template in the schedule event %s : %s", name, err.Error())
+	}
+	return buffer.String(), nil
+}
@@ -0,0 +1,131 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestExecuteResolvesIterationTemplate(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodPost, "/api/v1/count/{{.Iteration}}", `{"schedule":"{{.ScheduleName}}"}`)
+
+	for i := 0; i < 3; i++ {
+		executeSchedule(schedule.Id.Hex())
+	}
+
+	if len(client.requests) != 3 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 3)
+	}
+	for i, req := range client.requests {
+		expected := "/api/v1/count/" + strconv.Itoa(i+1)
+		if req.URL.Path != expected {
+			t.Errorf(TestUnexpectedMsgFormatStr, req.URL.Path, expected)
+		}
+		if client.bodies[i] != fmt.Sprintf(`{"schedule":"%s"}`, TestScheduleName) {
+			t.Errorf(TestUnexpectedMsgFormatStr, client.bodies[i], fmt.Sprintf(`{"schedule":"%s"}`, TestScheduleName))
+		}
+	}
+
+	//the stored event keeps its template
+	stored, _ := queryScheduleEvent(scheduleEvent.Id.Hex())
+	if stored.Addressable.Path != "/api/v1/count/{{.Iteration}}" {
+		t.Errorf(TestUnexpectedMsgFormatStr, stored.Addressable.Path, "/api/v1/count/{{.Iteration}}")
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	now := time.Date(2018, 10, 16, 9, 0, 0, 0, time.UTC)
+	data := TemplateData{Now: now, Iteration: 7, ScheduleName: TestScheduleName}
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"/api/v1/ping", "/api/v1/ping"},
+		{"/api/v1/{{.Iteration}}", "/api/v1/7"},
+		{"/api/v1/{{.Now.Unix}}", "/api/v1/" + strconv.FormatInt(now.Unix(), 10)},
+		{`{"name":"{{.ScheduleName}}"}`, `{"name":"` + TestScheduleName + `"}`},
+	}
+	for _, tt := range tests {
+		result, err := renderTemplate(TestScheduleEventName, tt.text, data)
+		if err != nil {
+			t.Errorf("unexpected error for %q : %s", tt.text, err.Error())
+			continue
+		}
+		if result != tt.expected {
+			t.Errorf(TestUnexpectedMsgFormatStr, result, tt.expected)
+		}
+	}
+
+	//only the template data can be referred to
+	for _, text := range []string{"{{.Configuration}}", "{{.Iteration", "{{template \"other\"}}"} {
+		if _, err := renderTemplate(TestScheduleEventName, text, data); err == nil {
+			t.Errorf("expected an error for %q", text)
+		}
+	}
+}
+
+func TestExecuteInvalidTemplateFailsTheEvent(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/{{.Secret}}", "")
+
+	if err := executeSchedule(schedule.Id.Hex()); err == nil {
+		t.Error("expected an error for the unknown template field")
+	}
+	if len(client.requests) != 0 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 0)
+	}
+}
+
+func TestExecuteResolvesHeaderTemplate(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	scheduleEvent := addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+	scheduleEvent.Headers = map[string]string{"X-Ts": "{{.Now.Unix}}", "X-Schedule": "{{.ScheduleName}}"}
+	if err := updateScheduleEvent(scheduleEvent); err != nil {
+		t.Fatalf("unexpected error updating the schedule event : %s", err.Error())
+	}
+
+	before := time.Now().Unix()
+	executeSchedule(schedule.Id.Hex())
+	after := time.Now().Unix()
+
+	if len(client.requests) != 1 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 1)
+	}
+	ts, err := strconv.ParseInt(client.requests[0].Header.Get("X-Ts"), 10, 64)
+	if err != nil || ts < before || ts > after {
+		t.Errorf("expected a timestamp between %d and %d, got %q", before, after, client.requests[0].Header.Get("X-Ts"))
+	}
+	if header := client.requests[0].Header.Get("X-Schedule"); header != TestScheduleName {
+		t.Errorf(TestUnexpectedMsgFormatStr, header, TestScheduleName)
+	}
+
+	//the stored event keeps its template
+	stored, _ := queryScheduleEvent(scheduleEvent.Id.Hex())
+	if stored.Headers["X-Ts"] != "{{.Now.Unix}}" {
+		t.Errorf(TestUnexpectedMsgFormatStr, stored.Headers["X-Ts"], "{{.Now.Unix}}")
+	}
+}
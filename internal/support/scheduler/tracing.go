@@ -0,0 +1,120 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Tracer starts a span for every execution of a schedule. An OpenTelemetry tracer can be plugged in with SetTracer,
+// without one the executions are not traced unless EnableTracing is set.
+type Tracer interface {
+	// StartSpan starts a span as a child of the W3C traceparent passed in, a new trace is started when it is empty
+	StartSpan(name string, parent string) Span
+}
+
+// Span is the span of one execution
+type Span interface {
+	// TraceParent is the W3C traceparent propagated on the event requests, nothing is propagated when it is empty
+	TraceParent() string
+	End(err error)
+}
+
+// the tracer used for the executions, the default one is picked from the configuration when it is not set
+var tracer Tracer
+
+// SetTracer replaces the tracer of the executions, a nil tracer restores the default one
+func SetTracer(t Tracer) {
+	tracer = t
+}
+
+func getTracer() Tracer {
+	if tracer != nil {
+		return tracer
+	}
+	if Configuration != nil && Configuration.EnableTracing {
+		return w3cTracer{}
+	}
+	return noopTracer{}
+}
+
+type noopTracer struct{}
+
+type noopSpan struct{}
+
+func (noopTracer) StartSpan(name string, parent string) Span { return noopSpan{} }
+
+func (noopSpan) TraceParent() string { return "" }
+
+func (noopSpan) End(err error) {}
+
+// version-traceid-spanid-flags, see https://www.w3.org/TR/trace-context/#traceparent-header
+var traceParentPattern = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+
+const (
+	traceParentVersion = "00"
+	sampledFlag        = "01"
+	invalidTraceId     = "00000000000000000000000000000000"
+	invalidSpanId      = "0000000000000000"
+)
+
+// The tracer generating the W3C traceparent of the executions itself, the spans are only logged
+type w3cTracer struct{}
+
+type w3cSpan struct {
+	name    string
+	traceId string
+	spanId  string
+	flags   string
+	start   time.Time
+}
+
+func (w3cTracer) StartSpan(name string, parent string) Span {
+	span := &w3cSpan{name: name, flags: sampledFlag, start: clock.Now()}
+	if traceId, flags, ok := parseTraceParent(parent); ok {
+		span.traceId = traceId
+		span.flags = flags
+	} else {
+		span.traceId = randomHex(16)
+	}
+	span.spanId = randomHex(8)
+	return span
+}
+
+func (s *w3cSpan) TraceParent() string {
+	return fmt.Sprintf("%s-%s-%s-%s", traceParentVersion, s.traceId, s.spanId, s.flags)
+}
+
+func (s *w3cSpan) End(err error) {
+	msg := fmt.Sprintf("span %s of trace %s for %s ended after %s", s.spanId, s.traceId, s.name, clock.Now().Sub(s.start))
+	if err != nil {
+		msg += " with error : " + err.Error()
+	}
+	LoggingClient.Debug(msg)
+}
+
+// Return the trace id and flags of a W3C traceparent, the malformed ones and the invalid ids are rejected so a new
+// trace is started instead
+func parseTraceParent(traceParent string) (string, string, bool) {
+	parts := traceParentPattern.FindStringSubmatch(traceParent)
+	if parts == nil || parts[1] == "ff" || parts[2] == invalidTraceId || parts[3] == invalidSpanId {
+		return "", "", false
+	}
+	return parts[2], parts[4], true
+}
+
+func randomHex(size int) string {
+	id := make([]byte, size)
+	if _, err := rand.Read(id); err != nil {
+		//the source of randomness failing is not a reason to fail the execution
+		return fmt.Sprintf("%0*x", size*2, time.Now().UnixNano())
+	}
+	return hex.EncodeToString(id)
+}
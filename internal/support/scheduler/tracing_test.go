@@ -0,0 +1,166 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var wellFormedTraceParent = regexp.MustCompile(`^00-[0-9a-f]{32}-[0-9a-f]{16}-0[01]$`)
+
+type recordingTracer struct {
+	parents []string
+	ended   []error
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+}
+
+func (t *recordingTracer) StartSpan(name string, parent string) Span {
+	t.parents = append(t.parents, parent)
+	return recordingSpan{tracer: t}
+}
+
+func (s recordingSpan) TraceParent() string {
+	return "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+}
+
+func (s recordingSpan) End(err error) {
+	s.tracer.ended = append(s.tracer.ended, err)
+}
+
+func TestExecuteSendsTraceParentWhenTracingEnabled(t *testing.T) {
+	resetScheduler()
+	Configuration = &ConfigurationStruct{EnableTracing: true}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/first", "")
+	addTestScheduleEvent(t, schedule, TestScheduleEventName+"-2", http.MethodGet, "/api/v1/second", "")
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	if len(client.requests) != 2 {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, len(client.requests), 2)
+	}
+	traceParent := client.requests[0].Header.Get(TraceParentHeader)
+	if !wellFormedTraceParent.MatchString(traceParent) {
+		t.Fatalf("expected a well formed traceparent header, got %q", traceParent)
+	}
+	//the events of an execution share its span
+	if second := client.requests[1].Header.Get(TraceParentHeader); second != traceParent {
+		t.Errorf(TestUnexpectedMsgFormatStr, second, traceParent)
+	}
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+	if next := client.requests[2].Header.Get(TraceParentHeader); next[3:35] == traceParent[3:35] {
+		t.Errorf("expected every execution to start a trace of its own, got %s twice", traceParent[3:35])
+	}
+}
+
+func TestExecuteWithoutTracing(t *testing.T) {
+	resetScheduler()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/event", "")
+
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+	if traceParent := client.requests[0].Header.Get(TraceParentHeader); traceParent != "" {
+		t.Errorf("expected no traceparent header, got %q", traceParent)
+	}
+}
+
+func TestTriggerPropagatesTraceParent(t *testing.T) {
+	resetScheduler()
+	Configuration = &ConfigurationStruct{EnableTracing: true}
+	defer func() { Configuration = &ConfigurationStruct{} }()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+	addTaggedTestSchedule(t, "cleanup", "nightly")
+
+	const incoming = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/schedule/trigger?tag=nightly", nil)
+	req.Header.Set(TraceParentHeader, incoming)
+	rec := httptest.NewRecorder()
+	LoadRestRoutes().ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf(TestUnexpectedMsgFormatStrForIntVal, rec.Code, http.StatusOK)
+	}
+
+	//the trace of the trigger is kept, the execution is a span of its own
+	traceParent := client.requests[0].Header.Get(TraceParentHeader)
+	if !wellFormedTraceParent.MatchString(traceParent) || !strings.HasPrefix(traceParent, incoming[:36]) {
+		t.Errorf("expected a span of the trace 4bf92f3577b34da6a3ce929d0e0e4736, got %q", traceParent)
+	}
+	if traceParent == incoming {
+		t.Error("expected the execution to have a span id of its own")
+	}
+}
+
+func TestParseTraceParent(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceParent string
+		valid       bool
+	}{
+		{"valid", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		{"empty", "", false},
+		{"upper case", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01", false},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"zero span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"short trace id", "00-4bf92f3577b34da6-00f067aa0ba902b7-01", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, valid := parseTraceParent(tt.traceParent); valid != tt.valid {
+				t.Errorf("expected %s to be valid : %t, got %t", tt.traceParent, tt.valid, valid)
+			}
+		})
+	}
+}
+
+func TestSetTracer(t *testing.T) {
+	resetScheduler()
+	custom := &recordingTracer{}
+	SetTracer(custom)
+	defer SetTracer(nil)
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := addTestSchedule(t, TestScheduleName)
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/event", "")
+	if err := executeSchedule(schedule.Id.Hex()); err != nil {
+		t.Fatalf("unexpected error executing the schedule : %s", err.Error())
+	}
+
+	if len(custom.parents) != 1 || custom.parents[0] != "" || len(custom.ended) != 1 || custom.ended[0] != nil {
+		t.Errorf("expected one span started and ended without error, got %v and %v", custom.parents, custom.ended)
+	}
+	if traceParent := client.requests[0].Header.Get(TraceParentHeader); traceParent != (recordingSpan{}).TraceParent() {
+		t.Errorf(TestUnexpectedMsgFormatStr, traceParent, (recordingSpan{}).TraceParent())
+	}
+}
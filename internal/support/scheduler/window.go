@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// the days and the time of day a schedule is allowed to fire in, the fires outside of it are skipped
+type fireWindow struct {
+	days  [7]bool
+	start time.Duration // since midnight
+	end   time.Duration // since midnight, before the start when the window wraps midnight
+}
+
+// Parse the allowed window of the schedule, nil when it has none
+func (sc *ScheduleContext) resetWindow() error {
+	sc.window = nil
+	schedule := sc.Schedule
+	if schedule.WindowDays == "" && schedule.WindowStart == "" && schedule.WindowEnd == "" {
+		return nil
+	}
+	if (schedule.WindowStart == "") != (schedule.WindowEnd == "") {
+		return fmt.Errorf("the schedule %s needs both a window start and a window end", schedule.Name)
+	}
+
+	window := &fireWindow{}
+	if err := window.parseDays(schedule.WindowDays); err != nil {
+		return fmt.Errorf("the schedule %s has invalid window days %q : %s", schedule.Name, schedule.WindowDays, err.Error())
+	}
+	if schedule.WindowStart != "" {
+		var err error
+		if window.start, err = parseTimeOfDay(schedule.WindowStart); err != nil {
+			return fmt.Errorf("the schedule %s has an invalid window start : %s", schedule.Name, err.Error())
+		}
+		if window.end, err = parseTimeOfDay(schedule.WindowEnd); err != nil {
+			return fmt.Errorf("the schedule %s has an invalid window end : %s", schedule.Name, err.Error())
+		}
+		if window.start == window.end {
+			return fmt.Errorf("the schedule %s has an empty window from %s to %s", schedule.Name, schedule.WindowStart, schedule.WindowEnd)
+		}
+	}
+	sc.window = window
+	return nil
+}
+
+// Parse a comma separated list of days and ranges of days, e.g. mon-fri or sat,sun. A range may wrap the end of the
+// week like fri-mon. Empty is every day.
+func (w *fireWindow) parseDays(spec string) error {
+	if strings.TrimSpace(spec) == "" {
+		for day := range w.days {
+			w.days[day] = true
+		}
+		return nil
+	}
+	for _, part := range strings.Split(spec, ",") {
+		bounds := strings.SplitN(part, "-", 2)
+		first, err := parseWeekday(bounds[0])
+		if err != nil {
+			return err
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = parseWeekday(bounds[1]); err != nil {
+				return err
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			w.days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func parseWeekday(value string) (time.Weekday, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	//the full names are accepted as well
+	if len(name) > 3 {
+		if day, ok := weekdays[name[:3]]; ok && name == strings.ToLower(day.String()) {
+			return day, nil
+		}
+	}
+	day, ok := weekdays[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown day %q, expected one of mon, tue, wed, thu, fri, sat or sun", strings.TrimSpace(value))
+	}
+	return day, nil
+}
+
+// parse a HH:MM time of day into the time since midnight
+func parseTimeOfDay(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Whether the time, in the time zone of the schedule, is within the window. The window runs from its start up to
+// its end, a window wrapping midnight belongs to the day it starts on.
+func (w *fireWindow) contains(t time.Time) bool {
+	day := t.Weekday()
+	if w.start == w.end {
+		return w.days[day]
+	}
+	offset := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second
+	if w.start < w.end {
+		return w.days[day] && offset >= w.start && offset < w.end
+	}
+	if offset >= w.start {
+		return w.days[day]
+	}
+	return offset < w.end && w.days[(day+6)%7]
+}
+
+// Whether the schedule may fire at its next time, a schedule without a window always does
+func (sc *ScheduleContext) inWindow() bool {
+	if sc.window == nil {
+		return true
+	}
+	return sc.window.contains(sc.NextTime.In(sc.Location))
+}
@@ -0,0 +1,130 @@
+//
+// Copyright (c) 2018 Dell Inc.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package scheduler
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/edgexfoundry/edgex-go/pkg/models"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestWindowSuppressesFiresOutsideBusinessHours(t *testing.T) {
+	resetScheduler()
+	//the 1st of January 2018 is a Monday
+	fake, restore := useFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer restore()
+	client := &mockHTTPClient{}
+	SetHTTPClient(client)
+	defer SetHTTPClient(nil)
+
+	schedule := models.Schedule{
+		Id:          bson.NewObjectId(),
+		Name:        TestScheduleName,
+		Start:       "20180101T000000",
+		Frequency:   "PT1H",
+		WindowDays:  "mon-fri",
+		WindowStart: "09:00",
+		WindowEnd:   "17:00",
+	}
+	if err := addSchedule(schedule); err != nil {
+		t.Fatalf("unexpected error adding the schedule : %s", err.Error())
+	}
+	addTestScheduleEvent(t, schedule, TestScheduleEventName, http.MethodGet, "/api/v1/ping", "")
+
+	fired := 0
+	for hour := 1; hour <= 7*24; hour++ {
+		fake.Advance(time.Hour)
+		now := fake.Now()
+		before := len(client.requests)
+
+		triggerSchedule()
+		waitForExecutions()
+
+		inWindow := now.Weekday() != time.Saturday && now.Weekday() != time.Sunday && now.Hour() >= 9 && now.Hour() < 17
+		sent := len(client.requests) - before
+		if inWindow && sent != 1 {
+			t.Errorf("expected a fire at %s", now)
+		} else if !inWindow && sent != 0 {
+			t.Errorf("unexpected fire outside of the window at %s", now)
+		}
+		fired += sent
+
+		//a skipped fire still moves the schedule to its next time
+		if next := scheduleIdToContextMap[schedule.Id.Hex()].NextTime; !next.Equal(now.Add(time.Hour)) {
+			t.Fatalf(TestUnexpectedMsgFormatStr, next, now.Add(time.Hour))
+		}
+	}
+	if fired != 5*8 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, fired, 5*8)
+	}
+	if iterations := scheduleIdToContextMap[schedule.Id.Hex()].CurrentIterations; iterations != 5*8 {
+		t.Errorf(TestUnexpectedMsgFormatStrForIntVal, iterations, 5*8)
+	}
+}
+
+func TestWindowWrapsMidnight(t *testing.T) {
+	context := ScheduleContext{Schedule: models.Schedule{Name: TestScheduleName, WindowDays: "fri", WindowStart: "22:00", WindowEnd: "06:00"}}
+	if err := context.resetWindow(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+
+	//the 5th of January 2018 is a Friday, the window it opens runs into the Saturday
+	cases := []struct {
+		time     time.Time
+		expected bool
+	}{
+		{time.Date(2018, 1, 5, 21, 59, 0, 0, time.UTC), false},
+		{time.Date(2018, 1, 5, 22, 0, 0, 0, time.UTC), true},
+		{time.Date(2018, 1, 5, 23, 30, 0, 0, time.UTC), true},
+		{time.Date(2018, 1, 6, 2, 0, 0, 0, time.UTC), true},
+		{time.Date(2018, 1, 6, 6, 0, 0, 0, time.UTC), false},
+		{time.Date(2018, 1, 6, 23, 0, 0, 0, time.UTC), false},
+		{time.Date(2018, 1, 5, 2, 0, 0, 0, time.UTC), false},
+	}
+	for _, c := range cases {
+		if inWindow := context.window.contains(c.time); inWindow != c.expected {
+			t.Errorf("unexpected %t for %s", inWindow, c.time)
+		}
+	}
+}
+
+func TestWindowDays(t *testing.T) {
+	context := ScheduleContext{Schedule: models.Schedule{Name: TestScheduleName, WindowDays: "Saturday-mon, wed"}}
+	if err := context.resetWindow(); err != nil {
+		t.Fatalf("unexpected error : %s", err.Error())
+	}
+	expected := [7]bool{time.Sunday: true, time.Monday: true, time.Wednesday: true, time.Saturday: true}
+	if context.window.days != expected {
+		t.Errorf("unexpected days %v, expected %v", context.window.days, expected)
+	}
+	//a window of days only covers them whole
+	if !context.window.contains(time.Date(2018, 1, 3, 23, 59, 0, 0, time.UTC)) {
+		t.Error("expected the whole Wednesday to be in the window")
+	}
+}
+
+func TestInvalidWindow(t *testing.T) {
+	windows := []models.Schedule{
+		{WindowDays: "funday"},
+		{WindowDays: "monxyz"},
+		{WindowStart: "09:00"},
+		{WindowStart: "09:00", WindowEnd: "25:00"},
+		{WindowStart: "9am", WindowEnd: "17:00"},
+		{WindowStart: "09:00", WindowEnd: "09:00"},
+	}
+	for _, window := range windows {
+		window.Name = TestScheduleName
+		window.Start = "20180101T000000"
+		window.Frequency = "PT1H"
+		context := ScheduleContext{ScheduleEventsMap: make(map[string]models.ScheduleEvent)}
+		if err := context.Reset(window); err == nil {
+			t.Errorf("expected an error for the window %q %q-%q", window.WindowDays, window.WindowStart, window.WindowEnd)
+		}
+	}
+}
@@ -21,31 +21,68 @@ import (
 )
 
 type Schedule struct {
-	BaseObject `bson:",inline"`
-	Id         bson.ObjectId `bson:"_id,omitempty" json:"id"`
-	Name       string        `bson:"name" json:"name"`           // non-database identifier for a shcedule (*must be quitue)
-	Start      string        `bson:"start" json:"start"`         // Start time in ISO 8601 format YYYYMMDD'T'HHmmss 	@JsonFormat(shape = JsonFormat.Shape.STRING, pattern = "yyyymmdd'T'HHmmss")
-	End        string        `bson:"end" json:"end"`             // Start time in ISO 8601 format YYYYMMDD'T'HHmmss 	@JsonFormat(shape = JsonFormat.Shape.STRING, pattern = "yyyymmdd'T'HHmmss")
-	Frequency  string        `bson:"frequency" json:"frequency"` // how frequently should the event occur according ISO 8601
-	Cron       string        `bson:"cron" json:"cron"`           // cron styled regular expression indicating how often the action under schedule should occur.  Use either runOnce, frequency or cron and not all.
-	RunOnce    bool          `bson:"runOnce" json:"runOnce"`     // boolean indicating that this schedules runs one time - at the time indicated by the start
+	BaseObject       `bson:",inline"`
+	Id               bson.ObjectId `bson:"_id,omitempty" json:"id"`
+	Name             string        `bson:"name" json:"name"`                         // non-database identifier for a shcedule (*must be quitue)
+	Start            string        `bson:"start" json:"start"`                       // Start time in ISO 8601 format YYYYMMDD'T'HHmmss 	@JsonFormat(shape = JsonFormat.Shape.STRING, pattern = "yyyymmdd'T'HHmmss")
+	End              string        `bson:"end" json:"end"`                           // Start time in ISO 8601 format YYYYMMDD'T'HHmmss 	@JsonFormat(shape = JsonFormat.Shape.STRING, pattern = "yyyymmdd'T'HHmmss")
+	Frequency        string        `bson:"frequency" json:"frequency"`               // how frequently should the event occur according ISO 8601
+	Cron             string        `bson:"cron" json:"cron"`                         // cron styled regular expression indicating how often the action under schedule should occur.  Use either runOnce, frequency or cron and not all.
+	RunOnce          bool          `bson:"runOnce" json:"runOnce"`                   // boolean indicating that this schedules runs one time - at the time indicated by the start
+	Timezone         string        `bson:"timezone" json:"timezone"`                 // IANA time zone name used to interpret start, end and cron (defaults to UTC)
+	JitterMs         int           `bson:"jitterMs" json:"jitterMs"`                 // window in milliseconds of the random delay added to each fire time
+	MissedFirePolicy string        `bson:"missedFirePolicy" json:"missedFirePolicy"` // skip, fire-once or catch-up (default), what to do with the fires missed while the scheduler was down
+	AlignToInterval  bool          `bson:"alignToInterval" json:"alignToInterval"`   // fire on the multiples of the frequency since the epoch in the time zone instead of since the start
+	Tags             []string      `bson:"tags" json:"tags"`                         // groups the schedule can be triggered with
+	MaxExecutionMs   int           `bson:"maxExecutionMs" json:"maxExecutionMs"`     // bound in milliseconds on the time an execution of all the events takes, 0 is unbounded
+	MaxIterations    int           `bson:"maxIterations" json:"maxIterations"`       // number of fires after which the schedule is complete, 0 is unbounded
+	IntervalPolicy   string        `bson:"intervalPolicy" json:"intervalPolicy"`     // constant (default) or multiplicative, how the interval changes after each fire
+	IntervalFactor   float64       `bson:"intervalFactor" json:"intervalFactor"`     // factor the interval is multiplied by after each fire under the multiplicative policy
+	IntervalLimit    string        `bson:"intervalLimit" json:"intervalLimit"`       // ISO 8601 duration the interval stops at, the largest when it grows and the smallest when it shrinks
+	ResetInterval    bool          `bson:"resetInterval" json:"resetInterval"`       // go back to the frequency once an execution succeeds with a response body
+	WindowDays       string        `bson:"windowDays" json:"windowDays"`             // days of the week the schedule fires on, e.g. mon-fri or sat,sun, empty is every day
+	WindowStart      string        `bson:"windowStart" json:"windowStart"`           // HH:MM time of day the fires are allowed from, in the time zone
+	WindowEnd        string        `bson:"windowEnd" json:"windowEnd"`               // HH:MM time of day the fires are allowed up to, before the start when the window wraps midnight
+	Originator       string        `bson:"originator" json:"originator"`             // id of the scheduler instance which added the schedule to core-metadata
 }
 
 // Custom marshaling to make empty strings null
 func (s Schedule) MarshalJSON() ([]byte, error) {
 	test := struct {
 		BaseObject
-		Id        bson.ObjectId `json:"id"`
-		Name      *string       `json:"name"`      // non-database identifier for a shcedule (*must be quitue)
-		Start     *string       `json:"start"`     // Start time in ISO 8601 format YYYYMMDD'T'HHmmss 	@JsonFormat(shape = JsonFormat.Shape.STRING, pattern = "yyyymmdd'T'HHmmss")
-		End       *string       `json:"end"`       // Start time in ISO 8601 format YYYYMMDD'T'HHmmss 	@JsonFormat(shape = JsonFormat.Shape.STRING, pattern = "yyyymmdd'T'HHmmss")
-		Frequency *string       `json:"frequency"` // how frequently should the event occur
-		Cron      *string       `json:"cron"`      // cron styled regular expression indicating how often the action under schedule should occur.  Use either runOnce, frequency or cron and not all.
-		RunOnce   bool          `json:"runOnce"`   // boolean indicating that this schedules runs one time - at the time indicated by the start
+		Id               bson.ObjectId `json:"id"`
+		Name             *string       `json:"name"`      // non-database identifier for a shcedule (*must be quitue)
+		Start            *string       `json:"start"`     // Start time in ISO 8601 format YYYYMMDD'T'HHmmss 	@JsonFormat(shape = JsonFormat.Shape.STRING, pattern = "yyyymmdd'T'HHmmss")
+		End              *string       `json:"end"`       // Start time in ISO 8601 format YYYYMMDD'T'HHmmss 	@JsonFormat(shape = JsonFormat.Shape.STRING, pattern = "yyyymmdd'T'HHmmss")
+		Frequency        *string       `json:"frequency"` // how frequently should the event occur
+		Cron             *string       `json:"cron"`      // cron styled regular expression indicating how often the action under schedule should occur.  Use either runOnce, frequency or cron and not all.
+		RunOnce          bool          `json:"runOnce"`   // boolean indicating that this schedules runs one time - at the time indicated by the start
+		Timezone         *string       `json:"timezone,omitempty"`
+		JitterMs         int           `json:"jitterMs,omitempty"`
+		MissedFirePolicy *string       `json:"missedFirePolicy,omitempty"`
+		AlignToInterval  bool          `json:"alignToInterval,omitempty"`
+		Tags             []string      `json:"tags,omitempty"`
+		MaxExecutionMs   int           `json:"maxExecutionMs,omitempty"`
+		MaxIterations    int           `json:"maxIterations,omitempty"`
+		IntervalPolicy   *string       `json:"intervalPolicy,omitempty"`
+		IntervalFactor   float64       `json:"intervalFactor,omitempty"`
+		IntervalLimit    *string       `json:"intervalLimit,omitempty"`
+		ResetInterval    bool          `json:"resetInterval,omitempty"`
+		WindowDays       *string       `json:"windowDays,omitempty"`
+		WindowStart      *string       `json:"windowStart,omitempty"`
+		WindowEnd        *string       `json:"windowEnd,omitempty"`
+		Originator       *string       `json:"originator,omitempty"`
 	}{
-		Id:         s.Id,
-		BaseObject: s.BaseObject,
-		RunOnce:    s.RunOnce,
+		Id:              s.Id,
+		BaseObject:      s.BaseObject,
+		RunOnce:         s.RunOnce,
+		JitterMs:        s.JitterMs,
+		AlignToInterval: s.AlignToInterval,
+		Tags:            s.Tags,
+		MaxExecutionMs:  s.MaxExecutionMs,
+		MaxIterations:   s.MaxIterations,
+		IntervalFactor:  s.IntervalFactor,
+		ResetInterval:   s.ResetInterval,
 	}
 
 	// Empty strings are null
@@ -64,6 +101,30 @@ func (s Schedule) MarshalJSON() ([]byte, error) {
 	if s.Cron != "" {
 		test.Cron = &s.Cron
 	}
+	if s.Timezone != "" {
+		test.Timezone = &s.Timezone
+	}
+	if s.MissedFirePolicy != "" {
+		test.MissedFirePolicy = &s.MissedFirePolicy
+	}
+	if s.IntervalPolicy != "" {
+		test.IntervalPolicy = &s.IntervalPolicy
+	}
+	if s.IntervalLimit != "" {
+		test.IntervalLimit = &s.IntervalLimit
+	}
+	if s.WindowDays != "" {
+		test.WindowDays = &s.WindowDays
+	}
+	if s.WindowStart != "" {
+		test.WindowStart = &s.WindowStart
+	}
+	if s.WindowEnd != "" {
+		test.WindowEnd = &s.WindowEnd
+	}
+	if s.Originator != "" {
+		test.Originator = &s.Originator
+	}
 
 	return json.Marshal(test)
 }
@@ -21,29 +21,59 @@ import (
 )
 
 type ScheduleEvent struct {
-	BaseObject  `bson:",inline"`
-	Id          bson.ObjectId `bson:"_id,omitempty" json:"id"`
-	Name        string        `bson:"name" json:"name"`               // non-database unique identifier for a schedule event
-	Schedule    string        `bson:"schedule" json:"schedule"`       // Name to associated owning schedule
-	Addressable Addressable   `bson:"addressable" json:"addressable"` // address {MQTT topic, HTTP address, serial bus, etc.} for the action (can be empty)
-	Parameters  string        `bson:"parameters" json:"parameters"`   // json body for parameters
-	Service     string        `bson:"service" json:"service"`         // json body for parameters
+	BaseObject     `bson:",inline"`
+	Id             bson.ObjectId     `bson:"_id,omitempty" json:"id"`
+	Name           string            `bson:"name" json:"name"`                     // non-database unique identifier for a schedule event
+	Schedule       string            `bson:"schedule" json:"schedule"`             // Name to associated owning schedule
+	Addressable    Addressable       `bson:"addressable" json:"addressable"`       // address {MQTT topic, HTTP address, serial bus, etc.} for the action (can be empty)
+	Parameters     string            `bson:"parameters" json:"parameters"`         // json body for parameters
+	Service        string            `bson:"service" json:"service"`               // json body for parameters
+	Order          int               `bson:"order" json:"order"`                   // position of the event within its schedule, 0 runs after the ordered events
+	ExpectResponse string            `bson:"expectResponse" json:"expectResponse"` // regular expression the response body must match for the event to succeed, empty accepts any body
+	QueryParams    map[string]string `bson:"queryParams" json:"queryParams"`       // parameters appended to the query string of the request
+	Headers        map[string]string `bson:"headers" json:"headers"`               // headers of the request, a Content-Type here takes precedence over the configured default
+	CompressBody   bool              `bson:"compressBody" json:"compressBody"`     // gzip the body of the request
+	BodySource     string            `bson:"bodySource" json:"bodySource"`         // file path or http(s) url the body is read from at execution, replaces the parameters
+	NoRedirects    bool              `bson:"noRedirects" json:"noRedirects"`       // return the redirect responses instead of following them
+	MaxRedirects   int               `bson:"maxRedirects" json:"maxRedirects"`     // requests sent along the redirects before the request fails, 0 allows 10
+	SuccessStatus  string            `bson:"successStatus" json:"successStatus"`   // status codes and ranges counted as a success, e.g. 200-299,304, empty uses the configured ones
+	Originator     string            `bson:"originator" json:"originator"`         // id of the scheduler instance which added the schedule event to core-metadata
+	OnSuccessURL   string            `bson:"onSuccessUrl" json:"onSuccessUrl"`     // http(s) url the outcome of a successful execution is posted to
+	OnFailureURL   string            `bson:"onFailureUrl" json:"onFailureUrl"`     // http(s) url the outcome of a failed execution is posted to
 }
 
 // Custom marshaling to make empty strings null
 func (se ScheduleEvent) MarshalJSON() ([]byte, error) {
 	test := struct {
 		BaseObject
-		Id          bson.ObjectId `json:"id"`
-		Name        *string       `json:"name"`        // non-database unique identifier for a schedule event
-		Schedule    *string       `json:"schedule"`    // Name to associated owning schedule
-		Addressable Addressable   `json:"addressable"` // address {MQTT topic, HTTP address, serial bus, etc.} for the action (can be empty)
-		Parameters  *string       `json:"parameters"`  // json body for parameters
-		Service     *string       `json:"service"`     // json body for parameters
+		Id             bson.ObjectId     `json:"id"`
+		Name           *string           `json:"name"`        // non-database unique identifier for a schedule event
+		Schedule       *string           `json:"schedule"`    // Name to associated owning schedule
+		Addressable    Addressable       `json:"addressable"` // address {MQTT topic, HTTP address, serial bus, etc.} for the action (can be empty)
+		Parameters     *string           `json:"parameters"`  // json body for parameters
+		Service        *string           `json:"service"`     // json body for parameters
+		Order          int               `json:"order,omitempty"`
+		ExpectResponse *string           `json:"expectResponse,omitempty"`
+		QueryParams    map[string]string `json:"queryParams,omitempty"`
+		Headers        map[string]string `json:"headers,omitempty"`
+		CompressBody   bool              `json:"compressBody,omitempty"`
+		BodySource     *string           `json:"bodySource,omitempty"`
+		NoRedirects    bool              `json:"noRedirects,omitempty"`
+		MaxRedirects   int               `json:"maxRedirects,omitempty"`
+		SuccessStatus  *string           `json:"successStatus,omitempty"`
+		Originator     *string           `json:"originator,omitempty"`
+		OnSuccessURL   *string           `json:"onSuccessUrl,omitempty"`
+		OnFailureURL   *string           `json:"onFailureUrl,omitempty"`
 	}{
-		Id:          se.Id,
-		BaseObject:  se.BaseObject,
-		Addressable: se.Addressable,
+		Id:           se.Id,
+		BaseObject:   se.BaseObject,
+		Addressable:  se.Addressable,
+		Order:        se.Order,
+		QueryParams:  se.QueryParams,
+		Headers:      se.Headers,
+		CompressBody: se.CompressBody,
+		NoRedirects:  se.NoRedirects,
+		MaxRedirects: se.MaxRedirects,
 	}
 
 	// Empty strings are null
@@ -59,6 +89,24 @@ func (se ScheduleEvent) MarshalJSON() ([]byte, error) {
 	if se.Service != "" {
 		test.Service = &se.Service
 	}
+	if se.ExpectResponse != "" {
+		test.ExpectResponse = &se.ExpectResponse
+	}
+	if se.BodySource != "" {
+		test.BodySource = &se.BodySource
+	}
+	if se.SuccessStatus != "" {
+		test.SuccessStatus = &se.SuccessStatus
+	}
+	if se.Originator != "" {
+		test.Originator = &se.Originator
+	}
+	if se.OnSuccessURL != "" {
+		test.OnSuccessURL = &se.OnSuccessURL
+	}
+	if se.OnFailureURL != "" {
+		test.OnFailureURL = &se.OnFailureURL
+	}
 
 	return json.Marshal(test)
 }